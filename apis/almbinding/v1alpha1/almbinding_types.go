@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ALMBindingParameters are the configurable fields of an ALMBinding.
+type ALMBindingParameters struct {
+	// ProjectKey is the key of the bound project.
+	ProjectKey string `json:"projectKey"`
+
+	// ALM the project is bound to.
+	// +kubebuilder:validation:Enum=github;gitlab;bitbucket;bitbucketcloud;azure
+	ALM string `json:"alm"`
+
+	// ALMSetting is the key of the ALM setting the project is bound with.
+	ALMSetting string `json:"almSetting"`
+
+	// Repository is the repository name, or its id on GitLab.
+	Repository string `json:"repository"`
+
+	// Slug is the repository slug on Bitbucket Server, or the project name on
+	// Azure DevOps. Other ALMs ignore it.
+	// +optional
+	Slug string `json:"slug,omitempty"`
+
+	// Monorepo is true when the repository holds several projects.
+	// +optional
+	Monorepo bool `json:"monorepo,omitempty"`
+}
+
+// ALMBindingObservation are the observable fields of an ALMBinding.
+type ALMBindingObservation struct {
+	// URL of the ALM instance.
+	URL string `json:"url,omitempty"`
+}
+
+// An ALMBindingSpec defines the desired state of an ALMBinding.
+type ALMBindingSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ALMBindingParameters `json:"forProvider"`
+}
+
+// An ALMBindingStatus represents the observed state of an ALMBinding.
+type ALMBindingStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ALMBindingObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An ALMBinding binds a SonarQube project to a repository of an ALM (DevOps
+// platform).
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type ALMBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ALMBindingSpec   `json:"spec"`
+	Status ALMBindingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ALMBindingList contains a list of ALMBinding
+type ALMBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ALMBinding `json:"items"`
+}
+
+// ALMBinding type metadata.
+var (
+	ALMBindingKind             = reflect.TypeOf(ALMBinding{}).Name()
+	ALMBindingGroupKind        = schema.GroupKind{Group: Group, Kind: ALMBindingKind}.String()
+	ALMBindingKindAPIVersion   = ALMBindingKind + "." + SchemeGroupVersion.String()
+	ALMBindingGroupVersionKind = SchemeGroupVersion.WithKind(ALMBindingKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ALMBinding{}, &ALMBindingList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ALMSettingParameters are the configurable fields of an ALMSetting.
+type ALMSettingParameters struct {
+	// Key of this ALM setting, referred to by the ALM bindings of projects.
+	Key string `json:"key"`
+
+	// URL of the GitHub API, for example https://api.github.com or
+	// https://github.example.org/api/v3.
+	// +kubebuilder:validation:Pattern=`^https?://.+`
+	URL string `json:"url"`
+
+	// AppID is the id of the GitHub App.
+	AppID string `json:"appId"`
+
+	// ClientID is the client id of the GitHub App.
+	ClientID string `json:"clientId"`
+
+	// ClientSecretSecretRef references the secret key holding the client
+	// secret of the GitHub App.
+	ClientSecretSecretRef xpv1.SecretKeySelector `json:"clientSecretSecretRef"`
+
+	// PrivateKeySecretRef references the secret key holding the PEM encoded
+	// private key of the GitHub App.
+	PrivateKeySecretRef xpv1.SecretKeySelector `json:"privateKeySecretRef"`
+
+	// WebhookSecretSecretRef references the secret key holding the secret of
+	// the webhook of the GitHub App.
+	// +optional
+	WebhookSecretSecretRef *xpv1.SecretKeySelector `json:"webhookSecretSecretRef,omitempty"`
+}
+
+// ALMSettingObservation are the observable fields of an ALMSetting.
+type ALMSettingObservation struct {
+	// ALM configured by this setting, e.g. github.
+	ALM string `json:"alm,omitempty"`
+}
+
+// An ALMSettingSpec defines the desired state of an ALMSetting.
+type ALMSettingSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ALMSettingParameters `json:"forProvider"`
+}
+
+// An ALMSettingStatus represents the observed state of an ALMSetting.
+type ALMSettingStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ALMSettingObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An ALMSetting configures a GitHub App as an ALM (DevOps platform) that
+// SonarQube projects can be bound to.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type ALMSetting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ALMSettingSpec   `json:"spec"`
+	Status ALMSettingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ALMSettingList contains a list of ALMSetting
+type ALMSettingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ALMSetting `json:"items"`
+}
+
+// ALMSetting type metadata.
+var (
+	ALMSettingKind             = reflect.TypeOf(ALMSetting{}).Name()
+	ALMSettingGroupKind        = schema.GroupKind{Group: Group, Kind: ALMSettingKind}.String()
+	ALMSettingKindAPIVersion   = ALMSettingKind + "." + SchemeGroupVersion.String()
+	ALMSettingGroupVersionKind = SchemeGroupVersion.WithKind(ALMSettingKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&ALMSetting{}, &ALMSettingList{})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A GroupPermission grants permissions to a group.
+type GroupPermission struct {
+	// Group is the name of the group.
+	Group string `json:"group"`
+
+	// Permissions granted to the group, e.g. admin, codeviewer, issueadmin,
+	// securityhotspotadmin, scan or user.
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionTemplateParameters are the configurable fields of a
+// PermissionTemplate.
+type PermissionTemplateParameters struct {
+	// Organization the permission template belongs to.
+	// Required on SonarCloud, and left empty on SonarQube, which has no
+	// organizations.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Name of this permission template.
+	Name string `json:"name"`
+
+	// Description of this permission template.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// ProjectKeyPattern is a regular expression matching the keys of the
+	// projects this permission template applies to by default.
+	// +optional
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
+
+	// GroupPermissions granted by this permission template. Permissions of
+	// groups that are not listed are revoked. Grants are left unmanaged when
+	// unset.
+	// +optional
+	GroupPermissions []GroupPermission `json:"groupPermissions,omitempty"`
+}
+
+// PermissionTemplateObservation are the observable fields of a
+// PermissionTemplate.
+type PermissionTemplateObservation struct {
+	// ID of this permission template.
+	ID string `json:"id,omitempty"`
+}
+
+// A PermissionTemplateSpec defines the desired state of a PermissionTemplate.
+type PermissionTemplateSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PermissionTemplateParameters `json:"forProvider"`
+}
+
+// A PermissionTemplateStatus represents the observed state of a PermissionTemplate.
+type PermissionTemplateStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PermissionTemplateObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PermissionTemplate grants permissions to the projects it is applied to.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type PermissionTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PermissionTemplateSpec   `json:"spec"`
+	Status PermissionTemplateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PermissionTemplateList contains a list of PermissionTemplate
+type PermissionTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PermissionTemplate `json:"items"`
+}
+
+// PermissionTemplate type metadata.
+var (
+	PermissionTemplateKind             = reflect.TypeOf(PermissionTemplate{}).Name()
+	PermissionTemplateGroupKind        = schema.GroupKind{Group: Group, Kind: PermissionTemplateKind}.String()
+	PermissionTemplateKindAPIVersion   = PermissionTemplateKind + "." + SchemeGroupVersion.String()
+	PermissionTemplateGroupVersionKind = SchemeGroupVersion.WithKind(PermissionTemplateKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&PermissionTemplate{}, &PermissionTemplateList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// PortfolioParameters are the configurable fields of a Portfolio.
+type PortfolioParameters struct {
+	// Key of this portfolio.
+	Key string `json:"key"`
+
+	// Name of this portfolio.
+	Name string `json:"name"`
+
+	// Description of this portfolio.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Visibility of this portfolio. It can only be set on creation.
+	// +kubebuilder:validation:Enum=public;private
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// Projects of this portfolio, by key. Projects that are not listed are
+	// removed from the portfolio. Projects are left unmanaged when unset.
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+}
+
+// PortfolioObservation are the observable fields of a Portfolio.
+type PortfolioObservation struct {
+	// Visibility of this portfolio.
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// A PortfolioSpec defines the desired state of a Portfolio.
+type PortfolioSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       PortfolioParameters `json:"forProvider"`
+}
+
+// A PortfolioStatus represents the observed state of a Portfolio.
+type PortfolioStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          PortfolioObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Portfolio aggregates projects of a SonarQube Enterprise edition instance.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type Portfolio struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PortfolioSpec   `json:"spec"`
+	Status PortfolioStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PortfolioList contains a list of Portfolio
+type PortfolioList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Portfolio `json:"items"`
+}
+
+// Portfolio type metadata.
+var (
+	PortfolioKind             = reflect.TypeOf(Portfolio{}).Name()
+	PortfolioGroupKind        = schema.GroupKind{Group: Group, Kind: PortfolioKind}.String()
+	PortfolioKindAPIVersion   = PortfolioKind + "." + SchemeGroupVersion.String()
+	PortfolioGroupVersionKind = SchemeGroupVersion.WithKind(PortfolioKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Portfolio{}, &PortfolioList{})
+}
@@ -25,21 +25,231 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// AnnotationKeyDeletionProtectionDays is the key of an annotation holding a
+// number of days. A Project with this annotation is not deleted while its
+// project was analyzed within that many days, so that the analysis history of
+// a project in use is not lost by accident.
+const AnnotationKeyDeletionProtectionDays = "sonar.crossplane.io/deletion-protection-days"
+
+// A NewCodePeriod defines which code of a project is considered new.
+type NewCodePeriod struct {
+	// Type of this new code period.
+	// +kubebuilder:validation:Enum=PREVIOUS_VERSION;NUMBER_OF_DAYS;REFERENCE_BRANCH
+	Type string `json:"type"`
+
+	// Value of this new code period: a number of days for NUMBER_OF_DAYS or
+	// a branch for REFERENCE_BRANCH. PREVIOUS_VERSION takes no value.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// A ProjectLink is a link shown on the dashboard of a project, e.g. to its
+// homepage, CI or issue tracker.
+type ProjectLink struct {
+	// Name of this link. It identifies the link within the project.
+	Name string `json:"name"`
+
+	// URL this link points to.
+	URL string `json:"url"`
+}
+
 // ProjectParameters are the configurable fields of a Project.
 type ProjectParameters struct {
-	// Organization of this project.
-	Organization string `json:"organization"`
+	// Organization of this project. A project cannot move to another
+	// organization, so it cannot be changed once set. Required on
+	// SonarCloud unless the ProviderConfig sets a default organization, and
+	// left empty on SonarQube, which has no organizations.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="organization is immutable"
+	Organization string `json:"organization,omitempty"`
+
+	// Key of this project. The key identifies the project, so it cannot be
+	// changed once set. Defaults to the crossplane.io/external-name
+	// annotation, which imports the existing project with that key. The
+	// annotation is set to the key otherwise. A key is made of letters,
+	// digits, '-', '_', '.' and ':', and has at least one character that is
+	// not a digit.
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=400
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.:-]*[a-zA-Z_.:-][a-zA-Z0-9_.:-]*$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="key is immutable"
+	Key string `json:"key,omitempty"`
 
-	// Key of this project.
-	Key string `json:"key"`
+	// Name of this project. Defaults to the name of this resource and is
+	// late-initialized from the observed project.
+	// +optional
+	Name string `json:"name,omitempty"`
 
-	// Visibility of this project.
+	// Visibility of this project. Late-initialized from the observed project
+	// when unset.
+	// +kubebuilder:validation:Enum=public;private
+	// +optional
 	Visibility string `json:"visibility,omitempty"`
+
+	// Tags of this project, for example team:payments. The order of the tags
+	// is not significant. Tags are left unmanaged when unset.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Links of this project, identified by their name. Links that are not
+	// listed are removed, except those provided by the scanner. Links are
+	// left unmanaged when unset.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Links []ProjectLink `json:"links,omitempty"`
+
+	// QualityGate is the name of the quality gate selected for this project.
+	// The selection is left unmanaged when unset.
+	// +optional
+	QualityGate string `json:"qualityGate,omitempty"`
+
+	// TemplateProject is the key of an existing project whose quality gate
+	// and permission template are applied to this project when it is
+	// created. The permission template of a project is the one whose project
+	// key pattern matches its key. A qualityGate set on this project takes
+	// precedence. Changing it after creation has no effect.
+	// +optional
+	TemplateProject string `json:"templateProject,omitempty"`
+
+	// BadgeTokenRotation renews the badge token of this project whenever its
+	// value changes, e.g. to a timestamp. The token is published to the
+	// connection secret.
+	// +optional
+	BadgeTokenRotation string `json:"badgeTokenRotation,omitempty"`
+
+	// GenerateAnalysisToken generates a project analysis token for this
+	// project, published to the connection secret as analysisToken. The
+	// token is revoked when this project is deleted.
+	// +optional
+	GenerateAnalysisToken bool `json:"generateAnalysisToken,omitempty"`
+
+	// NewCodePeriod of this project. The project inherits the period of its
+	// organization or instance when unset.
+	// +optional
+	NewCodePeriod *NewCodePeriod `json:"newCodePeriod,omitempty"`
+
+	// MainBranch is the name of the main branch of this project. A new
+	// project is created with this main branch, and the main branch of an
+	// existing project is renamed when its name differs. Left unmanaged when
+	// unset, in which case new projects get the default main branch name.
+	// +optional
+	MainBranch string `json:"mainBranch,omitempty"`
+
+	// ProtectedBranches are the branches of this project that are kept when
+	// inactive, rather than deleted automatically. A branch is protected once
+	// it was analyzed. A branch removed from the list is no longer protected,
+	// and the main branch is always kept.
+	// +optional
+	ProtectedBranches []string `json:"protectedBranches,omitempty"`
+
+	// ProfileAssociations maps a language, e.g. java, to the name of the
+	// quality profile this project uses for it. Languages that are not listed
+	// use the default profile. A language removed from this map reverts to
+	// the default profile.
+	// +optional
+	ProfileAssociations map[string]string `json:"profileAssociations,omitempty"`
+
+	// Exclusions are the patterns of the files left out of the analysis of
+	// this project, e.g. **/vendor/**, set as sonar.exclusions. The setting
+	// is reset when the list is emptied, and left unmanaged when unset.
+	// +optional
+	Exclusions []string `json:"exclusions,omitempty"`
+
+	// CoverageExclusions are the patterns of the files left out of the code
+	// coverage of this project, set as sonar.coverage.exclusions. The setting
+	// is reset when the list is emptied, and left unmanaged when unset.
+	// +optional
+	CoverageExclusions []string `json:"coverageExclusions,omitempty"`
+
+	// Measures lists the keys of the metrics, e.g. coverage, bugs or
+	// code_smells, whose current values are reported in the status.
+	// +optional
+	Measures []string `json:"measures,omitempty"`
+
+	// ReportIssueCounts reports the number of open blocker and critical
+	// issues, and of security hotspots to review, in the status.
+	// +optional
+	ReportIssueCounts bool `json:"reportIssueCounts,omitempty"`
+
+	// ReportAnalyses reports the latest analysis of this project and the
+	// latest change of its quality gate status, read from its analysis
+	// history, in the status.
+	// +optional
+	ReportAnalyses bool `json:"reportAnalyses,omitempty"`
 }
 
 // ProjectObservation are the observable fields of a Project.
 type ProjectObservation struct {
-	ObservableField string `json:"observableField,omitempty"`
+	// Organization this project was created in. A project cannot be moved to
+	// another organization.
+	Organization string `json:"organization,omitempty"`
+
+	// LastAnalysisDate is when this project was last analyzed.
+	LastAnalysisDate *metav1.Time `json:"lastAnalysisDate,omitempty"`
+
+	// LastQualityGateStatus is the quality gate status, OK or ERROR, this
+	// project changed to most recently. Only reported when reportAnalyses is
+	// set, and only when the change is among the recent analyses.
+	LastQualityGateStatus string `json:"lastQualityGateStatus,omitempty"`
+
+	// LastQualityGateChangeDate is when the analysis that changed the quality
+	// gate status to lastQualityGateStatus ran. Only reported when
+	// reportAnalyses is set.
+	LastQualityGateChangeDate *metav1.Time `json:"lastQualityGateChangeDate,omitempty"`
+
+	// Qualifier of this project, e.g. TRK.
+	Qualifier string `json:"qualifier,omitempty"`
+
+	// Revision analyzed last, e.g. a commit SHA.
+	Revision string `json:"revision,omitempty"`
+
+	// ProjectURL is the URL of the dashboard of this project.
+	ProjectURL string `json:"projectUrl,omitempty"`
+
+	// MainBranch is the name of the main branch of this project.
+	MainBranch string `json:"mainBranch,omitempty"`
+
+	// ProtectedBranches are the branches last protected from automatic
+	// deletion. Branches that are no longer desired are unprotected.
+	ProtectedBranches []string `json:"protectedBranches,omitempty"`
+
+	// BadgeTokenRotation is the value of the badgeTokenRotation parameter
+	// the badge token was last renewed for.
+	BadgeTokenRotation string `json:"badgeTokenRotation,omitempty"`
+
+	// AnalysisTokenName is the name of the project analysis token generated
+	// for this project.
+	AnalysisTokenName string `json:"analysisTokenName,omitempty"`
+
+	// ProfileAssociations are the quality profiles last associated with this
+	// project, keyed by language. Associations that are no longer desired are
+	// removed.
+	ProfileAssociations map[string]string `json:"profileAssociations,omitempty"`
+
+	// Exclusions are the patterns last set as sonar.exclusions.
+	Exclusions []string `json:"exclusions,omitempty"`
+
+	// CoverageExclusions are the patterns last set as
+	// sonar.coverage.exclusions.
+	CoverageExclusions []string `json:"coverageExclusions,omitempty"`
+
+	// Measures are the current values of the metrics listed in the measures
+	// parameter, keyed by metric. Metrics without a value yet are omitted.
+	Measures map[string]string `json:"measures,omitempty"`
+
+	// OpenBlockerCount is the number of open issues of blocker severity.
+	// Only reported when reportIssueCounts is set.
+	OpenBlockerCount *int `json:"openBlockerCount,omitempty"`
+
+	// OpenCriticalCount is the number of open issues of critical severity.
+	// Only reported when reportIssueCounts is set.
+	OpenCriticalCount *int `json:"openCriticalCount,omitempty"`
+
+	// OpenHotspotCount is the number of security hotspots to review. Only
+	// reported when reportIssueCounts is set.
+	OpenHotspotCount *int `json:"openHotspotCount,omitempty"`
 }
 
 // A ProjectSpec defines the desired state of a Project.
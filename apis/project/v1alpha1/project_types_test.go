@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+// TestKeyImmutable checks the generated CRD, which is what the API server
+// validates updates against, rejects a change of the project key.
+func TestKeyImmutable(t *testing.T) {
+	data, err := os.ReadFile("../../../package/crds/project.sonar.crossplane.io_projects.yaml")
+	if err != nil {
+		t.Fatalf("cannot read CRD: %v", err)
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		t.Fatalf("cannot parse CRD: %v", err)
+	}
+
+	for _, v := range crd.Spec.Versions {
+		if v.Name != Version {
+			continue
+		}
+		key := v.Schema.OpenAPIV3Schema.Properties["spec"].Properties["forProvider"].Properties["key"]
+		want := apiextensionsv1.ValidationRules{{Rule: "self == oldSelf", Message: "key is immutable"}}
+		if diff := cmp.Diff(want, key.XValidations); diff != "" {
+			t.Errorf("spec.forProvider.key: -want validation rules, +got validation rules:\n%s\n", diff)
+		}
+		return
+	}
+	t.Fatalf("CRD has no version %s", Version)
+}
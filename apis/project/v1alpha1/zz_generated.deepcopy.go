@@ -25,6 +25,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewCodePeriod) DeepCopyInto(out *NewCodePeriod) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewCodePeriod.
+func (in *NewCodePeriod) DeepCopy() *NewCodePeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(NewCodePeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Project) DeepCopyInto(out *Project) {
 	*out = *in
@@ -52,6 +67,21 @@ func (in *Project) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectLink) DeepCopyInto(out *ProjectLink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectLink.
+func (in *ProjectLink) DeepCopy() *ProjectLink {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectList) DeepCopyInto(out *ProjectList) {
 	*out = *in
@@ -87,6 +117,58 @@ func (in *ProjectList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectObservation) DeepCopyInto(out *ProjectObservation) {
 	*out = *in
+	if in.LastAnalysisDate != nil {
+		in, out := &in.LastAnalysisDate, &out.LastAnalysisDate
+		*out = (*in).DeepCopy()
+	}
+	if in.LastQualityGateChangeDate != nil {
+		in, out := &in.LastQualityGateChangeDate, &out.LastQualityGateChangeDate
+		*out = (*in).DeepCopy()
+	}
+	if in.ProtectedBranches != nil {
+		in, out := &in.ProtectedBranches, &out.ProtectedBranches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProfileAssociations != nil {
+		in, out := &in.ProfileAssociations, &out.ProfileAssociations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CoverageExclusions != nil {
+		in, out := &in.CoverageExclusions, &out.CoverageExclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Measures != nil {
+		in, out := &in.Measures, &out.Measures
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OpenBlockerCount != nil {
+		in, out := &in.OpenBlockerCount, &out.OpenBlockerCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.OpenCriticalCount != nil {
+		in, out := &in.OpenCriticalCount, &out.OpenCriticalCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.OpenHotspotCount != nil {
+		in, out := &in.OpenHotspotCount, &out.OpenHotspotCount
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectObservation.
@@ -102,6 +184,48 @@ func (in *ProjectObservation) DeepCopy() *ProjectObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
 	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]ProjectLink, len(*in))
+		copy(*out, *in)
+	}
+	if in.NewCodePeriod != nil {
+		in, out := &in.NewCodePeriod, &out.NewCodePeriod
+		*out = new(NewCodePeriod)
+		**out = **in
+	}
+	if in.ProtectedBranches != nil {
+		in, out := &in.ProtectedBranches, &out.ProtectedBranches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProfileAssociations != nil {
+		in, out := &in.ProfileAssociations, &out.ProfileAssociations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CoverageExclusions != nil {
+		in, out := &in.CoverageExclusions, &out.CoverageExclusions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Measures != nil {
+		in, out := &in.Measures, &out.Measures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectParameters.
@@ -118,7 +242,7 @@ func (in *ProjectParameters) DeepCopy() *ProjectParameters {
 func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
@@ -135,7 +259,7 @@ func (in *ProjectSpec) DeepCopy() *ProjectSpec {
 func (in *ProjectStatus) DeepCopyInto(out *ProjectStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectStatus.
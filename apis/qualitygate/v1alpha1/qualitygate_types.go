@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// QualityGateCondition is a condition a project must meet to pass a
+// QualityGate.
+type QualityGateCondition struct {
+	// Metric key of this condition, for example new_coverage.
+	Metric string `json:"metric"`
+
+	// Op is the operator used to compare the metric to the error threshold.
+	// +kubebuilder:validation:Enum=LT;GT
+	Op string `json:"op"`
+
+	// Error threshold of this condition.
+	Error string `json:"error"`
+}
+
+// QualityGateParameters are the configurable fields of a QualityGate.
+type QualityGateParameters struct {
+	// Organization of this quality gate.
+	// Required on SonarCloud, and left empty on SonarQube, which has no
+	// organizations.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Name of this quality gate.
+	Name string `json:"name"`
+
+	// Conditions of this quality gate. A quality gate has at most one
+	// condition per metric.
+	// +optional
+	Conditions []QualityGateCondition `json:"conditions,omitempty"`
+
+	// Default makes this quality gate the default of its organization, which
+	// applies to every project that does not select a quality gate. Only one
+	// quality gate can be the default. When false the default is not managed.
+	// +optional
+	Default bool `json:"default,omitempty"`
+}
+
+// QualityGateConditionObservation is a condition as observed on a QualityGate.
+type QualityGateConditionObservation struct {
+	// ID of this condition.
+	ID string `json:"id,omitempty"`
+
+	QualityGateCondition `json:",inline"`
+}
+
+// QualityGateObservation are the observable fields of a QualityGate.
+type QualityGateObservation struct {
+	// ID of this quality gate. Unlike its name, the ID never changes, so it
+	// identifies the quality gate when it is renamed.
+	ID string `json:"id,omitempty"`
+
+	// Name of this quality gate, as observed.
+	Name string `json:"name,omitempty"`
+
+	// Conditions of this quality gate.
+	Conditions []QualityGateConditionObservation `json:"conditions,omitempty"`
+
+	// IsDefault is true when this quality gate is the default of its
+	// organization.
+	IsDefault bool `json:"isDefault,omitempty"`
+}
+
+// A QualityGateSpec defines the desired state of a QualityGate.
+type QualityGateSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       QualityGateParameters `json:"forProvider"`
+}
+
+// A QualityGateStatus represents the observed state of a QualityGate.
+type QualityGateStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          QualityGateObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A QualityGate is a set of conditions a project's analysis must meet.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type QualityGate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QualityGateSpec   `json:"spec"`
+	Status QualityGateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QualityGateList contains a list of QualityGate
+type QualityGateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QualityGate `json:"items"`
+}
+
+// QualityGate type metadata.
+var (
+	QualityGateKind             = reflect.TypeOf(QualityGate{}).Name()
+	QualityGateGroupKind        = schema.GroupKind{Group: Group, Kind: QualityGateKind}.String()
+	QualityGateKindAPIVersion   = QualityGateKind + "." + SchemeGroupVersion.String()
+	QualityGateGroupVersionKind = SchemeGroupVersion.WithKind(QualityGateKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&QualityGate{}, &QualityGateList{})
+}
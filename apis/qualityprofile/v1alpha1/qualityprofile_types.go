@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// QualityProfileParameters are the configurable fields of a QualityProfile.
+type QualityProfileParameters struct {
+	// Organization of this quality profile.
+	// Required on SonarCloud, and left empty on SonarQube, which has no
+	// organizations.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Language of this quality profile, for example java. A quality profile
+	// is identified by its language and name.
+	Language string `json:"language"`
+
+	// Name of this quality profile.
+	Name string `json:"name"`
+
+	// Projects associated with this quality profile, by project key. The
+	// associations are left unmanaged when unset.
+	// +optional
+	Projects []string `json:"projects,omitempty"`
+}
+
+// QualityProfileObservation are the observable fields of a QualityProfile.
+type QualityProfileObservation struct {
+	// Key of this quality profile.
+	Key string `json:"key,omitempty"`
+}
+
+// A QualityProfileSpec defines the desired state of a QualityProfile.
+type QualityProfileSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       QualityProfileParameters `json:"forProvider"`
+}
+
+// A QualityProfileStatus represents the observed state of a QualityProfile.
+type QualityProfileStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          QualityProfileObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A QualityProfile is a set of rules used to analyze the projects of a language.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type QualityProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QualityProfileSpec   `json:"spec"`
+	Status QualityProfileStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QualityProfileList contains a list of QualityProfile
+type QualityProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QualityProfile `json:"items"`
+}
+
+// QualityProfile type metadata.
+var (
+	QualityProfileKind             = reflect.TypeOf(QualityProfile{}).Name()
+	QualityProfileGroupKind        = schema.GroupKind{Group: Group, Kind: QualityProfileKind}.String()
+	QualityProfileKindAPIVersion   = QualityProfileKind + "." + SchemeGroupVersion.String()
+	QualityProfileGroupVersionKind = SchemeGroupVersion.WithKind(QualityProfileKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&QualityProfile{}, &QualityProfileList{})
+}
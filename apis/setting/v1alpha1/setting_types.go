@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// SettingParameters are the configurable fields of a Setting. Exactly one of
+// value, values and fieldValues should be set, depending on the type of the
+// setting.
+type SettingParameters struct {
+	// Key of this setting, e.g. sonar.exclusions.
+	Key string `json:"key"`
+
+	// Project key this setting is set on. The setting is global when no
+	// project is set.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Value of a single value setting.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Values of a multi-value setting, e.g. a list of exclusions.
+	// +optional
+	Values []string `json:"values,omitempty"`
+
+	// FieldValues of a property set setting, one map of fields to values per
+	// entry.
+	// +optional
+	FieldValues []map[string]string `json:"fieldValues,omitempty"`
+}
+
+// SettingObservation are the observable fields of a Setting.
+type SettingObservation struct {
+	// Inherited is true when the setting is not set on its scope, and takes
+	// its default or inherited value.
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// A SettingSpec defines the desired state of a Setting.
+type SettingSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SettingParameters `json:"forProvider"`
+}
+
+// A SettingStatus represents the observed state of a Setting.
+type SettingStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SettingObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Setting sets a global or project setting, e.g. the exclusions of an analysis.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type Setting struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SettingSpec   `json:"spec"`
+	Status SettingStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SettingList contains a list of Setting
+type SettingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Setting `json:"items"`
+}
+
+// Setting type metadata.
+var (
+	SettingKind             = reflect.TypeOf(Setting{}).Name()
+	SettingGroupKind        = schema.GroupKind{Group: Group, Kind: SettingKind}.String()
+	SettingKindAPIVersion   = SettingKind + "." + SchemeGroupVersion.String()
+	SettingGroupVersionKind = SchemeGroupVersion.WithKind(SettingKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Setting{}, &SettingList{})
+}
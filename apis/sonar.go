@@ -20,15 +20,39 @@ package apis
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 
+	almbindingv1alpha1 "github.com/crossplane/provider-sonar/apis/almbinding/v1alpha1"
+	almsettingv1alpha1 "github.com/crossplane/provider-sonar/apis/almsetting/v1alpha1"
+	applicationv1alpha1 "github.com/crossplane/provider-sonar/apis/application/v1alpha1"
+	organizationv1alpha1 "github.com/crossplane/provider-sonar/apis/organization/v1alpha1"
+	permissiontemplatev1alpha1 "github.com/crossplane/provider-sonar/apis/permissiontemplate/v1alpha1"
+	portfoliov1alpha1 "github.com/crossplane/provider-sonar/apis/portfolio/v1alpha1"
 	projectv1alpha1 "github.com/crossplane/provider-sonar/apis/project/v1alpha1"
+	qualitygatev1alpha1 "github.com/crossplane/provider-sonar/apis/qualitygate/v1alpha1"
+	qualityprofilev1alpha1 "github.com/crossplane/provider-sonar/apis/qualityprofile/v1alpha1"
+	settingv1alpha1 "github.com/crossplane/provider-sonar/apis/setting/v1alpha1"
+	userv1alpha1 "github.com/crossplane/provider-sonar/apis/user/v1alpha1"
+	usergroupv1alpha1 "github.com/crossplane/provider-sonar/apis/usergroup/v1alpha1"
 	sonarv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	webhookv1alpha1 "github.com/crossplane/provider-sonar/apis/webhook/v1alpha1"
 )
 
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
 	AddToSchemes = append(AddToSchemes,
 		sonarv1alpha1.SchemeBuilder.AddToScheme,
+		almbindingv1alpha1.SchemeBuilder.AddToScheme,
+		almsettingv1alpha1.SchemeBuilder.AddToScheme,
+		applicationv1alpha1.SchemeBuilder.AddToScheme,
+		organizationv1alpha1.SchemeBuilder.AddToScheme,
+		permissiontemplatev1alpha1.SchemeBuilder.AddToScheme,
+		portfoliov1alpha1.SchemeBuilder.AddToScheme,
 		projectv1alpha1.SchemeBuilder.AddToScheme,
+		qualitygatev1alpha1.SchemeBuilder.AddToScheme,
+		qualityprofilev1alpha1.SchemeBuilder.AddToScheme,
+		settingv1alpha1.SchemeBuilder.AddToScheme,
+		usergroupv1alpha1.SchemeBuilder.AddToScheme,
+		userv1alpha1.SchemeBuilder.AddToScheme,
+		webhookv1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
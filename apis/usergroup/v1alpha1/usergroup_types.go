@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// UserGroupParameters are the configurable fields of a UserGroup.
+type UserGroupParameters struct {
+	// Name of this group.
+	Name string `json:"name"`
+
+	// Description of this group.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Members of this group, by login. Members that are not listed are
+	// removed from the group, so an empty list removes every member.
+	// +optional
+	Members []string `json:"members,omitempty"`
+}
+
+// UserGroupObservation are the observable fields of a UserGroup.
+type UserGroupObservation struct {
+	// ID of this group.
+	ID string `json:"id,omitempty"`
+
+	// Members of this group, by login.
+	Members []string `json:"members,omitempty"`
+}
+
+// A UserGroupSpec defines the desired state of a UserGroup.
+type UserGroupSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       UserGroupParameters `json:"forProvider"`
+}
+
+// A UserGroupStatus represents the observed state of a UserGroup.
+type UserGroupStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          UserGroupObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A UserGroup is a group of users of a SonarQube instance.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type UserGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UserGroupSpec   `json:"spec"`
+	Status UserGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UserGroupList contains a list of UserGroup
+type UserGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UserGroup `json:"items"`
+}
+
+// UserGroup type metadata.
+var (
+	UserGroupKind             = reflect.TypeOf(UserGroup{}).Name()
+	UserGroupGroupKind        = schema.GroupKind{Group: Group, Kind: UserGroupKind}.String()
+	UserGroupKindAPIVersion   = UserGroupKind + "." + SchemeGroupVersion.String()
+	UserGroupGroupVersionKind = SchemeGroupVersion.WithKind(UserGroupKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&UserGroup{}, &UserGroupList{})
+}
@@ -29,6 +29,82 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// BaseURL of the SonarCloud or SonarQube instance, for example
+	// https://sonarqube.example.org. Defaults to https://sonarcloud.io.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://.+`
+	BaseURL string `json:"baseUrl,omitempty"`
+
+	// Organization used by the resources of this ProviderConfig that do not
+	// set one. Only SonarCloud has organizations.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// InstanceMode is the kind of instance at BaseURL. SonarQube has no
+	// organizations, so the organizations of resources are never sent to a
+	// sonarqube instance, and Organization resources cannot be managed.
+	// +optional
+	// +kubebuilder:validation:Enum=sonarcloud;sonarqube
+	// +kubebuilder:default=sonarcloud
+	InstanceMode string `json:"instanceMode,omitempty"`
+
+	// CABundleSecretRef references a secret key holding the PEM encoded
+	// certificates of the CA of a SonarQube instance, trusted in addition to
+	// the system roots.
+	// +optional
+	CABundleSecretRef *xpv1.SecretKeySelector `json:"caBundleSecretRef,omitempty"`
+
+	// AuthMode selects how the credentials are sent to the API: as the basic
+	// auth username, or as a bearer token as supported by SonarQube 10.
+	// +optional
+	// +kubebuilder:validation:Enum=basic;bearer
+	// +kubebuilder:default=basic
+	AuthMode string `json:"authMode,omitempty"`
+
+	// TokenType of the credentials. Analysis tokens can only run analyses,
+	// so the requests they cannot send are reported as such rather than as
+	// permission errors. Defaults to a user token.
+	// +optional
+	// +kubebuilder:validation:Enum=USER_TOKEN;GLOBAL_ANALYSIS_TOKEN;PROJECT_ANALYSIS_TOKEN
+	TokenType string `json:"tokenType,omitempty"`
+
+	// ValidateCredentials checks the credentials each time a resource
+	// connects, so that a rejected token is reported as such rather than as a
+	// failure of the first request. Off by default, since it takes an extra
+	// request per reconcile.
+	// +optional
+	ValidateCredentials bool `json:"validateCredentials,omitempty"`
+
+	// RequestsPerSecond limits the rate of the requests sent with the
+	// credentials of this ProviderConfig, so that reconciling many resources
+	// at once stays within the rate limits of the instance. Requests are not
+	// limited when unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int `json:"requestsPerSecond,omitempty"`
+
+	// CountForbiddenAsAuthFailure counts 403 responses, and not only 401
+	// responses, towards suspending the requests made with the credentials of
+	// this ProviderConfig, e.g. when the permissions of a token are revoked
+	// rather than the token itself. Off by default, since a 403 is also
+	// returned for a single resource the credentials lack a permission on.
+	// +optional
+	CountForbiddenAsAuthFailure bool `json:"countForbiddenAsAuthFailure,omitempty"`
+
+	// DisableRecreation stops the projects of this ProviderConfig from being
+	// created again when they are deleted outside of Crossplane, which could
+	// mask an accidental deletion. Such a project is reported as unavailable
+	// instead, until it is restored or its managed resource is deleted.
+	// +optional
+	DisableRecreation bool `json:"disableRecreation,omitempty"`
+
+	// DefaultTags are carried by every project of this ProviderConfig, in
+	// addition to the tags of the project, e.g. to meet a tagging policy of
+	// the organization. They are added to the tags of projects that leave
+	// their tags unmanaged too, keeping the other tags of such projects.
+	// +optional
+	DefaultTags []string `json:"defaultTags,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// WebhookParameters are the configurable fields of a Webhook.
+type WebhookParameters struct {
+	// Organization this webhook belongs to.
+	// Required on SonarCloud, and left empty on SonarQube, which has no
+	// organizations.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// Project key this webhook belongs to. The webhook belongs to the
+	// organization when no project is set.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Name of this webhook.
+	Name string `json:"name"`
+
+	// URL the webhook notifications are sent to.
+	// +kubebuilder:validation:Pattern=`^https?://.+`
+	URL string `json:"url"`
+
+	// SecretSecretRef references the secret key holding the secret used to
+	// sign the webhook notifications.
+	// +optional
+	SecretSecretRef *xpv1.SecretKeySelector `json:"secretSecretRef,omitempty"`
+}
+
+// WebhookObservation are the observable fields of a Webhook.
+type WebhookObservation struct {
+	// Key of this webhook.
+	Key string `json:"key,omitempty"`
+
+	// HasSecret is true when the webhook notifications are signed.
+	HasSecret bool `json:"hasSecret,omitempty"`
+
+	// LastDeliveryAt is when this webhook last sent a notification.
+	LastDeliveryAt *metav1.Time `json:"lastDeliveryAt,omitempty"`
+
+	// LastDeliveryHTTPStatus is the HTTP status returned to the last
+	// notification, if it got a response.
+	LastDeliveryHTTPStatus *int `json:"lastDeliveryHttpStatus,omitempty"`
+
+	// LastDeliverySuccess is true when the last notification was delivered.
+	LastDeliverySuccess *bool `json:"lastDeliverySuccess,omitempty"`
+}
+
+// A WebhookSpec defines the desired state of a Webhook.
+type WebhookSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       WebhookParameters `json:"forProvider"`
+}
+
+// A WebhookStatus represents the observed state of a Webhook.
+type WebhookStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          WebhookObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Webhook notifies an external service when a project analysis completes.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,sonar}
+type Webhook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WebhookSpec   `json:"spec"`
+	Status WebhookStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WebhookList contains a list of Webhook
+type WebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Webhook `json:"items"`
+}
+
+// Webhook type metadata.
+var (
+	WebhookKind             = reflect.TypeOf(Webhook{}).Name()
+	WebhookGroupKind        = schema.GroupKind{Group: Group, Kind: WebhookKind}.String()
+	WebhookKindAPIVersion   = WebhookKind + "." + SchemeGroupVersion.String()
+	WebhookGroupVersionKind = SchemeGroupVersion.WithKind(WebhookKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Webhook{}, &WebhookList{})
+}
@@ -0,0 +1,95 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var ErrAlmBindingNotFound = errors.New("ALM binding not found")
+
+// ALMs a project can be bound to.
+const (
+	AlmGitHub         = "github"
+	AlmGitLab         = "gitlab"
+	AlmBitbucket      = "bitbucket"
+	AlmBitbucketCloud = "bitbucketcloud"
+	AlmAzure          = "azure"
+)
+
+// AlmBinding binds a project to a repository of an ALM (DevOps platform).
+type AlmBinding struct {
+	// Key of the ALM setting the project is bound with.
+	Key string `json:"key"`
+	Alm string `json:"alm"`
+	// Repository is the repository name, or id on GitLab.
+	Repository string `json:"repository"`
+	// Slug is the repository slug on Bitbucket Server, or the project name on
+	// Azure DevOps.
+	Slug     string `json:"slug,omitempty"`
+	Monorepo bool   `json:"monorepo"`
+	// Url of the ALM instance.
+	Url string `json:"url,omitempty"`
+}
+
+type AlmBindingClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new ALM Binding Client
+func NewAlmBindingClient(options SonarApiOptions) AlmBindingClient {
+	return AlmBindingClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Set binds a project, replacing any existing binding
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings
+func (almBindingClient AlmBindingClient) Set(ctx context.Context, project string, binding AlmBinding) error {
+	params := url.Values{}
+	params.Add("almSetting", binding.Key)
+	params.Add("project", project)
+	params.Add("monorepo", strconv.FormatBool(binding.Monorepo))
+
+	switch binding.Alm {
+	case AlmGitHub, AlmGitLab, AlmBitbucketCloud:
+		params.Add("repository", binding.Repository)
+	case AlmBitbucket:
+		params.Add("repository", binding.Repository)
+		params.Add("slug", binding.Slug)
+	case AlmAzure:
+		params.Add("projectName", binding.Slug)
+		params.Add("repositoryName", binding.Repository)
+	default:
+		return fmt.Errorf("unknown ALM %q", binding.Alm)
+	}
+
+	return almBindingClient.sonarApi.do(ctx, "POST", "/api/alm_settings/set_"+binding.Alm+"_binding", params, nil)
+}
+
+// Get the binding of a project
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/get_binding
+func (almBindingClient AlmBindingClient) Get(ctx context.Context, project string) (AlmBinding, error) {
+	params := url.Values{}
+	params.Add("project", project)
+
+	var binding AlmBinding
+	err := almBindingClient.sonarApi.do(ctx, "GET", "/api/alm_settings/get_binding", params, &binding)
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return AlmBinding{}, ErrAlmBindingNotFound
+	}
+	return binding, err
+}
+
+// Delete the binding of a project
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/delete_binding
+func (almBindingClient AlmBindingClient) Delete(ctx context.Context, project string) error {
+	params := url.Values{}
+	params.Add("project", project)
+
+	return almBindingClient.sonarApi.do(ctx, "POST", "/api/alm_settings/delete_binding", params, nil)
+}
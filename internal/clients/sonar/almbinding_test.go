@@ -0,0 +1,97 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAlmBindingSet(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+	}
+
+	cases := map[string]struct {
+		reason  string
+		binding AlmBinding
+		want    want
+	}{
+		"GitHub": {
+			reason:  "A GitHub binding should be set with its repository.",
+			binding: AlmBinding{Key: "github", Alm: AlmGitHub, Repository: "acme/payments", Monorepo: true},
+			want: want{
+				path:   "/api/alm_settings/set_github_binding",
+				params: url.Values{"almSetting": {"github"}, "project": {"key"}, "repository": {"acme/payments"}, "monorepo": {"true"}},
+			},
+		},
+		"Bitbucket": {
+			reason:  "A Bitbucket Server binding should be set with its repository and slug.",
+			binding: AlmBinding{Key: "bitbucket", Alm: AlmBitbucket, Repository: "PAY", Slug: "payments"},
+			want: want{
+				path:   "/api/alm_settings/set_bitbucket_binding",
+				params: url.Values{"almSetting": {"bitbucket"}, "project": {"key"}, "repository": {"PAY"}, "slug": {"payments"}, "monorepo": {"false"}},
+			},
+		},
+		"Azure": {
+			reason:  "An Azure DevOps binding should be set with its project and repository names.",
+			binding: AlmBinding{Key: "azure", Alm: AlmAzure, Repository: "payments", Slug: "Payments"},
+			want: want{
+				path:   "/api/alm_settings/set_azure_binding",
+				params: url.Values{"almSetting": {"azure"}, "project": {"key"}, "projectName": {"Payments"}, "repositoryName": {"payments"}, "monorepo": {"false"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = want{path: r.URL.Path, params: r.Form}
+			}))
+			defer srv.Close()
+
+			c := NewAlmBindingClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if err := c.Set(context.Background(), "key", tc.binding); err != nil {
+				t.Fatalf("\n%s\nc.Set(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.Set(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAlmBindingGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"key":"github","alm":"github","repository":"acme/payments","url":"https://api.github.com","monorepo":false}`))
+	}))
+	defer srv.Close()
+
+	c := NewAlmBindingClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Get(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.Get(...): unexpected error: %v", err)
+	}
+	want := AlmBinding{Key: "github", Alm: AlmGitHub, Repository: "acme/payments", Url: "https://api.github.com"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Get(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestAlmBindingGetNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewAlmBindingClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.Get(context.Background(), "key"); err != ErrAlmBindingNotFound {
+		t.Errorf("c.Get(...): want ErrAlmBindingNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+var ErrAlmSettingNotFound = errors.New("ALM setting not found")
+
+// AlmSetting configures an ALM (DevOps platform) instance that projects can
+// be bound to.
+type AlmSetting struct {
+	Key string `json:"key"`
+	Alm string `json:"alm"`
+	// Url of the ALM instance.
+	Url string `json:"url,omitempty"`
+}
+
+// GitHubAlmSetting configures a GitHub App used to bind projects to GitHub
+// repositories. The secrets are never returned by the API.
+type GitHubAlmSetting struct {
+	Key string `json:"key"`
+	// Url of the GitHub API, e.g. https://api.github.com.
+	Url      string `json:"url"`
+	AppId    string `json:"appId"`
+	ClientId string `json:"clientId"`
+
+	ClientSecret  string `json:"-"`
+	PrivateKey    string `json:"-"`
+	WebhookSecret string `json:"-"`
+}
+
+type AlmSettingClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new ALM Setting Client
+func NewAlmSettingClient(options SonarApiOptions) AlmSettingClient {
+	return AlmSettingClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// List the ALM settings of the instance
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/list
+func (almSettingClient AlmSettingClient) List(ctx context.Context) ([]AlmSetting, error) {
+	var response struct {
+		AlmSettings []AlmSetting `json:"almSettings"`
+	}
+	if err := almSettingClient.sonarApi.do(ctx, "GET", "/api/alm_settings/list", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+	return response.AlmSettings, nil
+}
+
+// GetGitHub returns the GitHub ALM setting with the supplied key. Unlike List,
+// the definitions include the app and client ids.
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/list_definitions
+func (almSettingClient AlmSettingClient) GetGitHub(ctx context.Context, key string) (GitHubAlmSetting, error) {
+	var response struct {
+		GitHub []GitHubAlmSetting `json:"github"`
+	}
+	if err := almSettingClient.sonarApi.do(ctx, "GET", "/api/alm_settings/list_definitions", url.Values{}, &response); err != nil {
+		return GitHubAlmSetting{}, err
+	}
+
+	for _, setting := range response.GitHub {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return GitHubAlmSetting{}, ErrAlmSettingNotFound
+}
+
+// Create new GitHub ALM setting
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/create_github
+func (almSettingClient AlmSettingClient) CreateGitHub(ctx context.Context, setting GitHubAlmSetting) error {
+	params := url.Values{}
+	params.Add("key", setting.Key)
+	params.Add("url", setting.Url)
+	params.Add("appId", setting.AppId)
+	params.Add("clientId", setting.ClientId)
+	params.Add("clientSecret", setting.ClientSecret)
+	params.Add("privateKey", setting.PrivateKey)
+	if setting.WebhookSecret != "" {
+		params.Add("webhookSecret", setting.WebhookSecret)
+	}
+
+	return almSettingClient.sonarApi.do(ctx, "POST", "/api/alm_settings/create_github", params, nil)
+}
+
+// Update a GitHub ALM setting, identified by its key. Secrets left empty are
+// kept.
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/update_github
+func (almSettingClient AlmSettingClient) UpdateGitHub(ctx context.Context, setting GitHubAlmSetting) error {
+	params := url.Values{}
+	params.Add("key", setting.Key)
+	params.Add("url", setting.Url)
+	params.Add("appId", setting.AppId)
+	params.Add("clientId", setting.ClientId)
+	if setting.ClientSecret != "" {
+		params.Add("clientSecret", setting.ClientSecret)
+	}
+	if setting.PrivateKey != "" {
+		params.Add("privateKey", setting.PrivateKey)
+	}
+	if setting.WebhookSecret != "" {
+		params.Add("webhookSecret", setting.WebhookSecret)
+	}
+
+	return almSettingClient.sonarApi.do(ctx, "POST", "/api/alm_settings/update_github", params, nil)
+}
+
+// Delete an ALM setting, which also removes the bindings of its projects
+// https://next.sonarqube.com/sonarqube/web_api/api/alm_settings/delete
+func (almSettingClient AlmSettingClient) Delete(ctx context.Context, key string) error {
+	params := url.Values{}
+	params.Add("key", key)
+
+	return almSettingClient.sonarApi.do(ctx, "POST", "/api/alm_settings/delete", params, nil)
+}
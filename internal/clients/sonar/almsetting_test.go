@@ -0,0 +1,81 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAlmSettingGetGitHub(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"github":[{"key":"github","url":"https://api.github.com","appId":"12345","clientId":"Iv1.abc"}],"gitlab":[{"key":"gitlab","url":"https://gitlab.com/api/v4"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewAlmSettingClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.GetGitHub(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("c.GetGitHub(...): unexpected error: %v", err)
+	}
+	want := GitHubAlmSetting{Key: "github", Url: "https://api.github.com", AppId: "12345", ClientId: "Iv1.abc"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetGitHub(...): -want, +got:\n%s\n", diff)
+	}
+
+	if _, err := c.GetGitHub(context.Background(), "gitlab"); err != ErrAlmSettingNotFound {
+		t.Errorf("c.GetGitHub(...): want ErrAlmSettingNotFound, got %v", err)
+	}
+}
+
+func TestAlmSettingUpdateGitHub(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+	}
+
+	cases := map[string]struct {
+		reason  string
+		setting GitHubAlmSetting
+		want    want
+	}{
+		"WithSecrets": {
+			reason:  "The secrets should be sent when they are set.",
+			setting: GitHubAlmSetting{Key: "github", Url: "https://api.github.com", AppId: "12345", ClientId: "Iv1.abc", ClientSecret: "secret", PrivateKey: "key"},
+			want: want{
+				path:   "/api/alm_settings/update_github",
+				params: url.Values{"key": {"github"}, "url": {"https://api.github.com"}, "appId": {"12345"}, "clientId": {"Iv1.abc"}, "clientSecret": {"secret"}, "privateKey": {"key"}},
+			},
+		},
+		"WithoutSecrets": {
+			reason:  "Empty secrets should not be sent, so that they are kept.",
+			setting: GitHubAlmSetting{Key: "github", Url: "https://api.github.com", AppId: "12345", ClientId: "Iv1.abc"},
+			want: want{
+				path:   "/api/alm_settings/update_github",
+				params: url.Values{"key": {"github"}, "url": {"https://api.github.com"}, "appId": {"12345"}, "clientId": {"Iv1.abc"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = want{path: r.URL.Path, params: r.Form}
+			}))
+			defer srv.Close()
+
+			c := NewAlmSettingClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if err := c.UpdateGitHub(context.Background(), tc.setting); err != nil {
+				t.Fatalf("\n%s\nc.UpdateGitHub(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.UpdateGitHub(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+var ErrApplicationNotFound = errors.New("Application not found")
+
+type Application struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+	// Projects of the application, by key.
+	Projects []string `json:"-"`
+}
+
+// ApplicationClient manages applications, which only exist on the Developer
+// edition and above. Its methods return ErrUnsupportedEdition on the
+// Community edition.
+type ApplicationClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Application Client
+func NewApplicationClient(options SonarApiOptions) ApplicationClient {
+	return ApplicationClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// do calls an applications endpoint, reporting a missing endpoint as
+// ErrUnsupportedEdition along with the edition of the instance.
+func (applicationClient ApplicationClient) do(ctx context.Context, method string, path string, params url.Values, out any) error {
+	err := applicationClient.sonarApi.do(ctx, method, path, params, out)
+	if isUnknownUrl(err) {
+		return unsupportedEdition(ctx, applicationClient.sonarApi, "applications", EditionDeveloper)
+	}
+	return err
+}
+
+// Create new application
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/create
+func (applicationClient ApplicationClient) Create(ctx context.Context, application Application) error {
+	params := url.Values{}
+	params.Add("key", application.Key)
+	params.Add("name", application.Name)
+	if application.Description != "" {
+		params.Add("description", application.Description)
+	}
+	if application.Visibility != "" {
+		params.Add("visibility", application.Visibility)
+	}
+
+	return applicationClient.do(ctx, "POST", "/api/applications/create", params, nil)
+}
+
+// Update the name and description of an application
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/update
+func (applicationClient ApplicationClient) Update(ctx context.Context, application Application) error {
+	params := url.Values{}
+	params.Add("application", application.Key)
+	params.Add("name", application.Name)
+	params.Add("description", application.Description)
+
+	return applicationClient.do(ctx, "POST", "/api/applications/update", params, nil)
+}
+
+// Delete application
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/delete
+func (applicationClient ApplicationClient) Delete(ctx context.Context, key string) error {
+	params := url.Values{}
+	params.Add("application", key)
+
+	return applicationClient.do(ctx, "POST", "/api/applications/delete", params, nil)
+}
+
+// Get an application and its projects by key
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/show
+func (applicationClient ApplicationClient) Get(ctx context.Context, key string) (Application, error) {
+	params := url.Values{}
+	params.Add("application", key)
+
+	var response struct {
+		Application struct {
+			Application
+			Projects []struct {
+				Key string `json:"key"`
+			} `json:"projects"`
+		} `json:"application"`
+	}
+	err := applicationClient.do(ctx, "GET", "/api/applications/show", params, &response)
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return Application{}, ErrApplicationNotFound
+	}
+	if err != nil {
+		return Application{}, err
+	}
+
+	application := response.Application.Application
+	for _, p := range response.Application.Projects {
+		application.Projects = append(application.Projects, p.Key)
+	}
+	return application, nil
+}
+
+// Add a project to an application
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/add_project
+func (applicationClient ApplicationClient) AddProject(ctx context.Context, key string, project string) error {
+	params := url.Values{}
+	params.Add("application", key)
+	params.Add("project", project)
+
+	return applicationClient.do(ctx, "POST", "/api/applications/add_project", params, nil)
+}
+
+// Remove a project from an application
+// https://next.sonarqube.com/sonarqube/web_api/api/applications/remove_project
+func (applicationClient ApplicationClient) RemoveProject(ctx context.Context, key string, project string) error {
+	params := url.Values{}
+	params.Add("application", key)
+	params.Add("project", project)
+
+	return applicationClient.do(ctx, "POST", "/api/applications/remove_project", params, nil)
+}
@@ -0,0 +1,47 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplicationGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"application":{"key":"payments","name":"Payments","visibility":"private","branch":"main","isMain":true,
+			"projects":[{"key":"payments-api","branch":"main","isMain":true},{"key":"payments-web","branch":"main","isMain":true}]}}`))
+	}))
+	defer srv.Close()
+
+	c := NewApplicationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Get(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("c.Get(...): unexpected error: %v", err)
+	}
+
+	want := Application{Key: "payments", Name: "Payments", Visibility: "private", Projects: []string{"payments-api", "payments-web"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Get(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestApplicationUnsupportedEdition(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Unknown url : ` + r.URL.Path + `"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewApplicationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	_, err := c.Get(context.Background(), "payments")
+	if !errors.Is(err, ErrUnsupportedEdition) {
+		t.Errorf("c.Get(...): want ErrUnsupportedEdition, got %v", err)
+	}
+	if errors.Is(err, ErrApplicationNotFound) {
+		t.Errorf("c.Get(...): a missing endpoint must not be reported as ErrApplicationNotFound")
+	}
+}
@@ -0,0 +1,111 @@
+package sonar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultAuthFailureThreshold is the number of consecutive authentication
+// failures that open the circuit when SonarApiOptions does not set one.
+const DefaultAuthFailureThreshold = 5
+
+// DefaultAuthFailureCooldown is how long an open circuit short-circuits
+// requests when SonarApiOptions does not set one.
+const DefaultAuthFailureCooldown = time.Minute
+
+// ErrAuthCircuitOpen is matched by the error returned for requests that were
+// not sent because their credentials failed repeatedly.
+var ErrAuthCircuitOpen = errors.New("Too many authentication failures")
+
+// An AuthCircuitOpenError is returned instead of sending a request while the
+// circuit of its credentials is open. It unwraps to the last authentication
+// error returned by the API.
+type AuthCircuitOpenError struct {
+	// Until is when requests are sent again.
+	Until time.Time
+	// Err is the last authentication error returned by the API.
+	Err error
+}
+
+func (e *AuthCircuitOpenError) Error() string {
+	return fmt.Sprintf("%s, requests are suspended until %s: %s", ErrAuthCircuitOpen, e.Until.Format(time.RFC3339), e.Err)
+}
+
+func (e *AuthCircuitOpenError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AuthCircuitOpenError) Is(target error) bool {
+	return target == ErrAuthCircuitOpen
+}
+
+// breakerState tracks the authentication failures of one credential.
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+	err       error
+}
+
+// authBreaker stops sending requests with credentials that were rejected
+// repeatedly, e.g. a revoked token, so that every reconcile does not add to
+// the throttling of the instance. Its state is shared by every client, since
+// clients are created for every reconcile.
+type authBreaker struct {
+	mu     sync.Mutex
+	states map[string]*breakerState
+	now    func() time.Time
+}
+
+var authBreakers = &authBreaker{states: map[string]*breakerState{}, now: time.Now}
+
+// breakerKey identifies the credentials of options without keeping the token
+// itself.
+func breakerKey(options SonarApiOptions) string {
+	sum := sha256.Sum256([]byte(options.BaseUrl + "\x00" + options.Key))
+	return hex.EncodeToString(sum[:])
+}
+
+// allow returns an *AuthCircuitOpenError if the circuit of key is open.
+func (b *authBreaker) allow(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.states[key]
+	if !ok || !b.now().Before(s.openUntil) {
+		return nil
+	}
+	return &AuthCircuitOpenError{Until: s.openUntil, Err: s.err}
+}
+
+// record updates the circuit of key with the outcome of a request. A 401
+// counts as a failure and opens the circuit for cooldown once threshold
+// failures happened in a row. A 403 only counts when forbidden is true, since
+// it is also returned to valid credentials that lack the permission of one
+// resource, which must not suspend the requests for the others. Any other
+// response closes the circuit.
+func (b *authBreaker) record(key string, statusCode int, err error, threshold int, cooldown time.Duration, forbidden bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if statusCode != http.StatusUnauthorized && !(forbidden && statusCode == http.StatusForbidden) {
+		delete(b.states, key)
+		return
+	}
+
+	s, ok := b.states[key]
+	if !ok {
+		s = &breakerState{}
+		b.states[key] = s
+	}
+	s.failures++
+	s.err = err
+	if s.failures >= threshold {
+		s.openUntil = b.now().Add(cooldown)
+		s.failures = 0
+	}
+}
@@ -0,0 +1,152 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// withFakeClock makes the breaker use a clock advanced by the returned
+// function, for the duration of the test.
+func withFakeClock(t *testing.T) func(d time.Duration) {
+	t.Helper()
+	now := time.Date(2022, time.November, 10, 12, 0, 0, 0, time.UTC)
+	authBreakers.mu.Lock()
+	authBreakers.now = func() time.Time { return now }
+	authBreakers.mu.Unlock()
+	t.Cleanup(func() {
+		authBreakers.mu.Lock()
+		authBreakers.now = time.Now
+		authBreakers.mu.Unlock()
+	})
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestAuthCircuitBreaker(t *testing.T) {
+	advance := withFakeClock(t)
+
+	calls := 0
+	status := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "revoked", BaseUrl: srv.URL, AuthFailureThreshold: 3, AuthFailureCooldown: time.Minute})
+	call := func() error { return c.Delete(context.Background(), "key") }
+
+	for i := 0; i < 3; i++ {
+		if err := call(); errors.Is(err, ErrAuthCircuitOpen) {
+			t.Fatalf("call %d: the circuit should not open before the threshold, got %v", i+1, err)
+		}
+	}
+
+	err := call()
+	if !errors.Is(err, ErrAuthCircuitOpen) {
+		t.Fatalf("c.Delete(...): want ErrAuthCircuitOpen once the threshold is reached, got %v", err)
+	}
+	var apiErr *SonarAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("c.Delete(...): want the cached 401 error, got %v", err)
+	}
+	if diff := cmp.Diff(3, calls); diff != "" {
+		t.Errorf("c.Delete(...): requests should be short-circuited while the circuit is open: -want calls, +got calls:\n%s\n", diff)
+	}
+
+	other := NewProjectClient(SonarApiOptions{Key: "valid", BaseUrl: srv.URL})
+	if err := other.Delete(context.Background(), "key"); errors.Is(err, ErrAuthCircuitOpen) {
+		t.Errorf("other.Delete(...): other credentials should not be short-circuited, got %v", err)
+	}
+	calls = 0
+
+	advance(time.Minute)
+	status = http.StatusNoContent
+	if err := call(); err != nil {
+		t.Errorf("c.Delete(...): requests should be sent again after the cooldown, got %v", err)
+	}
+	if diff := cmp.Diff(1, calls); diff != "" {
+		t.Errorf("c.Delete(...): -want calls, +got calls:\n%s\n", diff)
+	}
+}
+
+func TestAuthCircuitBreakerReset(t *testing.T) {
+	withFakeClock(t)
+
+	statuses := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[calls])
+		calls++
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, AuthFailureThreshold: 3})
+	for range statuses {
+		if err := c.Delete(context.Background(), "key"); errors.Is(err, ErrAuthCircuitOpen) {
+			t.Fatalf("c.Delete(...): a success should reset the count of failures, got %v", err)
+		}
+	}
+	if diff := cmp.Diff(len(statuses), calls); diff != "" {
+		t.Errorf("c.Delete(...): -want calls, +got calls:\n%s\n", diff)
+	}
+}
+
+func TestAuthCircuitBreakerForbidden(t *testing.T) {
+	withFakeClock(t)
+
+	cases := map[string]struct {
+		reason    string
+		forbidden bool
+		wantOpen  bool
+		wantCalls int
+	}{
+		"NotCounted": {
+			reason:    "A 403 is returned to valid credentials that lack a permission, so it should not suspend the other requests made with them by default.",
+			wantCalls: 3,
+		},
+		"Counted": {
+			reason:    "A 403 should count as an authentication failure when AuthFailureForbidden is set.",
+			forbidden: true,
+			wantOpen:  true,
+			wantCalls: 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusForbidden)
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, AuthFailureThreshold: 2, AuthFailureForbidden: tc.forbidden})
+			var err error
+			for i := 0; i < 3; i++ {
+				err = c.Delete(context.Background(), "key")
+			}
+			if diff := cmp.Diff(tc.wantOpen, errors.Is(err, ErrAuthCircuitOpen)); diff != "" {
+				t.Errorf("\n%s\nc.Delete(...): -want circuit open, +got circuit open:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Errorf("\n%s\nc.Delete(...): -want calls, +got calls:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	backoff := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		if got := jitter(backoff); got < backoff/2 || got > backoff {
+			t.Fatalf("jitter(%s): want a wait between %s and %s, got %s", backoff, backoff/2, backoff, got)
+		}
+	}
+}
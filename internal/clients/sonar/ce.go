@@ -0,0 +1,134 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CeTaskStatus is the state of a background task of the Compute Engine.
+type CeTaskStatus string
+
+const (
+	CeTaskPending    CeTaskStatus = "PENDING"
+	CeTaskInProgress CeTaskStatus = "IN_PROGRESS"
+	CeTaskSuccess    CeTaskStatus = "SUCCESS"
+	CeTaskFailed     CeTaskStatus = "FAILED"
+	CeTaskCanceled   CeTaskStatus = "CANCELED"
+)
+
+// ErrCeTaskFailed is returned when a background task ends without succeeding.
+var ErrCeTaskFailed = errors.New("Background task did not succeed")
+
+// Terminal returns true if a task in this state will not change anymore.
+func (status CeTaskStatus) Terminal() bool {
+	return status == CeTaskSuccess || status == CeTaskFailed || status == CeTaskCanceled
+}
+
+type CeTask struct {
+	Id           string       `json:"id"`
+	Type         string       `json:"type"`
+	ComponentKey string       `json:"componentKey"`
+	Status       CeTaskStatus `json:"status"`
+	ErrorMessage string       `json:"errorMessage,omitempty"`
+}
+
+// maxTaskPollInterval caps the wait between two polls of a background task.
+const maxTaskPollInterval = 30 * time.Second
+
+type CeClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Compute Engine Client
+func NewCeClient(options SonarApiOptions) CeClient {
+	return CeClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Get a background task
+// https://sonarcloud.io/web_api/api/ce/task
+func (ceClient CeClient) GetTask(ctx context.Context, id string) (CeTask, error) {
+	params := url.Values{}
+	params.Add("id", id)
+
+	var response struct {
+		Task CeTask `json:"task"`
+	}
+	err := ceClient.sonarApi.do(ctx, "GET", "/api/ce/task", params, &response)
+	return response.Task, err
+}
+
+// CeActivityOptions filter the background tasks returned by Activity.
+type CeActivityOptions struct {
+	// Component whose tasks are returned, e.g. a project key
+	Component string
+	// Statuses of the tasks. Only the finished ones are returned when empty.
+	Statuses []CeTaskStatus
+	// Type of the tasks, e.g. REPORT or PROJECT_EXPORT
+	Type string
+}
+
+// Search the background tasks, the most recent first
+// https://sonarcloud.io/web_api/api/ce/activity
+func (ceClient CeClient) Activity(ctx context.Context, options CeActivityOptions) ([]CeTask, error) {
+	params := url.Values{}
+	if options.Component != "" {
+		params.Add("component", options.Component)
+	}
+	if len(options.Statuses) > 0 {
+		statuses := make([]string, 0, len(options.Statuses))
+		for _, status := range options.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		params.Add("status", strings.Join(statuses, ","))
+	}
+	if options.Type != "" {
+		params.Add("type", options.Type)
+	}
+
+	var response struct {
+		Tasks []CeTask `json:"tasks"`
+	}
+	if err := ceClient.sonarApi.do(ctx, "GET", "/api/ce/activity", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Tasks, nil
+}
+
+// WaitForTask polls a background task until it is in a terminal state or ctx
+// is done. The first poll waits TaskPollInterval, and every next wait doubles
+// up to 30 seconds. A task that failed or was canceled is returned along with
+// ErrCeTaskFailed.
+func (ceClient CeClient) WaitForTask(ctx context.Context, id string) (CeTask, error) {
+	interval := ceClient.sonarApi.Options.TaskPollInterval
+	for {
+		task, err := ceClient.GetTask(ctx, id)
+		if err != nil {
+			return CeTask{}, err
+		}
+
+		switch task.Status {
+		case CeTaskSuccess:
+			return task, nil
+		case CeTaskFailed, CeTaskCanceled:
+			if task.ErrorMessage != "" {
+				return task, fmt.Errorf("%w: task %s is %s: %s", ErrCeTaskFailed, id, task.Status, task.ErrorMessage)
+			}
+			return task, fmt.Errorf("%w: task %s is %s", ErrCeTaskFailed, id, task.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return task, ctx.Err()
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > maxTaskPollInterval {
+			interval = maxTaskPollInterval
+		}
+	}
+}
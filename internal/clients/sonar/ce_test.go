@@ -0,0 +1,144 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCeActivity(t *testing.T) {
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(`{"tasks":[{"id":"AU-2","type":"REPORT","componentKey":"key","status":"IN_PROGRESS"},{"id":"AU-1","type":"REPORT","componentKey":"key","status":"PENDING"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewCeClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Activity(context.Background(), CeActivityOptions{Component: "key", Statuses: []CeTaskStatus{CeTaskPending, CeTaskInProgress}, Type: "REPORT"})
+	if err != nil {
+		t.Fatalf("c.Activity(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(url.Values{"component": {"key"}, "status": {"PENDING,IN_PROGRESS"}, "type": {"REPORT"}}, params); diff != "" {
+		t.Errorf("c.Activity(...): -want params, +got params:\n%s\n", diff)
+	}
+	want := []CeTask{
+		{Id: "AU-2", Type: "REPORT", ComponentKey: "key", Status: CeTaskInProgress},
+		{Id: "AU-1", Type: "REPORT", ComponentKey: "key", Status: CeTaskPending},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Activity(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCeWaitForTask(t *testing.T) {
+	type want struct {
+		task  CeTask
+		err   error
+		polls int
+	}
+
+	cases := map[string]struct {
+		reason   string
+		statuses []string
+		want     want
+	}{
+		"Success": {
+			reason:   "The task should be polled until it succeeds.",
+			statuses: []string{`"PENDING"`, `"IN_PROGRESS"`, `"SUCCESS"`},
+			want: want{
+				task:  CeTask{Id: "AU-Tpxb--iU5OvuD2FLy", Type: "PROJECT_EXPORT", ComponentKey: "key", Status: CeTaskSuccess},
+				polls: 3,
+			},
+		},
+		"Canceled": {
+			reason:   "A canceled task should be returned with ErrCeTaskFailed.",
+			statuses: []string{`"PENDING"`, `"CANCELED"`},
+			want: want{
+				task:  CeTask{Id: "AU-Tpxb--iU5OvuD2FLy", Type: "PROJECT_EXPORT", ComponentKey: "key", Status: CeTaskCanceled},
+				err:   ErrCeTaskFailed,
+				polls: 2,
+			},
+		},
+		"Failed": {
+			reason:   "A failed task should be returned with ErrCeTaskFailed.",
+			statuses: []string{`"IN_PROGRESS"`, `"FAILED","errorMessage":"Dump file not found"`},
+			want: want{
+				task:  CeTask{Id: "AU-Tpxb--iU5OvuD2FLy", Type: "PROJECT_EXPORT", ComponentKey: "key", Status: CeTaskFailed, ErrorMessage: "Dump file not found"},
+				err:   ErrCeTaskFailed,
+				polls: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			polls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/ce/task" || r.FormValue("id") != "AU-Tpxb--iU5OvuD2FLy" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				status := tc.statuses[polls]
+				polls++
+				_, _ = w.Write([]byte(`{"task":{"id":"AU-Tpxb--iU5OvuD2FLy","type":"PROJECT_EXPORT","componentKey":"key","status":` + status + `}}`))
+			}))
+			defer srv.Close()
+
+			c := NewCeClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, TaskPollInterval: time.Millisecond})
+			got, err := c.WaitForTask(context.Background(), "AU-Tpxb--iU5OvuD2FLy")
+			if !errors.Is(err, tc.want.err) {
+				t.Errorf("\n%s\nc.WaitForTask(...): want error %v, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.task, got); diff != "" {
+				t.Errorf("\n%s\nc.WaitForTask(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.polls, polls); diff != "" {
+				t.Errorf("\n%s\nc.WaitForTask(...): -want polls, +got polls:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCeWaitForTaskCanceledContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"task":{"id":"AU-Tpxb--iU5OvuD2FLy","status":"PENDING"}}`))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	c := NewCeClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, TaskPollInterval: 10 * time.Millisecond})
+	if _, err := c.WaitForTask(ctx, "AU-Tpxb--iU5OvuD2FLy"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("c.WaitForTask(...): want error %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestCeWaitForTaskBackoff(t *testing.T) {
+	var polls []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, time.Now())
+		status := "PENDING"
+		if len(polls) == 3 {
+			status = "SUCCESS"
+		}
+		_, _ = w.Write([]byte(`{"task":{"id":"AU-1","status":"` + status + `"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewCeClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, TaskPollInterval: 20 * time.Millisecond})
+	if _, err := c.WaitForTask(context.Background(), "AU-1"); err != nil {
+		t.Fatalf("c.WaitForTask(...): unexpected error: %v", err)
+	}
+	// The second wait is twice as long as the first one.
+	if got := polls[2].Sub(polls[1]); got < 40*time.Millisecond {
+		t.Errorf("c.WaitForTask(...): want at least 40ms between the second and third polls, got %s", got)
+	}
+}
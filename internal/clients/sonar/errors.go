@@ -0,0 +1,214 @@
+package sonar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrUnsupportedEdition is returned when an endpoint does not exist on the
+// edition of the instance, e.g. portfolios on a Developer edition.
+var ErrUnsupportedEdition = errors.New("Not supported by the edition of this instance")
+
+// ErrNoOrganizations is returned by the calls that manage organizations when
+// the instance is a SonarQube, which has no organizations.
+var ErrNoOrganizations = errors.New("Not supported by SonarQube, which has no organizations")
+
+// ErrInvalidCredentials is returned when the sonar api rejects the token it
+// is called with.
+var ErrInvalidCredentials = errors.New("Invalid credentials: the token was rejected")
+
+// ErrTokenScope is matched by the error returned for requests the type of the
+// token does not allow.
+var ErrTokenScope = errors.New("Not allowed by the scope of the token")
+
+// A TokenScopeError is returned for requests that an analysis token cannot
+// send. Err is the error returned by the API, if the request was sent.
+type TokenScopeError struct {
+	// TokenType of the token, e.g. PROJECT_ANALYSIS_TOKEN.
+	TokenType string
+	// Method and Path of the request.
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *TokenScopeError) Error() string {
+	msg := fmt.Sprintf("%s: a %s can only run analyses, not call %s %s", ErrTokenScope, e.TokenType, e.Method, e.Path)
+	if e.Err == nil {
+		return msg
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *TokenScopeError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TokenScopeError) Is(target error) bool {
+	return target == ErrTokenScope
+}
+
+// ErrProjectLimitReached is matched by the error returned when a project
+// cannot be created because the organization reached the project limit of
+// its plan, e.g. the private projects of a free SonarCloud organization.
+var ErrProjectLimitReached = errors.New("Project limit of the organization plan reached")
+
+// A ProjectLimitError is returned when the plan of an organization does not
+// allow another project. Err is the error returned by the API.
+type ProjectLimitError struct {
+	Organization string
+	Err          error
+}
+
+func (e *ProjectLimitError) Error() string {
+	return fmt.Sprintf("%s: organization %q: %s", ErrProjectLimitReached, e.Organization, e.Err)
+}
+
+func (e *ProjectLimitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ProjectLimitError) Is(target error) bool {
+	return target == ErrProjectLimitReached
+}
+
+// ErrInvalidCondition is returned for a quality gate condition whose metric
+// cannot be used with its operator.
+var ErrInvalidCondition = errors.New("invalid quality gate condition")
+
+// InvalidConditionError is returned for a quality gate condition that the API
+// would reject, naming the condition and the reason.
+type InvalidConditionError struct {
+	Condition QualityGateCondition
+	Reason    string
+}
+
+func (e *InvalidConditionError) Error() string {
+	return fmt.Sprintf("%s on metric %q with operator %q: %s", ErrInvalidCondition, e.Condition.Metric, e.Condition.Op, e.Reason)
+}
+
+func (e *InvalidConditionError) Is(target error) bool {
+	return target == ErrInvalidCondition
+}
+
+// isAnalysisToken returns true if a token of the supplied type can only run
+// analyses.
+func isAnalysisToken(tokenType string) bool {
+	return tokenType == UserTokenTypeGlobalAnalysis || tokenType == UserTokenTypeProjectAnalysis
+}
+
+// SonarAPIError is returned when the Sonar API answers with a non-2xx status.
+type SonarAPIError struct {
+	// StatusCode of the response, for example 400 or 403.
+	StatusCode int
+	// Status of the response, for example "400 Bad Request".
+	Status string
+	// Message joins the messages of the errors array in the response body.
+	// It is empty when the body has no such array.
+	Message string
+}
+
+func (e *SonarAPIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("error calling sonar api: %s", e.Status)
+	}
+	return fmt.Sprintf("error calling sonar api: %s: %s", e.Status, e.Message)
+}
+
+// MaxDecodeErrorBody is the number of bytes of a response body kept in a
+// DecodeError.
+const MaxDecodeErrorBody = 512
+
+// A DecodeError is returned when the body of a successful response cannot be
+// decoded, e.g. because a proxy answered with an HTML login page. It keeps
+// the start of the body so that such answers can be told apart.
+type DecodeError struct {
+	// ContentType of the response, e.g. text/html.
+	ContentType string
+	// Body holds the first MaxDecodeErrorBody bytes of the response body.
+	Body string
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("cannot decode sonar api response of content type %q: %s: body starts with %q", e.ContentType, e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError returns a *DecodeError for the supplied response body.
+func newDecodeError(contentType string, body []byte, err error) *DecodeError {
+	if len(body) > MaxDecodeErrorBody {
+		body = body[:MaxDecodeErrorBody]
+	}
+	return &DecodeError{ContentType: contentType, Body: string(body), Err: err}
+}
+
+// checkResponse returns nil if resp has a 2xx status and a *SonarAPIError
+// carrying the messages of its body otherwise.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	apiErr := &SonarAPIError{StatusCode: resp.StatusCode, Status: resp.Status}
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apiErr
+	}
+
+	var body struct {
+		Errors []struct {
+			Msg string `json:"msg"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(responseData, &body); err != nil {
+		return apiErr
+	}
+
+	msgs := make([]string, 0, len(body.Errors))
+	for _, e := range body.Errors {
+		msgs = append(msgs, e.Msg)
+	}
+	apiErr.Message = strings.Join(msgs, "; ")
+
+	return apiErr
+}
+
+// isUnknownUrl returns true if err reports that the endpoint called does not
+// exist, which is how an instance answers for endpoints of a higher edition.
+func isUnknownUrl(err error) bool {
+	var apiErr *SonarAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && strings.HasPrefix(apiErr.Message, "Unknown url")
+}
+
+// isAlreadyExists returns true if err reports that the key of the resource
+// being created is already taken. SonarCloud answers "Could not create
+// Project, key already exists: foo", SonarQube "Could not create Project with
+// key: "foo". A similar key already exists: "foo"". The key taken by SonarQube
+// may only be similar to the one requested, so callers compare them.
+func isAlreadyExists(err error) bool {
+	var apiErr *SonarAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && strings.Contains(apiErr.Message, "key already exists")
+}
+
+// isProjectLimitReached returns true if err reports that the plan of the
+// organization does not allow another project. SonarCloud answers with a 400
+// whose message mentions the private projects the plan allows, e.g. "This
+// organization cannot use project private" or "Organization has reached the
+// limit of private projects".
+func isProjectLimitReached(err error) bool {
+	var apiErr *SonarAPIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "private") && (strings.Contains(msg, "limit") || strings.Contains(msg, "cannot use"))
+}
@@ -0,0 +1,224 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSonarAPIError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		body   string
+		want   *SonarAPIError
+	}{
+		"Validation": {
+			reason: "The messages of a 400 response should be joined into the error.",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"msg":"Could not create Project, key already exists: key"},{"msg":"Name is too long"}]}`,
+			want: &SonarAPIError{
+				StatusCode: http.StatusBadRequest,
+				Status:     "400 Bad Request",
+				Message:    "Could not create Project, key already exists: key; Name is too long",
+			},
+		},
+		"Unauthorized": {
+			reason: "A 401 response without a body should still carry its status code.",
+			status: http.StatusUnauthorized,
+			want: &SonarAPIError{
+				StatusCode: http.StatusUnauthorized,
+				Status:     "401 Unauthorized",
+			},
+		},
+		"Forbidden": {
+			reason: "The message of a 403 response should be carried in the error.",
+			status: http.StatusForbidden,
+			body:   `{"errors":[{"msg":"Insufficient privileges"}]}`,
+			want: &SonarAPIError{
+				StatusCode: http.StatusForbidden,
+				Status:     "403 Forbidden",
+				Message:    "Insufficient privileges",
+			},
+		},
+		"RateLimited": {
+			reason: "A 429 response with a body that is not JSON should still carry its status code.",
+			status: http.StatusTooManyRequests,
+			body:   `Too Many Requests`,
+			want: &SonarAPIError{
+				StatusCode: http.StatusTooManyRequests,
+				Status:     "429 Too Many Requests",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, MaxAttempts: 1})
+			err := c.Delete(context.Background(), "key")
+
+			var got *SonarAPIError
+			if !errors.As(err, &got) {
+				t.Fatalf("\n%s\nc.Delete(...): want *SonarAPIError, got %T: %v", tc.reason, err, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSonarAPIErrorMessage(t *testing.T) {
+	cases := map[string]struct {
+		err  *SonarAPIError
+		want string
+	}{
+		"StatusOnly": {
+			err:  &SonarAPIError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"},
+			want: "error calling sonar api: 401 Unauthorized",
+		},
+		"WithMessage": {
+			err:  &SonarAPIError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request", Message: "Name is too long"},
+			want: "error calling sonar api: 400 Bad Request: Name is too long",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.err.Error()); diff != "" {
+				t.Errorf("Error(): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestTokenScope(t *testing.T) {
+	type want struct {
+		err   string
+		scope bool
+		sent  bool
+	}
+
+	cases := map[string]struct {
+		reason    string
+		tokenType string
+		method    string
+		want      want
+	}{
+		"AnalysisTokenWrite": {
+			reason:    "A write with an analysis token should fail early, naming the scope of the token.",
+			tokenType: UserTokenTypeProjectAnalysis,
+			method:    http.MethodPost,
+			want: want{
+				err:   "Not allowed by the scope of the token: a PROJECT_ANALYSIS_TOKEN can only run analyses, not call POST /api/projects/delete",
+				scope: true,
+			},
+		},
+		"AnalysisTokenForbidden": {
+			reason:    "A read refused to an analysis token should be reported as a scope issue, with the reason given by the API.",
+			tokenType: UserTokenTypeGlobalAnalysis,
+			method:    http.MethodGet,
+			want: want{
+				err:   "Not allowed by the scope of the token: a GLOBAL_ANALYSIS_TOKEN can only run analyses, not call GET /api/projects/delete: error calling sonar api: 403 Forbidden: Insufficient privileges",
+				scope: true,
+				sent:  true,
+			},
+		},
+		"UserTokenForbidden": {
+			reason:    "A request refused to a user token should be reported as a permission issue of the user.",
+			tokenType: UserTokenTypeUser,
+			method:    http.MethodPost,
+			want: want{
+				err:  "error calling sonar api: 403 Forbidden: Insufficient privileges",
+				sent: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sent := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"errors":[{"msg":"Insufficient privileges"}]}`))
+			}))
+			defer srv.Close()
+
+			api := NewSonarApi(SonarApiOptions{Key: name, BaseUrl: srv.URL, TokenType: tc.tokenType})
+			err := api.do(context.Background(), tc.method, "/api/projects/delete", nil, nil)
+			if err == nil {
+				t.Fatalf("\n%s\ndo(...): want error, got nil", tc.reason)
+			}
+			got := want{err: err.Error(), scope: errors.Is(err, ErrTokenScope), sent: sent}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ndo(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	type want struct {
+		contentType string
+		body        string
+	}
+
+	login := `<!DOCTYPE html><html><head><title>Sign in</title></head><body>` + strings.Repeat("<p>Proxy login</p>", 40) + `</body></html>`
+
+	cases := map[string]struct {
+		reason      string
+		contentType string
+		body        string
+		want        want
+	}{
+		"ProxyLoginPage": {
+			reason:      "An HTML page should be reported with its content type and the first bytes of its body.",
+			contentType: "text/html; charset=utf-8",
+			body:        login,
+			want:        want{contentType: "text/html; charset=utf-8", body: login[:MaxDecodeErrorBody]},
+		},
+		"ChangedShape": {
+			reason:      "A JSON body of another shape should be reported whole when it is short.",
+			contentType: "application/json",
+			body:        `{"components":"none"}`,
+			want:        want{contentType: "application/json", body: `{"components":"none"}`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			_, err := c.Search(context.Background(), "org", SearchOptions{})
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("\n%s\nc.Search(...): want a *DecodeError, got %v", tc.reason, err)
+			}
+			got := want{contentType: decodeErr.ContentType, body: decodeErr.Body}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if !strings.Contains(err.Error(), tc.contentType) {
+				t.Errorf("\n%s\nc.Search(...): want the content type in %q", tc.reason, err.Error())
+			}
+		})
+	}
+}
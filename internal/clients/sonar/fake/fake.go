@@ -0,0 +1,89 @@
+// Package fake provides fake implementations of the Sonar clients, for use in
+// tests.
+package fake
+
+import (
+	"context"
+
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+var _ sonar.ProjectService = &MockProjectService{}
+
+// A MockProjectService is a fake sonar.ProjectService whose behavior is driven
+// by its function fields. Calling a method whose field is unset panics, so
+// unexpected calls fail the test.
+type MockProjectService struct {
+	MockCreate           func(ctx context.Context, organization string, name string, project string, visibility string, mainBranch string) (sonar.Project, bool, error)
+	MockDelete           func(ctx context.Context, project string) error
+	MockSearch           func(ctx context.Context, organization string, options sonar.SearchOptions) (sonar.ProjectPage, error)
+	MockGetByProjectKey  func(ctx context.Context, organization string, project string) (sonar.Project, error)
+	MockGetProjectUrl    func(project string) (string, error)
+	MockUpdateVisibility func(ctx context.Context, project string, visibility string) error
+	MockUpdateName       func(ctx context.Context, project string, name string) error
+	MockSetTags          func(ctx context.Context, project string, tags []string) error
+	MockGetBadgeToken    func(ctx context.Context, project string) (string, error)
+	MockRenewBadgeToken  func(ctx context.Context, project string) error
+	MockSetNewCodePeriod func(ctx context.Context, project string, period sonar.NewCodePeriod) error
+	MockGetNewCodePeriod func(ctx context.Context, project string) (sonar.NewCodePeriod, error)
+}
+
+// Create calls MockCreate.
+func (m *MockProjectService) Create(ctx context.Context, organization string, name string, project string, visibility string, mainBranch string) (sonar.Project, bool, error) {
+	return m.MockCreate(ctx, organization, name, project, visibility, mainBranch)
+}
+
+// Delete calls MockDelete.
+func (m *MockProjectService) Delete(ctx context.Context, project string) error {
+	return m.MockDelete(ctx, project)
+}
+
+// Search calls MockSearch.
+func (m *MockProjectService) Search(ctx context.Context, organization string, options sonar.SearchOptions) (sonar.ProjectPage, error) {
+	return m.MockSearch(ctx, organization, options)
+}
+
+// GetByProjectKey calls MockGetByProjectKey.
+func (m *MockProjectService) GetByProjectKey(ctx context.Context, organization string, project string) (sonar.Project, error) {
+	return m.MockGetByProjectKey(ctx, organization, project)
+}
+
+// GetProjectUrl calls MockGetProjectUrl.
+func (m *MockProjectService) GetProjectUrl(project string) (string, error) {
+	return m.MockGetProjectUrl(project)
+}
+
+// UpdateVisibility calls MockUpdateVisibility.
+func (m *MockProjectService) UpdateVisibility(ctx context.Context, project string, visibility string) error {
+	return m.MockUpdateVisibility(ctx, project, visibility)
+}
+
+// UpdateName calls MockUpdateName.
+func (m *MockProjectService) UpdateName(ctx context.Context, project string, name string) error {
+	return m.MockUpdateName(ctx, project, name)
+}
+
+// SetTags calls MockSetTags.
+func (m *MockProjectService) SetTags(ctx context.Context, project string, tags []string) error {
+	return m.MockSetTags(ctx, project, tags)
+}
+
+// GetBadgeToken calls MockGetBadgeToken.
+func (m *MockProjectService) GetBadgeToken(ctx context.Context, project string) (string, error) {
+	return m.MockGetBadgeToken(ctx, project)
+}
+
+// RenewBadgeToken calls MockRenewBadgeToken.
+func (m *MockProjectService) RenewBadgeToken(ctx context.Context, project string) error {
+	return m.MockRenewBadgeToken(ctx, project)
+}
+
+// SetNewCodePeriod calls MockSetNewCodePeriod.
+func (m *MockProjectService) SetNewCodePeriod(ctx context.Context, project string, period sonar.NewCodePeriod) error {
+	return m.MockSetNewCodePeriod(ctx, project, period)
+}
+
+// GetNewCodePeriod calls MockGetNewCodePeriod.
+func (m *MockProjectService) GetNewCodePeriod(ctx context.Context, project string) (sonar.NewCodePeriod, error) {
+	return m.MockGetNewCodePeriod(ctx, project)
+}
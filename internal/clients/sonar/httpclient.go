@@ -0,0 +1,180 @@
+package sonar
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MaxIdleConnsPerHost is the number of keep-alive connections kept open to the
+// instance. The default of net/http, 2, is far below the number of concurrent
+// reconciles.
+const MaxIdleConnsPerHost = 32
+
+// IdleConnTimeout is how long a keep-alive connection is kept open unused.
+const IdleConnTimeout = 90 * time.Second
+
+// MaxHttpClients is the number of shared clients kept, e.g. for the transport
+// options of as many ProviderConfigs. The least recently used client is
+// dropped to make room for another.
+const MaxHttpClients = 32
+
+// DrainTimeout is how long the requests in flight are waited for once the
+// provider stops.
+const DrainTimeout = 10 * time.Second
+
+// httpClientCache shares one *http.Client, and so one pool of keep-alive
+// connections, between the SonarApis with the same transport options. Clients
+// are created for every reconcile, so without it every reconcile would open
+// new connections. An *http.Client is safe for concurrent use.
+type httpClientCache struct {
+	mu      sync.Mutex
+	clients map[string]*cachedClient
+	// uses orders the clients by their last use.
+	uses uint64
+}
+
+type cachedClient struct {
+	client   *http.Client
+	lastUsed uint64
+}
+
+var httpClients = &httpClientCache{clients: map[string]*cachedClient{}}
+
+// transportKey identifies the options an *http.Client is built from.
+func transportKey(options SonarApiOptions) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%t\x00%s", options.Timeout, options.ProxyUrl, options.InsecureSkipVerify, options.CABundle)))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the client for options, creating it on first use. A client whose
+// transport cannot be configured is returned with the error, and not shared.
+func (c *httpClientCache) get(options SonarApiOptions) (*http.Client, error) {
+	key := transportKey(options)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.clients[key]; ok {
+		c.uses++
+		cached.lastUsed = c.uses
+		return cached.client, nil
+	}
+
+	transport, err := newTransport(options)
+	client := &http.Client{Timeout: options.Timeout, Transport: transport}
+	if err != nil {
+		return client, err
+	}
+	if len(c.clients) >= MaxHttpClients {
+		c.evict()
+	}
+	c.uses++
+	c.clients[key] = &cachedClient{client: client, lastUsed: c.uses}
+	return client, nil
+}
+
+// evict drops the least recently used client, closing its idle connections.
+// Requests it still sends keep their connections until they complete.
+func (c *httpClientCache) evict() {
+	var oldest string
+	for key, cached := range c.clients {
+		if oldest == "" || cached.lastUsed < c.clients[oldest].lastUsed {
+			oldest = key
+		}
+	}
+	c.clients[oldest].client.CloseIdleConnections()
+	delete(c.clients, oldest)
+}
+
+// closeIdleConnections closes the idle keep-alive connections of every
+// shared client. Connections in use are left to finish their requests.
+func (c *httpClientCache) closeIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cached := range c.clients {
+		cached.client.CloseIdleConnections()
+	}
+}
+
+// requestTracker counts the requests in flight, so that they can be waited for
+// when the provider stops.
+type requestTracker struct {
+	mu      sync.Mutex
+	n       int
+	drained []chan struct{}
+}
+
+var requests = &requestTracker{}
+
+// start records a request in flight. It must be followed by done.
+func (t *requestTracker) start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n++
+}
+
+// done records the end of a request, releasing the waiters of wait once no
+// request is left in flight.
+func (t *requestTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n--
+	if t.n > 0 {
+		return
+	}
+	for _, drained := range t.drained {
+		close(drained)
+	}
+	t.drained = nil
+}
+
+// wait blocks until no request is in flight, or ctx is done.
+func (t *requestTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.n == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	drained := make(chan struct{})
+	t.drained = append(t.drained, drained)
+	t.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CloseIdleConnectionsWhenDone blocks until ctx is done, then drains the
+// requests in flight and closes the idle keep-alive connections of every
+// shared client, so that none are leaked when the provider stops. Requests in
+// flight are cancelled by their own contexts, which derive from the same one,
+// and are waited for up to DrainTimeout to return. It fits a controller
+// manager Runnable.
+func CloseIdleConnectionsWhenDone(ctx context.Context) error {
+	<-ctx.Done()
+	return drain(DrainTimeout)
+}
+
+// drain waits up to timeout for the requests in flight to return, then closes
+// the idle connections of every shared client, including those of the
+// requests that returned.
+func drain(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := requests.wait(ctx)
+	httpClients.closeIdleConnections()
+	if err != nil {
+		return fmt.Errorf("requests still in flight after %s: %w", timeout, err)
+	}
+	return nil
+}
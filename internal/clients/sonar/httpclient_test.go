@@ -0,0 +1,274 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHttpClientReused(t *testing.T) {
+	base := SonarApiOptions{Key: "token", BaseUrl: "https://sonarcloud.io"}
+
+	cases := map[string]struct {
+		reason  string
+		options SonarApiOptions
+		same    bool
+	}{
+		"SameOptions": {
+			reason:  "Clients with the same transport options should share one HTTP client.",
+			options: base,
+			same:    true,
+		},
+		"OtherCredentials": {
+			reason:  "Credentials are sent per request, so clients with other credentials should share the HTTP client too.",
+			options: SonarApiOptions{Key: "other", BaseUrl: "https://sonarqube.example.org", Organization: "org"},
+			same:    true,
+		},
+		"OtherTimeout": {
+			reason:  "Clients with another timeout should not share the HTTP client.",
+			options: SonarApiOptions{Key: "token", Timeout: time.Second},
+		},
+		"OtherProxy": {
+			reason:  "Clients with another proxy should not share the HTTP client.",
+			options: SonarApiOptions{Key: "token", ProxyUrl: "http://proxy.example.org:3128"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, b := NewSonarApi(base), NewSonarApi(tc.options)
+			if got := a.client == b.client; got != tc.same {
+				t.Errorf("\n%s\nNewSonarApi(...): want the same client %t, got %t", tc.reason, tc.same, got)
+			}
+		})
+	}
+}
+
+func TestHttpClientTransport(t *testing.T) {
+	transport := NewSonarApi(SonarApiOptions{}).client.Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != MaxIdleConnsPerHost {
+		t.Errorf("MaxIdleConnsPerHost: want %d, got %d", MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != IdleConnTimeout {
+		t.Errorf("IdleConnTimeout: want %s, got %s", IdleConnTimeout, transport.IdleConnTimeout)
+	}
+}
+
+func TestHttpClientConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("10.2.1.78527"))
+	}))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := NewSystemClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL}).Version(context.Background()); err != nil {
+				t.Errorf("c.Version(...): unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkReconcileRequests compares a request sent by a new client, as every
+// reconcile creates one, with and without a shared HTTP client. Without it
+// every request opens a new connection.
+func BenchmarkReconcileRequests(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("10.2.1.78527"))
+	}))
+	defer srv.Close()
+	options := SonarApiOptions{Key: "token", BaseUrl: srv.URL}
+
+	b.Run("Shared", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := NewSystemClient(options).Version(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("PerClient", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			c := NewSystemClient(options)
+			transport, _ := newTransport(options)
+			c.sonarApi.client = &http.Client{Timeout: options.Timeout, Transport: transport}
+			if _, err := c.Version(context.Background()); err != nil {
+				b.Fatal(err)
+			}
+			transport.CloseIdleConnections()
+		}
+	})
+}
+
+func TestCloseIdleConnectionsWhenDone(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	// The connection of the request is kept alive once it completed.
+	c := NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.do(context.Background(), "GET", "/api/system/status", url.Values{}, nil); err != nil {
+		t.Fatalf("c.do(...): unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- CloseIdleConnectionsWhenDone(ctx) }()
+
+	select {
+	case <-done:
+		t.Fatal("CloseIdleConnectionsWhenDone(...): returned before the context was done")
+	case <-closed:
+		t.Fatal("CloseIdleConnectionsWhenDone(...): closed the connection before the context was done")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("CloseIdleConnectionsWhenDone(...): unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CloseIdleConnectionsWhenDone(...): did not return once the context was done")
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Error("CloseIdleConnectionsWhenDone(...): did not close the idle connection")
+	}
+}
+
+func TestPendingRequestCancelled(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	c := NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	start := time.Now()
+	err := c.do(ctx, "GET", "/api/system/status", url.Values{}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("c.do(...): want context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("c.do(...): want the pending request to return once the context is cancelled, took %s", elapsed)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+
+	// pending sends a request that is held by the server until it is
+	// released or cancelled.
+	pending := func(ctx context.Context) chan error {
+		returned := make(chan error, 1)
+		go func() {
+			returned <- NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: srv.URL}).do(ctx, "GET", "/api/system/status", url.Values{}, nil)
+		}()
+		<-received
+		return returned
+	}
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		returned := pending(ctx)
+
+		drained := make(chan error, 1)
+		go func() { drained <- drain(time.Second) }()
+		select {
+		case err := <-drained:
+			t.Fatalf("drain(...): returned while a request was in flight: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// The manager cancels the requests in flight when it stops.
+		cancel()
+		if err := <-returned; !errors.Is(err, context.Canceled) {
+			t.Errorf("c.do(...): want context.Canceled, got %v", err)
+		}
+		select {
+		case err := <-drained:
+			if err != nil {
+				t.Errorf("drain(...): unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("drain(...): did not return once the pending request returned")
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		returned := pending(context.Background())
+
+		if err := drain(50 * time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("drain(...): want context.DeadlineExceeded for a request still in flight, got %v", err)
+		}
+
+		// The request completes once the server answers it.
+		release <- struct{}{}
+		if err := <-returned; err != nil {
+			t.Errorf("c.do(...): unexpected error: %v", err)
+		}
+	})
+}
+
+func TestHttpClientEvicted(t *testing.T) {
+	cache := &httpClientCache{clients: map[string]*cachedClient{}}
+	first, err := cache.get(SonarApiOptions{Timeout: 1})
+	if err != nil {
+		t.Fatalf("cache.get(...): unexpected error: %v", err)
+	}
+	for i := 2; i <= MaxHttpClients+1; i++ {
+		if _, err := cache.get(SonarApiOptions{Timeout: time.Duration(i)}); err != nil {
+			t.Fatalf("cache.get(...): unexpected error: %v", err)
+		}
+	}
+
+	if got := len(cache.clients); got != MaxHttpClients {
+		t.Errorf("cache.get(...): want %d clients kept, got %d", MaxHttpClients, got)
+	}
+	again, err := cache.get(SonarApiOptions{Timeout: 1})
+	if err != nil {
+		t.Fatalf("cache.get(...): unexpected error: %v", err)
+	}
+	if again == first {
+		t.Errorf("cache.get(...): the least recently used client should have been dropped")
+	}
+}
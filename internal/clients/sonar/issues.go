@@ -0,0 +1,122 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Severities of an issue.
+const (
+	SeverityBlocker  = "BLOCKER"
+	SeverityCritical = "CRITICAL"
+	SeverityMajor    = "MAJOR"
+	SeverityMinor    = "MINOR"
+	SeverityInfo     = "INFO"
+)
+
+// Types of an issue.
+const (
+	IssueTypeBug           = "BUG"
+	IssueTypeVulnerability = "VULNERABILITY"
+	IssueTypeCodeSmell     = "CODE_SMELL"
+)
+
+// HotspotStatusToReview is the status of the security hotspots that were not
+// reviewed yet.
+const HotspotStatusToReview = "TO_REVIEW"
+
+type Issue struct {
+	Key       string `json:"key"`
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Component string `json:"component"`
+	Line      int    `json:"line,omitempty"`
+	Message   string `json:"message"`
+}
+
+type IssuePage struct {
+	Paging SonarPaging `json:"paging"`
+	Issues []Issue     `json:"issues"`
+}
+
+// IssueFilters select the issues returned by Search. Empty filters match
+// every issue.
+type IssueFilters struct {
+	Organization string
+	Severities   []string
+	Types        []string
+	// Resolved selects the resolved issues when true and the open issues
+	// when false. Both are returned when it is nil.
+	Resolved *bool
+	Page     int
+	PageSize int
+}
+
+type IssuesClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Issues Client
+func NewIssuesClient(options SonarApiOptions) IssuesClient {
+	return IssuesClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Search the issues of a project
+// https://sonarcloud.io/web_api/api/issues/search
+func (issuesClient IssuesClient) Search(ctx context.Context, project string, filters IssueFilters) (IssuePage, error) {
+	params := url.Values{}
+	issuesClient.sonarApi.addOrganization(params, filters.Organization)
+	params.Add("projects", project)
+	if len(filters.Severities) > 0 {
+		params.Add("severities", strings.Join(filters.Severities, ","))
+	}
+	if len(filters.Types) > 0 {
+		params.Add("types", strings.Join(filters.Types, ","))
+	}
+	if filters.Resolved != nil {
+		params.Add("resolved", strconv.FormatBool(*filters.Resolved))
+	}
+	if filters.Page > 0 {
+		params.Add("p", strconv.Itoa(filters.Page))
+	}
+	if filters.PageSize > 0 {
+		params.Add("ps", strconv.Itoa(filters.PageSize))
+	}
+
+	var issuePage IssuePage
+	err := issuesClient.sonarApi.do(ctx, "GET", "/api/issues/search", params, &issuePage)
+	return issuePage, err
+}
+
+// Count the issues of a project matched by filters, without fetching them.
+func (issuesClient IssuesClient) Count(ctx context.Context, project string, filters IssueFilters) (int, error) {
+	filters.Page = 0
+	filters.PageSize = 1
+
+	issuePage, err := issuesClient.Search(ctx, project, filters)
+	return issuePage.Paging.Total, err
+}
+
+// CountHotspots counts the security hotspots of a project with the supplied
+// status, e.g. HotspotStatusToReview.
+// https://sonarcloud.io/web_api/api/hotspots/search
+func (issuesClient IssuesClient) CountHotspots(ctx context.Context, project string, status string) (int, error) {
+	params := url.Values{}
+	params.Add("projectKey", project)
+	if status != "" {
+		params.Add("status", status)
+	}
+	params.Add("ps", "1")
+
+	var response struct {
+		Paging SonarPaging `json:"paging"`
+	}
+	err := issuesClient.sonarApi.do(ctx, "GET", "/api/hotspots/search", params, &response)
+	return response.Paging.Total, err
+}
@@ -0,0 +1,125 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSearchIssues(t *testing.T) {
+	response := `{
+		"paging": {"pageIndex": 1, "pageSize": 100, "total": 2},
+		"issues": [
+			{"key": "AX1", "rule": "go:S2068", "severity": "BLOCKER", "type": "VULNERABILITY", "status": "OPEN", "component": "key:main.go", "line": 12, "message": "Remove this hard-coded password."},
+			{"key": "AX2", "rule": "go:S1144", "severity": "CRITICAL", "type": "BUG", "status": "CONFIRMED", "component": "key:server.go", "message": "Remove this unused function."}
+		]
+	}`
+
+	want := IssuePage{
+		Paging: SonarPaging{PageIndex: 1, PageSize: 100, Total: 2},
+		Issues: []Issue{
+			{Key: "AX1", Rule: "go:S2068", Severity: SeverityBlocker, Type: IssueTypeVulnerability, Status: "OPEN", Component: "key:main.go", Line: 12, Message: "Remove this hard-coded password."},
+			{Key: "AX2", Rule: "go:S1144", Severity: SeverityCritical, Type: IssueTypeBug, Status: "CONFIRMED", Component: "key:server.go", Message: "Remove this unused function."},
+		},
+	}
+
+	resolved := false
+
+	cases := map[string]struct {
+		reason  string
+		filters IssueFilters
+		want    url.Values
+	}{
+		"NoFilters": {
+			reason: "Only the project should be sent without filters.",
+			want:   url.Values{"projects": {"key"}},
+		},
+		"OpenBlockers": {
+			reason:  "The severities and the resolution should be sent when filtering open blockers.",
+			filters: IssueFilters{Severities: []string{SeverityBlocker}, Resolved: &resolved},
+			want:    url.Values{"projects": {"key"}, "severities": {"BLOCKER"}, "resolved": {"false"}},
+		},
+		"Combined": {
+			reason:  "Every filter should be sent, with multiple values joined by commas.",
+			filters: IssueFilters{Organization: "org", Severities: []string{SeverityBlocker, SeverityCritical}, Types: []string{IssueTypeBug, IssueTypeVulnerability}, Page: 2, PageSize: 50},
+			want:    url.Values{"organization": {"org"}, "projects": {"key"}, "severities": {"BLOCKER,CRITICAL"}, "types": {"BUG,VULNERABILITY"}, "p": {"2"}, "ps": {"50"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				params = r.Form
+				_, _ = w.Write([]byte(response))
+			}))
+			defer srv.Close()
+
+			c := NewIssuesClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.Search(context.Background(), "key", tc.filters)
+			if err != nil {
+				t.Fatalf("\n%s\nc.Search(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, params); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCountIssues(t *testing.T) {
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":1,"total":7},"issues":[{"key":"AX1"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewIssuesClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Count(context.Background(), "key", IssueFilters{Severities: []string{SeverityBlocker}, Page: 3})
+	if err != nil {
+		t.Fatalf("c.Count(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(7, got); diff != "" {
+		t.Errorf("c.Count(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"projects": {"key"}, "severities": {"BLOCKER"}, "ps": {"1"}}, params); diff != "" {
+		t.Errorf("c.Count(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestCountHotspots(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":1,"total":3},"hotspots":[{"key":"HS1"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewIssuesClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.CountHotspots(context.Background(), "key", HotspotStatusToReview)
+	if err != nil {
+		t.Fatalf("c.CountHotspots(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(3, got); diff != "" {
+		t.Errorf("c.CountHotspots(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("/api/hotspots/search", path); diff != "" {
+		t.Errorf("c.CountHotspots(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"projectKey": {"key"}, "status": {"TO_REVIEW"}, "ps": {"1"}}, params); diff != "" {
+		t.Errorf("c.CountHotspots(...): -want params, +got params:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,55 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+type MeasuresClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Measures Client
+func NewMeasuresClient(options SonarApiOptions) MeasuresClient {
+	return MeasuresClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// GetMeasures returns the current values of the supplied metrics of a
+// project, keyed by metric. Metrics that have no value yet, e.g. because the
+// project was never analyzed, are left out.
+// https://sonarcloud.io/web_api/api/measures/component
+func (measuresClient MeasuresClient) GetMeasures(ctx context.Context, project string, metricKeys []string) (map[string]string, error) {
+	params := url.Values{}
+	params.Add("component", project)
+	params.Add("metricKeys", strings.Join(metricKeys, ","))
+
+	var response struct {
+		Component struct {
+			Measures []struct {
+				Metric string  `json:"metric"`
+				Value  *string `json:"value"`
+				// Metrics on new code only have a value for the period.
+				Period *struct {
+					Value string `json:"value"`
+				} `json:"period"`
+			} `json:"measures"`
+		} `json:"component"`
+	}
+	if err := measuresClient.sonarApi.do(ctx, "GET", "/api/measures/component", params, &response); err != nil {
+		return nil, err
+	}
+
+	measures := make(map[string]string, len(response.Component.Measures))
+	for _, m := range response.Component.Measures {
+		switch {
+		case m.Value != nil:
+			measures[m.Metric] = *m.Value
+		case m.Period != nil:
+			measures[m.Metric] = m.Period.Value
+		}
+	}
+	return measures, nil
+}
@@ -0,0 +1,51 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetMeasures(t *testing.T) {
+	// A sample response of a project that was analyzed, where code_smells was
+	// requested but has no value yet.
+	response := `{
+		"component": {
+			"key": "key",
+			"name": "Payments",
+			"qualifier": "TRK",
+			"measures": [
+				{"metric": "coverage", "value": "82.5", "bestValue": false},
+				{"metric": "bugs", "value": "0", "bestValue": true},
+				{"metric": "new_bugs", "period": {"index": 1, "value": "2", "bestValue": false}}
+			]
+		}
+	}`
+
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewMeasuresClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.GetMeasures(context.Background(), "key", []string{"coverage", "bugs", "new_bugs", "code_smells"})
+	if err != nil {
+		t.Fatalf("c.GetMeasures(...): unexpected error: %v", err)
+	}
+
+	want := map[string]string{"coverage": "82.5", "bugs": "0", "new_bugs": "2"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetMeasures(...): -want, +got:\n%s\n", diff)
+	}
+	wantParams := url.Values{"component": {"key"}, "metricKeys": {"coverage,bugs,new_bugs,code_smells"}}
+	if diff := cmp.Diff(wantParams, params); diff != "" {
+		t.Errorf("c.GetMeasures(...): -want params, +got params:\n%s\n", diff)
+	}
+}
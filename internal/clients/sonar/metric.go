@@ -0,0 +1,71 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Types of a metric.
+const (
+	MetricTypeInt          = "INT"
+	MetricTypeFloat        = "FLOAT"
+	MetricTypePercent      = "PERCENT"
+	MetricTypeBool         = "BOOL"
+	MetricTypeString       = "STRING"
+	MetricTypeMillisec     = "MILLISEC"
+	MetricTypeData         = "DATA"
+	MetricTypeLevel        = "LEVEL"
+	MetricTypeDistrib      = "DISTRIB"
+	MetricTypeRating       = "RATING"
+	MetricTypeWorkDuration = "WORK_DUR"
+)
+
+// Metric is a measure computed by the analysis, such as coverage, that
+// quality gate conditions and project measures refer to by key.
+type Metric struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Domain string `json:"domain,omitempty"`
+	// Direction is 1 when higher values are better, -1 when lower values are
+	// better, and 0 when neither is.
+	Direction int  `json:"direction"`
+	Hidden    bool `json:"hidden"`
+}
+
+type MetricsClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Metrics Client
+func NewMetricsClient(options SonarApiOptions) MetricsClient {
+	return MetricsClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Search returns every metric of the instance, across all pages
+// https://sonarcloud.io/web_api/api/metrics/search
+func (metricsClient MetricsClient) Search(ctx context.Context) ([]Metric, error) {
+	var metrics []Metric
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("p", strconv.Itoa(page))
+		params.Add("ps", strconv.Itoa(MaxPageSize))
+
+		// The paging of metrics is not nested in a paging object.
+		var response struct {
+			Metrics []Metric `json:"metrics"`
+			Total   int      `json:"total"`
+		}
+		if err := metricsClient.sonarApi.do(ctx, "GET", "/api/metrics/search", params, &response); err != nil {
+			return nil, err
+		}
+
+		metrics = append(metrics, response.Metrics...)
+		if len(response.Metrics) == 0 || len(metrics) >= response.Total {
+			return metrics, nil
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMetricsSearch(t *testing.T) {
+	// Sample pages of a catalog of three metrics, served two at a time.
+	pages := map[string]string{
+		"1": `{
+			"metrics": [
+				{"id": "1", "key": "coverage", "type": "PERCENT", "name": "Coverage", "domain": "Coverage", "direction": 1, "qualitative": true, "hidden": false},
+				{"id": "2", "key": "bugs", "type": "INT", "name": "Bugs", "domain": "Reliability", "direction": -1, "qualitative": false, "hidden": false}
+			],
+			"total": 3, "p": 1, "ps": 2
+		}`,
+		"2": `{
+			"metrics": [
+				{"id": "3", "key": "reliability_rating", "type": "RATING", "name": "Reliability Rating", "domain": "Reliability", "direction": -1, "qualitative": true, "hidden": false}
+			],
+			"total": 3, "p": 2, "ps": 2
+		}`,
+	}
+
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path != "/api/metrics/search" {
+			t.Errorf("c.Search(...): unexpected request %s", r.URL.Path)
+		}
+		requested = append(requested, r.Form.Encode())
+		_, _ = w.Write([]byte(pages[r.Form.Get("p")]))
+	}))
+	defer srv.Close()
+
+	c := NewMetricsClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Search(context.Background())
+	if err != nil {
+		t.Fatalf("c.Search(...): unexpected error: %v", err)
+	}
+
+	want := []Metric{
+		{Key: "coverage", Name: "Coverage", Type: MetricTypePercent, Domain: "Coverage", Direction: 1},
+		{Key: "bugs", Name: "Bugs", Type: MetricTypeInt, Domain: "Reliability", Direction: -1},
+		{Key: "reliability_rating", Name: "Reliability Rating", Type: MetricTypeRating, Domain: "Reliability", Direction: -1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Search(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"p=1&ps=500", "p=2&ps=500"}, requested); diff != "" {
+		t.Errorf("c.Search(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
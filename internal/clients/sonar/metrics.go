@@ -0,0 +1,46 @@
+package sonar
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// requestsTotal counts the requests sent to the Sonar API, including
+	// retries, by endpoint and status class.
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_sonar_requests_total",
+		Help: "Number of requests sent to the Sonar API, by endpoint and status class.",
+	}, []string{"endpoint", "status"})
+
+	// requestDuration observes the latency of the requests sent to the Sonar
+	// API, by endpoint.
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "provider_sonar_request_duration_seconds",
+		Help:    "Latency of the requests sent to the Sonar API, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration)
+}
+
+// recordRequest records a request to endpoint that took the supplied
+// duration. A statusCode of zero means no response was received.
+func recordRequest(endpoint string, statusCode int, duration time.Duration) {
+	requestsTotal.WithLabelValues(endpoint, statusClass(statusCode)).Inc()
+	requestDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// statusClass returns the class of statusCode, e.g. "2xx", or "error" when no
+// response was received.
+func statusClass(statusCode int) string {
+	if statusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
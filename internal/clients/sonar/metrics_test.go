@@ -0,0 +1,69 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		want   string
+	}{
+		"Success": {
+			reason: "A successful request should be counted in the 2xx class.",
+			status: http.StatusOK,
+			want:   "2xx",
+		},
+		"RateLimited": {
+			reason: "A rate limited request should be counted in the 4xx class.",
+			status: http.StatusTooManyRequests,
+			want:   "4xx",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			counter := requestsTotal.WithLabelValues("/api/projects/delete", tc.want)
+			before := testutil.ToFloat64(counter)
+
+			client := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, MaxAttempts: 1})
+			_ = client.Delete(context.Background(), "key")
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("\n%s\nDelete(...): want counter to increase by 1, got %v", tc.reason, got)
+			}
+		})
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+		want       string
+	}{
+		"NoResponse": {statusCode: 0, want: "error"},
+		"OK":         {statusCode: http.StatusOK, want: "2xx"},
+		"NotFound":   {statusCode: http.StatusNotFound, want: "4xx"},
+		"BadGateway": {statusCode: http.StatusBadGateway, want: "5xx"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := statusClass(tc.statusCode); got != tc.want {
+				t.Errorf("statusClass(%d): want %q, got %q", tc.statusCode, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package sonar
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Types of new code period.
+const (
+	NewCodePeriodPreviousVersion = "PREVIOUS_VERSION"
+	NewCodePeriodNumberOfDays    = "NUMBER_OF_DAYS"
+	NewCodePeriodReferenceBranch = "REFERENCE_BRANCH"
+)
+
+// NewCodePeriod defines which code of a project is considered new.
+type NewCodePeriod struct {
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+	// Inherited is true when the project has no period of its own and uses
+	// the one of its organization or instance.
+	Inherited bool `json:"inherited,omitempty"`
+}
+
+// Validate checks the type of a new code period, and that its value suits
+// the type.
+func (period NewCodePeriod) Validate() error {
+	switch period.Type {
+	case NewCodePeriodPreviousVersion:
+		if period.Value != "" {
+			return fmt.Errorf("new code period type %s takes no value", period.Type)
+		}
+	case NewCodePeriodNumberOfDays:
+		if days, err := strconv.Atoi(period.Value); err != nil || days <= 0 {
+			return fmt.Errorf("new code period type %s requires a positive number of days, got %q", period.Type, period.Value)
+		}
+	case NewCodePeriodReferenceBranch:
+		if period.Value == "" {
+			return fmt.Errorf("new code period type %s requires a branch", period.Type)
+		}
+	default:
+		return fmt.Errorf("unknown new code period type %q", period.Type)
+	}
+	return nil
+}
+
+// Set the new code period of a project
+// https://next.sonarqube.com/sonarqube/web_api/api/new_code_periods/set
+func (projectClient ProjectClient) SetNewCodePeriod(ctx context.Context, project string, period NewCodePeriod) error {
+	if err := period.Validate(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("type", period.Type)
+	if period.Value != "" {
+		params.Add("value", period.Value)
+	}
+
+	return projectClient.sonarApi.do(ctx, "POST", "/api/new_code_periods/set", params, nil)
+}
+
+// Get the new code period of a project
+// https://next.sonarqube.com/sonarqube/web_api/api/new_code_periods/show
+func (projectClient ProjectClient) GetNewCodePeriod(ctx context.Context, project string) (NewCodePeriod, error) {
+	params := url.Values{}
+	params.Add("project", project)
+
+	var period NewCodePeriod
+	err := projectClient.sonarApi.do(ctx, "GET", "/api/new_code_periods/show", params, &period)
+	return period, err
+}
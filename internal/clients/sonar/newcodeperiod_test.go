@@ -0,0 +1,96 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetNewCodePeriod(t *testing.T) {
+	type want struct {
+		params url.Values
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		period NewCodePeriod
+		want   want
+	}{
+		"PreviousVersion": {
+			reason: "A previous version period should be sent without a value.",
+			period: NewCodePeriod{Type: NewCodePeriodPreviousVersion},
+			want:   want{params: url.Values{"project": {"key"}, "type": {"PREVIOUS_VERSION"}}},
+		},
+		"PreviousVersionWithValue": {
+			reason: "A previous version period with a value should be rejected.",
+			period: NewCodePeriod{Type: NewCodePeriodPreviousVersion, Value: "1.0"},
+			want:   want{err: true},
+		},
+		"NumberOfDays": {
+			reason: "A number of days period should be sent with its number of days.",
+			period: NewCodePeriod{Type: NewCodePeriodNumberOfDays, Value: "30"},
+			want:   want{params: url.Values{"project": {"key"}, "type": {"NUMBER_OF_DAYS"}, "value": {"30"}}},
+		},
+		"NumberOfDaysNotANumber": {
+			reason: "A number of days period whose value is not a positive number should be rejected.",
+			period: NewCodePeriod{Type: NewCodePeriodNumberOfDays, Value: "thirty"},
+			want:   want{err: true},
+		},
+		"ReferenceBranch": {
+			reason: "A reference branch period should be sent with its branch.",
+			period: NewCodePeriod{Type: NewCodePeriodReferenceBranch, Value: "main"},
+			want:   want{params: url.Values{"project": {"key"}, "type": {"REFERENCE_BRANCH"}, "value": {"main"}}},
+		},
+		"ReferenceBranchWithoutBranch": {
+			reason: "A reference branch period without a branch should be rejected.",
+			period: NewCodePeriod{Type: NewCodePeriodReferenceBranch},
+			want:   want{err: true},
+		},
+		"UnknownType": {
+			reason: "An unknown type should be rejected before calling the API.",
+			period: NewCodePeriod{Type: "SPECIFIC_ANALYSIS", Value: "AXA1"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				params = r.Form
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := c.SetNewCodePeriod(context.Background(), "key", tc.period)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nc.SetNewCodePeriod(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\nc.SetNewCodePeriod(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetNewCodePeriod(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"projectKey":"key","type":"NUMBER_OF_DAYS","value":"30","inherited":false}`))
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.GetNewCodePeriod(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.GetNewCodePeriod(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(NewCodePeriod{Type: NewCodePeriodNumberOfDays, Value: "30"}, got); diff != "" {
+		t.Errorf("c.GetNewCodePeriod(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,217 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrOrganizationNotFound is returned when an organization does not exist.
+var ErrOrganizationNotFound = errors.New("Organization not found")
+
+// An Organization of SonarCloud. SonarQube has no organizations.
+type Organization struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// ProjectVisibility is the visibility of the projects created in the
+	// organization when none is given, public or private.
+	ProjectVisibility string `json:"projectVisibility"`
+}
+
+type OrganizationClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Organization Client
+func NewOrganizationClient(options SonarApiOptions) OrganizationClient {
+	return OrganizationClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// requireOrganizations returns ErrNoOrganizations for InstanceModeSonarQube.
+func (organizationClient OrganizationClient) requireOrganizations() error {
+	if organizationClient.sonarApi.Options.InstanceMode == InstanceModeSonarQube {
+		return ErrNoOrganizations
+	}
+	return nil
+}
+
+// Get an organization by its key
+// https://sonarcloud.io/web_api/api/navigation/organization
+func (organizationClient OrganizationClient) Get(ctx context.Context, organization string) (Organization, error) {
+	if err := organizationClient.requireOrganizations(); err != nil {
+		return Organization{}, err
+	}
+
+	params := url.Values{}
+	params.Add("organization", organization)
+
+	var response struct {
+		Organization Organization `json:"organization"`
+	}
+	err := organizationClient.sonarApi.do(ctx, "GET", "/api/navigation/organization", params, &response)
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return Organization{}, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return Organization{}, err
+	}
+
+	// The key is not part of the response.
+	response.Organization.Key = organization
+	return response.Organization, nil
+}
+
+// Search the organizations the authenticated user is a member of, e.g. to
+// check which organizations a token can access
+// https://sonarcloud.io/web_api/api/organizations/search
+func (organizationClient OrganizationClient) Search(ctx context.Context) ([]Organization, error) {
+	if err := organizationClient.requireOrganizations(); err != nil {
+		return nil, err
+	}
+
+	var organizations []Organization
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("member", "true")
+		params.Add("p", strconv.Itoa(page))
+		params.Add("ps", strconv.Itoa(MaxPageSize))
+
+		var response struct {
+			Paging        SonarPaging    `json:"paging"`
+			Organizations []Organization `json:"organizations"`
+		}
+		if err := organizationClient.sonarApi.do(ctx, "GET", "/api/organizations/search", params, &response); err != nil {
+			return nil, err
+		}
+
+		organizations = append(organizations, response.Organizations...)
+		if len(response.Organizations) == 0 || len(organizations) >= response.Paging.Total {
+			return organizations, nil
+		}
+	}
+}
+
+// SetDefaultVisibility sets the visibility of the projects created in an
+// organization when none is given, public or private.
+// https://sonarcloud.io/web_api/api/organizations/update_project_visibility
+func (organizationClient OrganizationClient) SetDefaultVisibility(ctx context.Context, organization string, visibility string) error {
+	if err := organizationClient.requireOrganizations(); err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	organizationClient.sonarApi.addOrganization(params, organization)
+	params.Add("projectVisibility", visibility)
+
+	return organizationClient.sonarApi.do(ctx, "POST", "/api/organizations/update_project_visibility", params, nil)
+}
+
+// DefaultVisibilityConcurrency is the number of projects whose visibility
+// UpdateVisibilityOfProjects changes at once when no concurrency is given.
+const DefaultVisibilityConcurrency = 4
+
+// A VisibilityUpdateSummary reports the outcome of changing the visibility of
+// every project of an organization. Keys are sorted.
+type VisibilityUpdateSummary struct {
+	// Updated are the keys of the projects whose visibility was changed.
+	Updated []string
+	// Unchanged are the keys of the projects that already had the visibility.
+	Unchanged []string
+	// Failed maps the key of every project whose visibility could not be
+	// changed to the error returned.
+	Failed map[string]error
+}
+
+// Err returns an error listing the projects whose visibility could not be
+// changed, or nil if there are none.
+func (s VisibilityUpdateSummary) Err() error {
+	if len(s.Failed) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(s.Failed))
+	for k := range s.Failed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", k, s.Failed[k]))
+	}
+	return fmt.Errorf("cannot update the visibility of %d of %d projects: %s", len(s.Failed), len(s.Updated)+len(s.Unchanged)+len(s.Failed), strings.Join(msgs, "; "))
+}
+
+// UpdateVisibilityOfProjects sets the visibility of every existing project of
+// an organization, e.g. to make them all private. SetDefaultVisibility only
+// applies to the projects created later. At most concurrency projects are
+// updated at once, DefaultVisibilityConcurrency when it is not positive. A
+// project that fails does not stop the others: failures are reported in the
+// summary, and an error is only returned when the projects cannot be listed.
+func (organizationClient OrganizationClient) UpdateVisibilityOfProjects(ctx context.Context, organization string, visibility string, concurrency int) (VisibilityUpdateSummary, error) {
+	// Every project of a SonarQube instance would be updated.
+	if err := organizationClient.requireOrganizations(); err != nil {
+		return VisibilityUpdateSummary{}, err
+	}
+	if err := ValidateVisibility(visibility); err != nil {
+		return VisibilityUpdateSummary{}, err
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultVisibilityConcurrency
+	}
+
+	projectClient := ProjectClient{sonarApi: organizationClient.sonarApi}
+	projects, err := projectClient.SearchAll(ctx, organization, SearchOptions{})
+	if err != nil {
+		return VisibilityUpdateSummary{}, err
+	}
+
+	summary := VisibilityUpdateSummary{Failed: map[string]error{}}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, p := range projects {
+		if p.Visibility == visibility {
+			summary.Unchanged = append(summary.Unchanged, p.Key)
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := ctx.Err()
+			if err == nil {
+				err = projectClient.UpdateVisibility(ctx, key, visibility)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed[key] = err
+				return
+			}
+			summary.Updated = append(summary.Updated, key)
+		}(p.Key)
+	}
+	wg.Wait()
+
+	sort.Strings(summary.Updated)
+	sort.Strings(summary.Unchanged)
+	return summary, nil
+}
@@ -0,0 +1,273 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGetOrganization(t *testing.T) {
+	type want struct {
+		organization Organization
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+		body   string
+		want   want
+	}{
+		"Found": {
+			reason: "The organization should be returned with its key and default project visibility.",
+			status: http.StatusOK,
+			body:   `{"organization":{"name":"GB Sandbox","projectVisibility":"private","canAdmin":true}}`,
+			want:   want{organization: Organization{Key: "gbsandbox", Name: "GB Sandbox", ProjectVisibility: "private"}},
+		},
+		"NotFound": {
+			reason: "A missing organization should be reported as not found.",
+			status: http.StatusNotFound,
+			body:   `{"errors":[{"msg":"No organization for key 'gbsandbox'"}]}`,
+			want:   want{err: ErrOrganizationNotFound},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				params = r.Form
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.Get(context.Background(), "gbsandbox")
+			if !errors.Is(err, tc.want.err) {
+				t.Fatalf("\n%s\nc.Get(...): want error %v, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.organization, got); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(url.Values{"organization": {"gbsandbox"}}, params); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSearchOrganizations(t *testing.T) {
+	pages := []string{
+		`{"paging":{"pageIndex":1,"pageSize":1,"total":2},"organizations":[{"key":"gbsandbox","name":"GB Sandbox","description":"Sandbox","isDefault":false}]}`,
+		`{"paging":{"pageIndex":2,"pageSize":1,"total":2},"organizations":[{"key":"payments","name":"Payments"}]}`,
+	}
+
+	var requested []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requested = append(requested, r.Form)
+		p, _ := strconv.Atoi(r.Form.Get("p"))
+		_, _ = w.Write([]byte(pages[p-1]))
+	}))
+	defer srv.Close()
+
+	c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Search(context.Background())
+	if err != nil {
+		t.Fatalf("c.Search(...): unexpected error: %v", err)
+	}
+
+	want := []Organization{{Key: "gbsandbox", Name: "GB Sandbox"}, {Key: "payments", Name: "Payments"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Search(...): -want, +got:\n%s\n", diff)
+	}
+	wantRequested := []url.Values{
+		{"member": {"true"}, "p": {"1"}, "ps": {"500"}},
+		{"member": {"true"}, "p": {"2"}, "ps": {"500"}},
+	}
+	if diff := cmp.Diff(wantRequested, requested); diff != "" {
+		t.Errorf("c.Search(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestOrganizationsOnSonarQube(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, c OrganizationClient) error
+	}{
+		"Get": {
+			reason: "Getting an organization of SonarQube should fail without a request.",
+			call: func(ctx context.Context, c OrganizationClient) error {
+				_, err := c.Get(ctx, "gbsandbox")
+				return err
+			},
+		},
+		"Search": {
+			reason: "Searching the organizations of SonarQube should fail without a request.",
+			call: func(ctx context.Context, c OrganizationClient) error {
+				_, err := c.Search(ctx)
+				return err
+			},
+		},
+		"SetDefaultVisibility": {
+			reason: "Setting the default visibility of an organization of SonarQube should fail without a request.",
+			call: func(ctx context.Context, c OrganizationClient) error {
+				return c.SetDefaultVisibility(ctx, "gbsandbox", "private")
+			},
+		},
+		"UpdateVisibilityOfProjects": {
+			reason: "Updating the projects of an organization of SonarQube should fail without a request, rather than update every project of the instance.",
+			call: func(ctx context.Context, c OrganizationClient) error {
+				_, err := c.UpdateVisibilityOfProjects(ctx, "gbsandbox", "private", 0)
+				return err
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+			}))
+			defer srv.Close()
+
+			c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, InstanceMode: InstanceModeSonarQube})
+			if err := tc.call(context.Background(), c); !errors.Is(err, ErrNoOrganizations) {
+				t.Errorf("\n%s\nc.%s(...): want ErrNoOrganizations, got %v", tc.reason, name, err)
+			}
+			if requests != 0 {
+				t.Errorf("\n%s\nc.%s(...): want no request, got %d", tc.reason, name, requests)
+			}
+		})
+	}
+}
+
+func TestSetDefaultVisibility(t *testing.T) {
+	visibility := "public"
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/api/organizations/update_project_visibility":
+			params = r.Form
+			visibility = params.Get("projectVisibility")
+			w.WriteHeader(http.StatusNoContent)
+		case "/api/navigation/organization":
+			_, _ = w.Write([]byte(`{"organization":{"name":"GB Sandbox","projectVisibility":"` + visibility + `"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.SetDefaultVisibility(context.Background(), "gbsandbox", "private"); err != nil {
+		t.Fatalf("c.SetDefaultVisibility(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(url.Values{"organization": {"gbsandbox"}, "projectVisibility": {"private"}}, params); diff != "" {
+		t.Errorf("c.SetDefaultVisibility(...): -want params, +got params:\n%s\n", diff)
+	}
+
+	got, err := c.Get(context.Background(), "gbsandbox")
+	if err != nil {
+		t.Fatalf("c.Get(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("private", got.ProjectVisibility); diff != "" {
+		t.Errorf("c.Get(...): -want visibility, +got visibility:\n%s\n", diff)
+	}
+}
+
+func TestUpdateVisibilityOfProjects(t *testing.T) {
+	projects := []Project{
+		{Key: "a", Visibility: "public"},
+		{Key: "b", Visibility: "private"},
+		{Key: "c", Visibility: "public"},
+		{Key: "d", Visibility: "public"},
+		{Key: "e", Visibility: "public"},
+	}
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/search":
+			_ = json.NewEncoder(w).Encode(ProjectPage{Paging: SonarPaging{PageIndex: 1, PageSize: 500, Total: len(projects)}, Projects: projects})
+		case "/api/projects/update_visibility":
+			mu.Lock()
+			inFlight++
+			if inFlight > maxSeen {
+				maxSeen = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			if key := r.FormValue("project"); key == "c" || key == "e" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors":[{"msg":"Project '` + key + `' has pull requests"}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.UpdateVisibilityOfProjects(context.Background(), "gbsandbox", "private", 2)
+	if err != nil {
+		t.Fatalf("c.UpdateVisibilityOfProjects(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"a", "d"}, got.Updated); diff != "" {
+		t.Errorf("c.UpdateVisibilityOfProjects(...): -want updated, +got updated:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"b"}, got.Unchanged); diff != "" {
+		t.Errorf("c.UpdateVisibilityOfProjects(...): -want unchanged, +got unchanged:\n%s\n", diff)
+	}
+	failed := make([]string, 0, len(got.Failed))
+	for key, err := range got.Failed {
+		var apiErr *SonarAPIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+			t.Errorf("c.UpdateVisibilityOfProjects(...): want a *SonarAPIError for %s, got %v", key, err)
+		}
+		failed = append(failed, key)
+	}
+	if diff := cmp.Diff([]string{"c", "e"}, failed, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("c.UpdateVisibilityOfProjects(...): -want failed, +got failed:\n%s\n", diff)
+	}
+	if err := got.Err(); err == nil || !strings.Contains(err.Error(), "2 of 5 projects") {
+		t.Errorf("summary.Err(): want an error for 2 of 5 projects, got %v", err)
+	}
+	if maxSeen > 2 {
+		t.Errorf("c.UpdateVisibilityOfProjects(...): want at most 2 concurrent updates, got %d", maxSeen)
+	}
+}
+
+func TestUpdateVisibilityOfProjectsSearchFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	c := NewOrganizationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.UpdateVisibilityOfProjects(context.Background(), "gbsandbox", "private", 0); err == nil {
+		t.Errorf("c.UpdateVisibilityOfProjects(...): want an error when the projects cannot be listed")
+	}
+}
@@ -0,0 +1,197 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+var ErrPermissionTemplateNotFound = errors.New("Permission template not found")
+
+// templateGroupsMaxPageSize is the largest page size accepted by the
+// template_groups endpoint, which is lower than MaxPageSize.
+const templateGroupsMaxPageSize = 100
+
+type PermissionTemplate struct {
+	Id                string `json:"id"`
+	Name              string `json:"name"`
+	Description       string `json:"description,omitempty"`
+	ProjectKeyPattern string `json:"projectKeyPattern,omitempty"`
+}
+
+// TemplateGroup is a group and the permissions it is granted by a permission
+// template.
+type TemplateGroup struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type PermissionTemplateClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Permission Template Client
+func NewPermissionTemplateClient(options SonarApiOptions) PermissionTemplateClient {
+	return PermissionTemplateClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// templateParams returns the parameters describing a permission template.
+// Optional fields are only sent when set.
+func (permissionTemplateClient PermissionTemplateClient) templateParams(organization string, template PermissionTemplate) url.Values {
+	params := url.Values{}
+	permissionTemplateClient.sonarApi.addOrganization(params, organization)
+	params.Add("name", template.Name)
+	if template.Description != "" {
+		params.Add("description", template.Description)
+	}
+	if template.ProjectKeyPattern != "" {
+		params.Add("projectKeyPattern", template.ProjectKeyPattern)
+	}
+	return params
+}
+
+// Create new permission template
+// https://sonarcloud.io/web_api/api/permissions/create_template
+func (permissionTemplateClient PermissionTemplateClient) Create(ctx context.Context, organization string, template PermissionTemplate) (PermissionTemplate, error) {
+	var response struct {
+		PermissionTemplate PermissionTemplate `json:"permissionTemplate"`
+	}
+	err := permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/create_template", permissionTemplateClient.templateParams(organization, template), &response)
+	return response.PermissionTemplate, err
+}
+
+// Update a permission template, identified by its id
+// https://sonarcloud.io/web_api/api/permissions/update_template
+func (permissionTemplateClient PermissionTemplateClient) Update(ctx context.Context, organization string, template PermissionTemplate) error {
+	params := permissionTemplateClient.templateParams(organization, template)
+	params.Del("organization")
+	params.Add("id", template.Id)
+
+	return permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/update_template", params, nil)
+}
+
+// Delete permission template, identified by its id
+// https://sonarcloud.io/web_api/api/permissions/delete_template
+func (permissionTemplateClient PermissionTemplateClient) Delete(ctx context.Context, organization string, id string) error {
+	params := url.Values{}
+	permissionTemplateClient.sonarApi.addOrganization(params, organization)
+	params.Add("templateId", id)
+
+	return permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/delete_template", params, nil)
+}
+
+// Search the permission templates of an organization by name
+// https://sonarcloud.io/web_api/api/permissions/search_templates
+func (permissionTemplateClient PermissionTemplateClient) Search(ctx context.Context, organization string, query string) ([]PermissionTemplate, error) {
+	params := url.Values{}
+	permissionTemplateClient.sonarApi.addOrganization(params, organization)
+	params.Add("q", query)
+
+	var response struct {
+		PermissionTemplates []PermissionTemplate `json:"permissionTemplates"`
+	}
+	if err := permissionTemplateClient.sonarApi.do(ctx, "GET", "/api/permissions/search_templates", params, &response); err != nil {
+		return nil, err
+	}
+	return response.PermissionTemplates, nil
+}
+
+// Get a single permission template by name. Search matches names partially,
+// so only an exact match is returned.
+func (permissionTemplateClient PermissionTemplateClient) GetByName(ctx context.Context, organization string, name string) (PermissionTemplate, error) {
+	templates, err := permissionTemplateClient.Search(ctx, organization, name)
+	if err != nil {
+		return PermissionTemplate{}, err
+	}
+
+	for _, template := range templates {
+		if template.Name == name {
+			return template, nil
+		}
+	}
+
+	return PermissionTemplate{}, ErrPermissionTemplateNotFound
+}
+
+// Groups returns every group granted at least one permission by a permission
+// template, with the permissions it is granted
+// https://sonarcloud.io/web_api/api/permissions/template_groups
+func (permissionTemplateClient PermissionTemplateClient) Groups(ctx context.Context, organization string, id string) ([]TemplateGroup, error) {
+	var groups []TemplateGroup
+	for page := 1; ; page++ {
+		params := url.Values{}
+		permissionTemplateClient.sonarApi.addOrganization(params, organization)
+		params.Add("templateId", id)
+		params.Add("p", strconv.Itoa(page))
+		params.Add("ps", strconv.Itoa(templateGroupsMaxPageSize))
+
+		var response struct {
+			Paging SonarPaging     `json:"paging"`
+			Groups []TemplateGroup `json:"groups"`
+		}
+		if err := permissionTemplateClient.sonarApi.do(ctx, "GET", "/api/permissions/template_groups", params, &response); err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, response.Groups...)
+		if len(response.Groups) == 0 || len(groups) >= response.Paging.Total {
+			return groups, nil
+		}
+	}
+}
+
+// Grant a permission to a group in a permission template
+// https://sonarcloud.io/web_api/api/permissions/add_group_to_template
+func (permissionTemplateClient PermissionTemplateClient) AddGroup(ctx context.Context, organization string, id string, group string, permission string) error {
+	return permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/add_group_to_template", permissionTemplateClient.groupPermissionParams(organization, id, group, permission), nil)
+}
+
+// Revoke a permission from a group in a permission template
+// https://sonarcloud.io/web_api/api/permissions/remove_group_from_template
+func (permissionTemplateClient PermissionTemplateClient) RemoveGroup(ctx context.Context, organization string, id string, group string, permission string) error {
+	return permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/remove_group_from_template", permissionTemplateClient.groupPermissionParams(organization, id, group, permission), nil)
+}
+
+// Apply a permission template to a project, replacing its permissions
+// https://sonarcloud.io/web_api/api/permissions/apply_template
+func (permissionTemplateClient PermissionTemplateClient) ApplyToProject(ctx context.Context, organization string, id string, project string) error {
+	params := url.Values{}
+	permissionTemplateClient.sonarApi.addOrganization(params, organization)
+	params.Add("templateId", id)
+	params.Add("projectKey", project)
+
+	return permissionTemplateClient.sonarApi.do(ctx, "POST", "/api/permissions/apply_template", params, nil)
+}
+
+// MatchProjectKey returns the first of the supplied templates whose project
+// key pattern matches the whole project key. This is the template applied to
+// a project when it is created. A pattern that is not a valid expression
+// matches nothing.
+func MatchProjectKey(templates []PermissionTemplate, project string) (PermissionTemplate, bool) {
+	for _, template := range templates {
+		if template.ProjectKeyPattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + template.ProjectKeyPattern + ")$")
+		if err != nil {
+			continue
+		}
+		if re.MatchString(project) {
+			return template, true
+		}
+	}
+	return PermissionTemplate{}, false
+}
+
+func (permissionTemplateClient PermissionTemplateClient) groupPermissionParams(organization string, id string, group string, permission string) url.Values {
+	params := url.Values{}
+	permissionTemplateClient.sonarApi.addOrganization(params, organization)
+	params.Add("templateId", id)
+	params.Add("groupName", group)
+	params.Add("permission", permission)
+	return params
+}
@@ -0,0 +1,120 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPermissionTemplateGroups(t *testing.T) {
+	pages := [][]TemplateGroup{
+		{{Name: "developers", Permissions: []string{"user"}}},
+		{{Name: "leads", Permissions: []string{"admin", "user"}}},
+	}
+
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.FormValue("templateId")+"/"+r.FormValue("p")+"/"+r.FormValue("ps"))
+		p, _ := strconv.Atoi(r.FormValue("p"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"paging": SonarPaging{PageIndex: p, PageSize: 1, Total: 2},
+			"groups": pages[p-1],
+		})
+	}))
+	defer srv.Close()
+
+	c := NewPermissionTemplateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Groups(context.Background(), "org", "AXT1")
+	if err != nil {
+		t.Fatalf("c.Groups(...): unexpected error: %v", err)
+	}
+
+	want := []TemplateGroup{
+		{Name: "developers", Permissions: []string{"user"}},
+		{Name: "leads", Permissions: []string{"admin", "user"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Groups(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"AXT1/1/100", "AXT1/2/100"}, requested); diff != "" {
+		t.Errorf("c.Groups(...): -want pages requested, +got pages requested:\n%s\n", diff)
+	}
+}
+
+func TestPermissionTemplateGetByNameNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"permissionTemplates":[{"id":"AXT2","name":"payments-legacy"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewPermissionTemplateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.GetByName(context.Background(), "org", "payments"); err != ErrPermissionTemplateNotFound {
+		t.Errorf("GetByName(...): want ErrPermissionTemplateNotFound, got %v", err)
+	}
+}
+
+func TestMatchProjectKey(t *testing.T) {
+	templates := []PermissionTemplate{
+		{Id: "AXT0", Name: "default"},
+		{Id: "AXT1", Name: "invalid", ProjectKeyPattern: "payments-("},
+		{Id: "AXT2", Name: "payments", ProjectKeyPattern: "payments-.*"},
+		{Id: "AXT3", Name: "all", ProjectKeyPattern: ".*"},
+	}
+
+	cases := map[string]struct {
+		reason  string
+		project string
+		want    string
+	}{
+		"FirstMatch": {
+			reason:  "The first template whose pattern matches the key should be returned.",
+			project: "payments-api",
+			want:    "AXT2",
+		},
+		"WholeKey": {
+			reason:  "A pattern should only match the whole key, not part of it.",
+			project: "legacy-payments-api",
+			want:    "AXT3",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := MatchProjectKey(templates, tc.project)
+			if !ok {
+				t.Fatalf("\n%s\nMatchProjectKey(...): want a match", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want, got.Id); diff != "" {
+				t.Errorf("\n%s\nMatchProjectKey(...): -want template, +got template:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	if _, ok := MatchProjectKey(templates[:3], "checkout"); ok {
+		t.Errorf("MatchProjectKey(...): want no match for a key no pattern matches")
+	}
+}
+
+func TestPermissionTemplateApplyToProject(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		got = r.Method + " " + r.URL.Path + " " + r.Form.Encode()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewPermissionTemplateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.ApplyToProject(context.Background(), "org", "AXT2", "payments-api"); err != nil {
+		t.Fatalf("c.ApplyToProject(...): unexpected error: %v", err)
+	}
+	want := "POST /api/permissions/apply_template organization=org&projectKey=payments-api&templateId=AXT2"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.ApplyToProject(...): -want request, +got request:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,113 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+var ErrPortfolioNotFound = errors.New("Portfolio not found")
+
+type Portfolio struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"desc,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+	// Projects selected manually into the portfolio.
+	Projects []string `json:"selectedProjects,omitempty"`
+}
+
+// PortfolioClient manages portfolios, which only exist on the Enterprise
+// edition. Its methods return ErrUnsupportedEdition on other editions.
+type PortfolioClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Portfolio Client
+func NewPortfolioClient(options SonarApiOptions) PortfolioClient {
+	return PortfolioClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// do calls a views endpoint, reporting a missing endpoint as
+// ErrUnsupportedEdition along with the edition of the instance.
+func (portfolioClient PortfolioClient) do(ctx context.Context, method string, path string, params url.Values, out any) error {
+	err := portfolioClient.sonarApi.do(ctx, method, path, params, out)
+	if isUnknownUrl(err) {
+		return unsupportedEdition(ctx, portfolioClient.sonarApi, "portfolios", EditionEnterprise)
+	}
+	return err
+}
+
+// Create new portfolio
+// https://next.sonarqube.com/sonarqube/web_api/api/views/create
+func (portfolioClient PortfolioClient) Create(ctx context.Context, portfolio Portfolio) error {
+	params := url.Values{}
+	params.Add("key", portfolio.Key)
+	params.Add("name", portfolio.Name)
+	if portfolio.Description != "" {
+		params.Add("description", portfolio.Description)
+	}
+	if portfolio.Visibility != "" {
+		params.Add("visibility", portfolio.Visibility)
+	}
+
+	return portfolioClient.do(ctx, "POST", "/api/views/create", params, nil)
+}
+
+// Update the name and description of a portfolio
+// https://next.sonarqube.com/sonarqube/web_api/api/views/update
+func (portfolioClient PortfolioClient) Update(ctx context.Context, portfolio Portfolio) error {
+	params := url.Values{}
+	params.Add("key", portfolio.Key)
+	params.Add("name", portfolio.Name)
+	params.Add("description", portfolio.Description)
+
+	return portfolioClient.do(ctx, "POST", "/api/views/update", params, nil)
+}
+
+// Delete portfolio
+// https://next.sonarqube.com/sonarqube/web_api/api/views/delete
+func (portfolioClient PortfolioClient) Delete(ctx context.Context, key string) error {
+	params := url.Values{}
+	params.Add("key", key)
+
+	return portfolioClient.do(ctx, "POST", "/api/views/delete", params, nil)
+}
+
+// Get a portfolio by key
+// https://next.sonarqube.com/sonarqube/web_api/api/views/show
+func (portfolioClient PortfolioClient) Get(ctx context.Context, key string) (Portfolio, error) {
+	params := url.Values{}
+	params.Add("key", key)
+
+	var portfolio Portfolio
+	err := portfolioClient.do(ctx, "GET", "/api/views/show", params, &portfolio)
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return Portfolio{}, ErrPortfolioNotFound
+	}
+	return portfolio, err
+}
+
+// Add a project to a portfolio
+// https://next.sonarqube.com/sonarqube/web_api/api/views/add_project
+func (portfolioClient PortfolioClient) AddProject(ctx context.Context, key string, project string) error {
+	params := url.Values{}
+	params.Add("key", key)
+	params.Add("project", project)
+
+	return portfolioClient.do(ctx, "POST", "/api/views/add_project", params, nil)
+}
+
+// Remove a project from a portfolio
+// https://next.sonarqube.com/sonarqube/web_api/api/views/remove_project
+func (portfolioClient PortfolioClient) RemoveProject(ctx context.Context, key string, project string) error {
+	params := url.Values{}
+	params.Add("key", key)
+	params.Add("project", project)
+
+	return portfolioClient.do(ctx, "POST", "/api/views/remove_project", params, nil)
+}
@@ -0,0 +1,43 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPortfolioUnsupportedEdition(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Unknown url : ` + r.URL.Path + `"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewPortfolioClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+
+	_, err := c.Get(context.Background(), "payments")
+	if !errors.Is(err, ErrUnsupportedEdition) {
+		t.Errorf("c.Get(...): want ErrUnsupportedEdition, got %v", err)
+	}
+	if errors.Is(err, ErrPortfolioNotFound) {
+		t.Errorf("c.Get(...): a missing endpoint must not be reported as ErrPortfolioNotFound")
+	}
+	if err := c.Create(context.Background(), Portfolio{Key: "payments", Name: "Payments"}); !errors.Is(err, ErrUnsupportedEdition) {
+		t.Errorf("c.Create(...): want ErrUnsupportedEdition, got %v", err)
+	}
+}
+
+func TestPortfolioGetNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Portfolio 'payments' not found"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewPortfolioClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.Get(context.Background(), "payments"); !errors.Is(err, ErrPortfolioNotFound) {
+		t.Errorf("c.Get(...): want ErrPortfolioNotFound, got %v", err)
+	}
+}
@@ -2,35 +2,106 @@ package sonar
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
-	"strconv"
+	"net/url"
 	"strings"
+	"time"
 )
 
 var ErrProjectNotFound = errors.New("Project not found")
 
+// ErrInsufficientPermissions is returned when the token is not allowed to see
+// a project, which must not be mistaken for the project not existing.
+var ErrInsufficientPermissions = errors.New("Insufficient permissions")
+
+// ErrBulkDeleteNotConfirmed is returned by BulkDelete when the deletion was
+// not confirmed.
+var ErrBulkDeleteNotConfirmed = errors.New("Bulk delete not confirmed")
+
 type Project struct {
-	Organization string `json:"organization"`
-	Key          string `json:"key"`
-	Name         string `json:"name"`
-	Qualifier    string `json:"qualifier"`
-	Visibility   string `json:"visibility"`
-	// TODO: Custom Unmarshal for Time format: 2022-11-10T19:33:53+0100
-	// https://eli.thegreenplace.net/2020/unmarshaling-time-values-from-json/
-	LastAnalysisDate string `json:"lastAnalysisDate,omitempty"`
-	Revision         string `json:"revision"`
+	Organization     string    `json:"organization"`
+	Key              string    `json:"key"`
+	Name             string    `json:"name"`
+	Qualifier        string    `json:"qualifier"`
+	Visibility       string    `json:"visibility"`
+	LastAnalysisDate SonarTime `json:"lastAnalysisDate,omitempty"`
+	Revision         string    `json:"revision"`
+	Tags             []string  `json:"tags,omitempty"`
+}
+
+// Visibilities of a project.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+// ValidateVisibility checks that visibility is public or private.
+func ValidateVisibility(visibility string) error {
+	if visibility != VisibilityPublic && visibility != VisibilityPrivate {
+		return fmt.Errorf("visibility %q must be %s or %s", visibility, VisibilityPublic, VisibilityPrivate)
+	}
+	return nil
+}
+
+// MaxProjectKeyLength is the longest key a project can have.
+const MaxProjectKeyLength = 400
+
+// ValidateProjectKey checks that key is a valid project key: at most
+// MaxProjectKeyLength letters, digits, '-', '_', '.' and ':', at least one of
+// which is not a digit.
+func ValidateProjectKey(key string) error {
+	if key == "" {
+		return errors.New("project key must not be empty")
+	}
+	if len(key) > MaxProjectKeyLength {
+		return fmt.Errorf("project key is %d characters long, longer than the maximum of %d", len(key), MaxProjectKeyLength)
+	}
+
+	digits := true
+	for _, r := range key {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-', r == '_', r == '.', r == ':':
+			digits = false
+		default:
+			return fmt.Errorf("project key %q contains %q, but may only contain letters, digits, '-', '_', '.' and ':'", key, r)
+		}
+	}
+	if digits {
+		return fmt.Errorf("project key %q must contain at least one character that is not a digit", key)
+	}
+	return nil
 }
 
 type ProjectPage struct {
 	Paging   SonarPaging `json:"paging"`
 	Projects []Project   `json:"components"`
+	// NextPageToken selects the next page of SearchV2. It is empty on the
+	// last page.
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// ProjectService manages the projects of an organization. ProjectClient
+// satisfies it, and package fake provides an implementation for tests.
+type ProjectService interface {
+	Create(ctx context.Context, organization string, name string, project string, visibility string, mainBranch string) (Project, bool, error)
+	Delete(ctx context.Context, project string) error
+	Search(ctx context.Context, organization string, options SearchOptions) (ProjectPage, error)
+	GetByProjectKey(ctx context.Context, organization string, project string) (Project, error)
+	GetProjectUrl(project string) (string, error)
+	UpdateVisibility(ctx context.Context, project string, visibility string) error
+	UpdateName(ctx context.Context, project string, name string) error
+	SetTags(ctx context.Context, project string, tags []string) error
+	GetBadgeToken(ctx context.Context, project string) (string, error)
+	RenewBadgeToken(ctx context.Context, project string) error
+	SetNewCodePeriod(ctx context.Context, project string, period NewCodePeriod) error
+	GetNewCodePeriod(ctx context.Context, project string) (NewCodePeriod, error)
 }
 
+var _ ProjectService = ProjectClient{}
+
 type ProjectClient struct {
 	sonarApi SonarApi
 }
@@ -42,133 +113,306 @@ func NewProjectClient(options SonarApiOptions) ProjectClient {
 	}
 }
 
+// Creates a new Project Client, returned as a ProjectService
+func NewProjectService(options SonarApiOptions) ProjectService {
+	return NewProjectClient(options)
+}
+
 type SearchOptions struct {
-	// List of project keys
+	// List of project keys. Search sends them all at once, SearchAll in
+	// batches of MaxProjectsPerSearch.
 	Projects []string
+	// Partial match on the name or key of the projects. It can be combined
+	// with Projects.
+	Query string
 	// 1-based page number
 	Page int
 	// Page size. Must be greater than 0 and less or equal than 500
 	PageSize int
+	// Token of the page returned by SearchV2, taken from the NextPageToken
+	// of the previous page. Empty for the first page.
+	PageToken string
+	// Only projects last analyzed before this time, or never analyzed
+	AnalyzedBefore time.Time
+	// Only projects that were provisioned but never analyzed
+	OnProvisionedOnly bool
+	// Qualifiers of the components, e.g. TRK for projects
+	Qualifiers []string
 }
 
-// Create new project
+// Create new project. The existing project is returned when the key is
+// already taken, and created reports whether the project is new. The main branch is named mainBranch, or gets the default name
+// of the organization or instance when mainBranch is empty.
 // https://sonarcloud.io/web_api/api/projects/create
-func (projectClient ProjectClient) Create(ctx context.Context, organization string, name string, project string, visibility string) (Project, error) {
+func (projectClient ProjectClient) Create(ctx context.Context, organization string, name string, project string, visibility string, mainBranch string) (Project, bool, error) {
+	if err := ValidateProjectKey(project); err != nil {
+		return Project{}, false, err
+	}
 
-	url := projectClient.sonarApi.GetUrl("/api/projects/create")
-	params := url.Query()
-	params.Add("organization", organization)
+	params := url.Values{}
+	projectClient.sonarApi.addOrganization(params, organization)
 	params.Add("name", name)
 	params.Add("project", project)
-	params.Add("visibility", visibility)
-
-	url.RawQuery = params.Encode()
-	client := &http.Client{}
-
-	req, err := projectClient.sonarApi.NewRequest(ctx, "POST", url.String(), nil)
-	if err != nil {
-		log.Fatal(err)
+	// The default visibility of the organization or instance applies when
+	// none is set.
+	if visibility != "" {
+		if err := ValidateVisibility(visibility); err != nil {
+			return Project{}, false, err
+		}
+		params.Add("visibility", visibility)
 	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+	if mainBranch != "" {
+		params.Add("mainBranch", mainBranch)
 	}
-	defer func() { err = resp.Body.Close() }()
 
-	if resp.StatusCode != 200 {
-		return Project{}, fmt.Errorf("error calling sonar api: %s", resp.Status)
+	var response map[string]Project
+	if err := projectClient.sonarApi.do(ctx, "POST", "/api/projects/create", params, &response); err != nil {
+		// A project left by an earlier, partially failed attempt is taken
+		// over, so that creating is idempotent. SonarQube also rejects a key
+		// similar to the key of another project, e.g. differing in case,
+		// which is not taken over.
+		if isAlreadyExists(err) {
+			existing, getErr := projectClient.GetByProjectKey(ctx, organization, project)
+			if getErr == nil && existing.Key == project {
+				return existing, false, nil
+			}
+			if getErr != nil && !errors.Is(getErr, ErrProjectNotFound) {
+				return Project{}, false, getErr
+			}
+			return Project{}, false, err
+		}
+		if organization == "" {
+			organization = projectClient.sonarApi.Options.Organization
+		}
+		if isProjectLimitReached(err) {
+			return Project{}, false, &ProjectLimitError{Organization: organization, Err: err}
+		}
+		return Project{}, false, withKeyPrefixHint(err, organization, project)
 	}
 
-	responseData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return Project{}, err
-	}
+	return response["project"], true, nil
+}
 
-	var response map[string]Project
-	e := json.Unmarshal(responseData, &response)
+// KeyPrefix returns the prefix SonarCloud organizations commonly require on
+// the keys of their projects, e.g. "myorg_".
+func KeyPrefix(organization string) string {
+	return organization + "_"
+}
 
-	return response["project"], e
+// withKeyPrefixHint adds a hint to a rejected create when the key lacks the
+// prefix of its organization, since SonarCloud reports this with a message
+// that does not mention the prefix. err can still be unwrapped to a
+// *SonarAPIError.
+func withKeyPrefixHint(err error, organization string, project string) error {
+	var apiErr *SonarAPIError
+	if organization == "" || strings.HasPrefix(project, KeyPrefix(organization)) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return err
+	}
+	return fmt.Errorf("%w (the organization may require project keys to start with %q, e.g. %q)", err, KeyPrefix(organization), KeyPrefix(organization)+project)
 }
 
 // Delete project
 // https://sonarcloud.io/web_api/api/projects/delete
 func (projectClient ProjectClient) Delete(ctx context.Context, project string) error {
-
-	url := projectClient.sonarApi.GetUrl("/api/projects/delete")
-	params := url.Query()
+	params := url.Values{}
 	params.Add("project", project)
-	url.RawQuery = params.Encode()
-
-	client := &http.Client{}
-	req, _ := projectClient.sonarApi.NewRequest(ctx, "POST", url.String(), nil)
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func() { err = resp.Body.Close() }()
-
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("error calling sonar api: %s", resp.Status)
-	}
-
-	return nil
 
+	return projectClient.sonarApi.do(ctx, "POST", "/api/projects/delete", params, nil)
 }
 
-// Search calls the "/api/projects/search" endpoint
+// Search calls the "/api/projects/search" endpoint. Its paging parameters
+// are named after Options.ServerVersion.
 // https://sonarcloud.io/web_api/api/projects/search
 func (projectClient ProjectClient) Search(ctx context.Context, organization string, options SearchOptions) (ProjectPage, error) {
+	params := projectClient.filterParams(organization, options)
+	projectClient.sonarApi.addPaging(params, options.Page, options.PageSize)
+
+	var page ProjectPage
+	if err := projectClient.sonarApi.do(ctx, "GET", "/api/projects/search", params, &page); err != nil {
+		return ProjectPage{}, err
+	}
+
+	return page, nil
+}
 
-	url := projectClient.sonarApi.GetUrl("/api/projects/search")
-	params := url.Query()
-	params.Add("organization", organization)
+// filterParams returns the parameters selecting the projects matched by
+// options, leaving out paging.
+func (projectClient ProjectClient) filterParams(organization string, options SearchOptions) url.Values {
+	params := url.Values{}
+	projectClient.sonarApi.addOrganization(params, organization)
 
 	if len(options.Projects) > 0 {
 		params.Add("projects", strings.Join(options.Projects, ","))
 	}
-	if options.Page > 0 {
-		params.Add("p", strconv.Itoa(options.Page))
+	if options.Query != "" {
+		params.Add("q", options.Query)
 	}
-	if options.PageSize > 0 {
-		params.Add("ps", strconv.Itoa(options.PageSize))
+	if !options.AnalyzedBefore.IsZero() {
+		params.Add("analyzedBefore", options.AnalyzedBefore.Format(SonarTimeLayout))
 	}
-
-	url.RawQuery = params.Encode()
-
-	client := &http.Client{}
-	req, err := projectClient.sonarApi.NewRequest(ctx, "GET", url.String(), nil)
-	if err != nil {
-		log.Fatal(err)
+	if options.OnProvisionedOnly {
+		params.Add("onProvisionedOnly", "true")
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatal(err)
+	if len(options.Qualifiers) > 0 {
+		params.Add("qualifiers", strings.Join(options.Qualifiers, ","))
 	}
-	defer func() { err = resp.Body.Close() }()
+	return params
+}
 
-	if resp.StatusCode != 200 {
-		return ProjectPage{}, fmt.Errorf("error calling sonar api: %s", resp.Status)
+// Delete every project matched by options in one call. Paging is ignored.
+// Nothing is deleted unless confirm is true, and at least one of Projects,
+// Query or AnalyzedBefore must be set.
+// https://sonarcloud.io/web_api/api/projects/bulk_delete
+func (projectClient ProjectClient) BulkDelete(ctx context.Context, organization string, options SearchOptions, confirm bool) error {
+	if !confirm {
+		return ErrBulkDeleteNotConfirmed
 	}
-	responseData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ProjectPage{}, err
+	if len(options.Projects) == 0 && options.Query == "" && options.AnalyzedBefore.IsZero() {
+		return errors.New("bulk delete requires projects, a query or an analysis date")
+	}
+
+	return projectClient.sonarApi.do(ctx, "POST", "/api/projects/bulk_delete", projectClient.filterParams(organization, options), nil)
+}
+
+// MaxPageSize is the largest page size accepted by the search endpoints.
+const MaxPageSize = 500
+
+// SearchV2 calls the "/api/projects/search_v2" endpoint of SonarQube 10 and
+// later, which pages with the tokens returned in NextPageToken instead of
+// page numbers. options.Page is ignored.
+func (projectClient ProjectClient) SearchV2(ctx context.Context, organization string, options SearchOptions) (ProjectPage, error) {
+	params := projectClient.filterParams(organization, options)
+	projectClient.sonarApi.addPaging(params, 0, options.PageSize)
+	if options.PageToken != "" {
+		params.Add("pageToken", options.PageToken)
 	}
 
 	var page ProjectPage
-	e := json.Unmarshal(responseData, &page)
-	if e != nil {
+	if err := projectClient.sonarApi.do(ctx, "GET", "/api/projects/search_v2", params, &page); err != nil {
 		return ProjectPage{}, err
 	}
 
-	return page, e
+	return page, nil
+}
+
+// usesPageTokens returns true if the instance is known to be SonarQube 10 or
+// later, whose search pages with tokens.
+func (projectClient ProjectClient) usesPageTokens() bool {
+	major, err := MajorVersion(projectClient.sonarApi.Options.ServerVersion)
+	return err == nil && major >= 10
+}
+
+// MaxProjectsPerSearch is the largest number of project keys sent in a single
+// search, which keeps the URL within the limits of the server.
+const MaxProjectsPerSearch = 50
+
+// SearchAll calls Search, or SearchV2 when Options.ServerVersion is 10 or
+// later, for every page of results and returns all matching projects in
+// order. The page size of options is capped at MaxPageSize and defaults to it;
+// options.Page and options.PageToken are ignored. Projects holding more than
+// MaxProjectsPerSearch keys are searched in batches whose results are merged.
+func (projectClient ProjectClient) SearchAll(ctx context.Context, organization string, options SearchOptions) ([]Project, error) {
+	if options.PageSize <= 0 || options.PageSize > MaxPageSize {
+		options.PageSize = MaxPageSize
+	}
+
+	if len(options.Projects) <= MaxProjectsPerSearch {
+		return projectClient.searchAllPages(ctx, organization, options)
+	}
+
+	keys := options.Projects
+	var projects []Project
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > MaxProjectsPerSearch {
+			n = MaxProjectsPerSearch
+		}
+		options.Projects = keys[:n]
+		keys = keys[n:]
+
+		batch, err := projectClient.searchAllPages(ctx, organization, options)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, batch...)
+	}
+	return projects, nil
+}
+
+// searchAllPages returns the projects of every page of results, paging with
+// tokens on instances that support it.
+func (projectClient ProjectClient) searchAllPages(ctx context.Context, organization string, options SearchOptions) ([]Project, error) {
+	if projectClient.usesPageTokens() {
+		projects, err := projectClient.searchAllByToken(ctx, organization, options)
+		// Page numbers are still supported by instances without search_v2.
+		if !isUnknownUrl(err) {
+			return projects, err
+		}
+	}
+
+	var projects []Project
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		options.Page = page
+		projectPage, err := projectClient.Search(ctx, organization, options)
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, projectPage.Projects...)
+		if len(projectPage.Projects) == 0 || len(projects) >= projectPage.Paging.Total {
+			return projects, nil
+		}
+	}
+}
+
+// searchAllByToken calls SearchV2 for every page of results.
+func (projectClient ProjectClient) searchAllByToken(ctx context.Context, organization string, options SearchOptions) ([]Project, error) {
+	options.PageToken = ""
+
+	var projects []Project
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		projectPage, err := projectClient.SearchV2(ctx, organization, options)
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, projectPage.Projects...)
+		if projectPage.NextPageToken == "" || len(projectPage.Projects) == 0 {
+			return projects, nil
+		}
+		options.PageToken = projectPage.NextPageToken
+	}
+}
+
+// GetProjectUrl returns the URL of the dashboard of a project
+func (projectClient ProjectClient) GetProjectUrl(project string) (string, error) {
+	u, err := projectClient.sonarApi.GetUrl("/dashboard")
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{}
+	params.Add("id", project)
+	u.RawQuery = params.Encode()
+
+	return u.String(), nil
 }
 
 // Get a single sonar project by project key
 func (projectClient ProjectClient) GetByProjectKey(ctx context.Context, organization string, project string) (Project, error) {
 
 	projectPage, err := projectClient.Search(ctx, organization, SearchOptions{Projects: []string{project}})
-
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+		return Project{}, fmt.Errorf("%w: %s", ErrInsufficientPermissions, err)
+	}
 	if err != nil {
 		return Project{}, err
 	}
@@ -183,27 +427,56 @@ func (projectClient ProjectClient) GetByProjectKey(ctx context.Context, organiza
 
 // Update project visibility
 func (projectClient ProjectClient) UpdateVisibility(ctx context.Context, project string, visibility string) error {
+	if err := ValidateVisibility(visibility); err != nil {
+		return err
+	}
 
-	url := projectClient.sonarApi.GetUrl("/api/projects/update_visibility")
-	params := url.Query()
+	params := url.Values{}
 	params.Add("project", project)
 	params.Add("visibility", visibility)
-	url.RawQuery = params.Encode()
 
-	client := &http.Client{}
-	req, _ := projectClient.sonarApi.NewRequest(ctx, "POST", url.String(), nil)
-	resp, _ := client.Do(req)
-	defer func() {
-		err := resp.Body.Close()
-		if err != nil {
-			log.Fatal(err)
-		}
-	}()
+	return projectClient.sonarApi.do(ctx, "POST", "/api/projects/update_visibility", params, nil)
+}
+
+// Update project name. The project key is left unchanged.
+// https://sonarcloud.io/web_api/api/projects/update_name
+func (projectClient ProjectClient) UpdateName(ctx context.Context, project string, name string) error {
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("name", name)
+
+	return projectClient.sonarApi.do(ctx, "POST", "/api/projects/update_name", params, nil)
+}
+
+// Set the tags of a project, replacing any existing ones
+// https://sonarcloud.io/web_api/api/project_tags/set
+func (projectClient ProjectClient) SetTags(ctx context.Context, project string, tags []string) error {
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("tags", strings.Join(tags, ","))
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("error calling sonar api: %s", resp.Status)
+	return projectClient.sonarApi.do(ctx, "POST", "/api/project_tags/set", params, nil)
+}
+
+// GetBadgeToken returns the token protecting the badges of a project
+// https://sonarcloud.io/web_api/api/project_badges/token
+func (projectClient ProjectClient) GetBadgeToken(ctx context.Context, project string) (string, error) {
+	params := url.Values{}
+	params.Add("project", project)
+
+	var response struct {
+		Token string `json:"token"`
 	}
+	err := projectClient.sonarApi.do(ctx, "GET", "/api/project_badges/token", params, &response)
+	return response.Token, err
+}
 
-	return nil
+// RenewBadgeToken replaces the token protecting the badges of a project,
+// invalidating the previous one
+// https://sonarcloud.io/web_api/api/project_badges/renew_token
+func (projectClient ProjectClient) RenewBadgeToken(ctx context.Context, project string) error {
+	params := url.Values{}
+	params.Add("project", project)
 
+	return projectClient.sonarApi.do(ctx, "POST", "/api/project_badges/renew_token", params, nil)
 }
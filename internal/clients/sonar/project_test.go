@@ -0,0 +1,1067 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// unreachableOptions returns options pointing at a server that has already
+// been shut down, so every request fails to connect.
+func unreachableOptions() SonarApiOptions {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+	return SonarApiOptions{Key: "token", BaseUrl: srv.URL}
+}
+
+func TestProjectClientConnectionFailure(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, c ProjectClient) error
+	}{
+		"Create": {
+			reason: "Create should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				_, _, err := c.Create(ctx, "org", "name", "key", "private", "")
+				return err
+			},
+		},
+		"Delete": {
+			reason: "Delete should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				return c.Delete(ctx, "key")
+			},
+		},
+		"Search": {
+			reason: "Search should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				_, err := c.Search(ctx, "org", SearchOptions{})
+				return err
+			},
+		},
+		"GetByProjectKey": {
+			reason: "GetByProjectKey should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				_, err := c.GetByProjectKey(ctx, "org", "key")
+				return err
+			},
+		},
+		"UpdateVisibility": {
+			reason: "UpdateVisibility should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				return c.UpdateVisibility(ctx, "key", "public")
+			},
+		},
+		"UpdateName": {
+			reason: "UpdateName should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				return c.UpdateName(ctx, "key", "name")
+			},
+		},
+		"GetBadgeToken": {
+			reason: "GetBadgeToken should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				_, err := c.GetBadgeToken(ctx, "key")
+				return err
+			},
+		},
+		"RenewBadgeToken": {
+			reason: "RenewBadgeToken should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				return c.RenewBadgeToken(ctx, "key")
+			},
+		},
+		"SetTags": {
+			reason: "SetTags should return an error when the API is unreachable.",
+			call: func(ctx context.Context, c ProjectClient) error {
+				return c.SetTags(ctx, "key", []string{"team:payments"})
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewProjectClient(unreachableOptions())
+			if err := tc.call(context.Background(), c); err == nil {
+				t.Errorf("\n%s\n%s(...): want error, got nil", tc.reason, name)
+			}
+		})
+	}
+}
+
+func TestSearchAll(t *testing.T) {
+	pages := [][]Project{
+		{{Key: "a"}, {Key: "b"}},
+		{{Key: "c"}, {Key: "d"}},
+		{{Key: "e"}},
+	}
+
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.FormValue("p")+"/"+r.FormValue("ps"))
+		p, _ := strconv.Atoi(r.FormValue("p"))
+		_ = json.NewEncoder(w).Encode(ProjectPage{
+			Paging:   SonarPaging{PageIndex: p, PageSize: 2, Total: 5},
+			Projects: pages[p-1],
+		})
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.SearchAll(context.Background(), "org", SearchOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("c.SearchAll(...): unexpected error: %v", err)
+	}
+
+	want := []Project{{Key: "a"}, {Key: "b"}, {Key: "c"}, {Key: "d"}, {Key: "e"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.SearchAll(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"1/2", "2/2", "3/2"}, requested); diff != "" {
+		t.Errorf("c.SearchAll(...): -want pages requested, +got pages requested:\n%s\n", diff)
+	}
+}
+
+func TestSearchAllPageSize(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		pageSize int
+		want     string
+	}{
+		"Default": {
+			reason: "The maximum page size should be used when none is set.",
+			want:   "500",
+		},
+		"Capped": {
+			reason:   "A page size above the documented maximum should be capped.",
+			pageSize: 1000,
+			want:     "500",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.FormValue("ps")
+				_ = json.NewEncoder(w).Encode(ProjectPage{})
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if _, err := c.SearchAll(context.Background(), "org", SearchOptions{PageSize: tc.pageSize}); err != nil {
+				t.Fatalf("\n%s\nc.SearchAll(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.SearchAll(...): -want ps, +got ps:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSearchAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Cancel after the first page so the next fetch is never issued.
+		cancel()
+		_ = json.NewEncoder(w).Encode(ProjectPage{
+			Paging:   SonarPaging{PageIndex: 1, PageSize: 1, Total: 3},
+			Projects: []Project{{Key: "a"}},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.SearchAll(ctx, "org", SearchOptions{PageSize: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("c.SearchAll(...): want context.Canceled, got %v", err)
+	}
+}
+
+func TestGetByProjectKeyForbidden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Insufficient privileges"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	_, err := c.GetByProjectKey(context.Background(), "org", "key")
+	if !errors.Is(err, ErrInsufficientPermissions) {
+		t.Errorf("c.GetByProjectKey(...): want ErrInsufficientPermissions, got %v", err)
+	}
+	if errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("c.GetByProjectKey(...): a permission error must not be reported as ErrProjectNotFound")
+	}
+}
+
+func TestOrganizationParameter(t *testing.T) {
+	type want struct {
+		present      bool
+		organization string
+	}
+
+	cases := map[string]struct {
+		reason       string
+		mode         InstanceMode
+		defaultOrg   string
+		organization string
+		call         func(ctx context.Context, c ProjectClient, organization string) error
+		want         want
+	}{
+		"SearchBlank": {
+			reason: "Search should omit the organization when it is blank.",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.Search(ctx, organization, SearchOptions{})
+				return err
+			},
+		},
+		"SearchSet": {
+			reason:       "Search should include the organization when it is set.",
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.Search(ctx, organization, SearchOptions{})
+				return err
+			},
+			want: want{present: true, organization: "org"},
+		},
+		"GetByProjectKeyBlank": {
+			reason: "GetByProjectKey should omit the organization when it is blank.",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.GetByProjectKey(ctx, organization, "key")
+				return err
+			},
+		},
+		"GetByProjectKeySet": {
+			reason:       "GetByProjectKey should include the organization when it is set.",
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.GetByProjectKey(ctx, organization, "key")
+				return err
+			},
+			want: want{present: true, organization: "org"},
+		},
+		"CreateSet": {
+			reason:       "Create should include the organization when it is set.",
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, _, err := c.Create(ctx, organization, "Name", "key", "public", "")
+				return err
+			},
+			want: want{present: true, organization: "org"},
+		},
+		"SearchSonarQube": {
+			reason:       "Search should omit the organization on SonarQube, which has none.",
+			mode:         InstanceModeSonarQube,
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.Search(ctx, organization, SearchOptions{})
+				return err
+			},
+		},
+		"GetByProjectKeySonarQube": {
+			reason:       "GetByProjectKey should omit the organization on SonarQube, which has none.",
+			mode:         InstanceModeSonarQube,
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.GetByProjectKey(ctx, organization, "key")
+				return err
+			},
+		},
+		"CreateSonarQube": {
+			reason:       "Create should omit the organization on SonarQube, which has none.",
+			mode:         InstanceModeSonarQube,
+			organization: "org",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, _, err := c.Create(ctx, organization, "Name", "key", "public", "")
+				return err
+			},
+		},
+		"DefaultSonarQube": {
+			reason:     "The default organization should not be sent to SonarQube either.",
+			mode:       InstanceModeSonarQube,
+			defaultOrg: "default",
+			call: func(ctx context.Context, c ProjectClient, organization string) error {
+				_, err := c.GetByProjectKey(ctx, organization, "key")
+				return err
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got.present = r.Form.Has("organization")
+				got.organization = r.Form.Get("organization")
+				if r.URL.Path == "/api/projects/create" {
+					_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(ProjectPage{Projects: []Project{{Key: "key"}}})
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, InstanceMode: tc.mode, Organization: tc.defaultOrg})
+			if err := tc.call(context.Background(), c, tc.organization); err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestBadgeToken(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requested = append(requested, r.Method+" "+r.URL.Path+"?"+r.Form.Encode())
+		if r.URL.Path == "/api/project_badges/token" {
+			_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.RenewBadgeToken(context.Background(), "key"); err != nil {
+		t.Fatalf("c.RenewBadgeToken(...): unexpected error: %v", err)
+	}
+	got, err := c.GetBadgeToken(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.GetBadgeToken(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff("badge-token", got); diff != "" {
+		t.Errorf("c.GetBadgeToken(...): -want, +got:\n%s\n", diff)
+	}
+	want := []string{"POST /api/project_badges/renew_token?project=key", "GET /api/project_badges/token?project=key"}
+	if diff := cmp.Diff(want, requested); diff != "" {
+		t.Errorf("-want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestVisibility(t *testing.T) {
+	type want struct {
+		err    bool
+		called bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		visibility string
+		call       func(ctx context.Context, c ProjectClient, visibility string) error
+		want       want
+	}{
+		"CreatePublic": {
+			reason:     "Create should accept the public visibility.",
+			visibility: "public",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				_, _, err := c.Create(ctx, "org", "name", "key", visibility, "")
+				return err
+			},
+			want: want{called: true},
+		},
+		"CreateUnset": {
+			reason: "Create should leave the default visibility when none is set.",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				_, _, err := c.Create(ctx, "org", "name", "key", visibility, "")
+				return err
+			},
+			want: want{called: true},
+		},
+		"CreateInvalid": {
+			reason:     "Create should reject an invalid visibility before calling the API.",
+			visibility: "internal",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				_, _, err := c.Create(ctx, "org", "name", "key", visibility, "")
+				return err
+			},
+			want: want{err: true},
+		},
+		"UpdatePrivate": {
+			reason:     "UpdateVisibility should accept the private visibility.",
+			visibility: "private",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				return c.UpdateVisibility(ctx, "key", visibility)
+			},
+			want: want{called: true},
+		},
+		"UpdateInvalid": {
+			reason:     "UpdateVisibility should reject an invalid visibility before calling the API.",
+			visibility: "Public",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				return c.UpdateVisibility(ctx, "key", visibility)
+			},
+			want: want{err: true},
+		},
+		"UpdateUnset": {
+			reason: "UpdateVisibility should reject an empty visibility before calling the API.",
+			call: func(ctx context.Context, c ProjectClient, visibility string) error {
+				return c.UpdateVisibility(ctx, "key", visibility)
+			},
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				_, _ = w.Write([]byte(`{"project":{"key":"key"}}`))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := tc.call(context.Background(), c, tc.visibility)
+			if (err != nil) != tc.want.err {
+				t.Errorf("\n%s\n%s(...): want error %t, got %v", tc.reason, name, tc.want.err, err)
+			}
+			if called != tc.want.called {
+				t.Errorf("\n%s\n%s(...): want API called %t, got %t", tc.reason, name, tc.want.called, called)
+			}
+		})
+	}
+}
+
+func TestValidateProjectKey(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		key    string
+		want   string
+	}{
+		"Valid": {
+			reason: "A key of letters, digits and the allowed punctuation should be accepted.",
+			key:    "my-org_payments:api.v2",
+		},
+		"LeadingDigits": {
+			reason: "A key that starts with digits should be accepted as long as it is not all digits.",
+			key:    "2024-payments",
+		},
+		"Longest": {
+			reason: "A key of the maximum length should be accepted.",
+			key:    strings.Repeat("k", MaxProjectKeyLength),
+		},
+		"Empty": {
+			reason: "An empty key should be rejected.",
+			want:   "project key must not be empty",
+		},
+		"TooLong": {
+			reason: "A key longer than the maximum length should be rejected.",
+			key:    strings.Repeat("k", MaxProjectKeyLength+1),
+			want:   "project key is 401 characters long, longer than the maximum of 400",
+		},
+		"Space": {
+			reason: "A key with a space should be rejected, naming the character.",
+			key:    "my project",
+			want:   `project key "my project" contains ' ', but may only contain letters, digits, '-', '_', '.' and ':'`,
+		},
+		"Slash": {
+			reason: "A key with a slash should be rejected, naming the character.",
+			key:    "org/project",
+			want:   `project key "org/project" contains '/', but may only contain letters, digits, '-', '_', '.' and ':'`,
+		},
+		"NonASCII": {
+			reason: "A key with a letter outside of ASCII should be rejected, naming the character.",
+			key:    "café",
+			want:   `project key "café" contains 'é', but may only contain letters, digits, '-', '_', '.' and ':'`,
+		},
+		"Digits": {
+			reason: "A key of digits only should be rejected.",
+			key:    "12345",
+			want:   `project key "12345" must contain at least one character that is not a digit`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if err := ValidateProjectKey(tc.key); err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidateProjectKey(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateInvalidKey(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, _, err := c.Create(context.Background(), "org", "name", "my project", "", ""); err == nil {
+		t.Errorf("c.Create(...): want error for an invalid key")
+	}
+	if called {
+		t.Errorf("c.Create(...): want an invalid key rejected before calling the API")
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		options SearchOptions
+		want    url.Values
+	}{
+		"None": {
+			reason: "No filter should be sent when none is set.",
+			want:   url.Values{"organization": {"org"}},
+		},
+		"AnalyzedBefore": {
+			reason:  "The analysis date should be sent in the SonarCloud time layout.",
+			options: SearchOptions{AnalyzedBefore: time.Date(2022, time.November, 10, 19, 33, 53, 0, time.FixedZone("CET", 3600))},
+			want:    url.Values{"organization": {"org"}, "analyzedBefore": {"2022-11-10T19:33:53+0100"}},
+		},
+		"OnProvisionedOnly": {
+			reason:  "Only provisioned projects should be requested when set.",
+			options: SearchOptions{OnProvisionedOnly: true},
+			want:    url.Values{"organization": {"org"}, "onProvisionedOnly": {"true"}},
+		},
+		"Qualifiers": {
+			reason:  "The qualifiers should be sent comma separated.",
+			options: SearchOptions{Qualifiers: []string{"TRK", "APP"}},
+			want:    url.Values{"organization": {"org"}, "qualifiers": {"TRK,APP"}},
+		},
+		"Query": {
+			reason:  "The free-text query should be sent as q.",
+			options: SearchOptions{Query: "payments"},
+			want:    url.Values{"organization": {"org"}, "q": {"payments"}},
+		},
+		"QueryAndProjects": {
+			reason:  "The query should be combined with the project keys.",
+			options: SearchOptions{Projects: []string{"payments-api", "payments-web"}, Query: "web"},
+			want:    url.Values{"organization": {"org"}, "projects": {"payments-api,payments-web"}, "q": {"web"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = r.Form
+				_ = json.NewEncoder(w).Encode(ProjectPage{})
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if _, err := c.Search(context.Background(), "org", tc.options); err != nil {
+				t.Fatalf("\n%s\nc.Search(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateAlreadyExists(t *testing.T) {
+	type want struct {
+		project Project
+		err     bool
+	}
+
+	existing := `{"paging":{"pageIndex":1,"pageSize":100,"total":1},"components":[{"organization":"org","key":"key","name":"Existing"}]}`
+
+	cases := map[string]struct {
+		reason string
+		body   string
+		search string
+		want   want
+	}{
+		"SonarCloud": {
+			reason: "A key taken on SonarCloud should return the existing project without error.",
+			body:   `{"errors":[{"msg":"Could not create Project, key already exists: key"}]}`,
+			want:   want{project: Project{Organization: "org", Key: "key", Name: "Existing"}},
+		},
+		"SonarQube": {
+			reason: "A key taken on SonarQube should return the existing project without error.",
+			body:   `{"errors":[{"msg":"Could not create Project with key: \"key\". A similar key already exists: \"key\""}]}`,
+			want:   want{project: Project{Organization: "org", Key: "key", Name: "Existing"}},
+		},
+		"SonarQubeSimilarKey": {
+			reason: "A key similar to the key of another project on SonarQube should be returned as an error rather than take that project over.",
+			body:   `{"errors":[{"msg":"Could not create Project with key: \"key\". A similar key already exists: \"KEY\""}]}`,
+			search: `{"paging":{"pageIndex":1,"pageSize":100,"total":1},"components":[{"organization":"org","key":"KEY","name":"Other"}]}`,
+			want:   want{err: true},
+		},
+		"SonarQubeSimilarKeyNotFound": {
+			reason: "A key similar to the key of another project that is not found should be returned as an error.",
+			body:   `{"errors":[{"msg":"Could not create Project with key: \"key\". A similar key already exists: \"KEY\""}]}`,
+			search: `{"paging":{"pageIndex":1,"pageSize":100,"total":0},"components":[]}`,
+			want:   want{err: true},
+		},
+		"OtherBadRequest": {
+			reason: "Any other 400 should still be returned as an error.",
+			body:   `{"errors":[{"msg":"Malformed key for Project: 'my key'."}]}`,
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/projects/create":
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(tc.body))
+				case "/api/projects/search":
+					search := existing
+					if tc.search != "" {
+						search = tc.search
+					}
+					_, _ = w.Write([]byte(search))
+				}
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, created, err := c.Create(context.Background(), "org", "name", "key", "private", "")
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nc.Create(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			// The 400 of the create is returned unchanged.
+			var apiErr *SonarAPIError
+			if err != nil && (!errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest) {
+				t.Errorf("\n%s\nc.Create(...): want the 400 of the create, got %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.project, got); diff != "" {
+				t.Errorf("\n%s\nc.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if created {
+				t.Errorf("\n%s\nc.Create(...): want an existing project, got a created one", tc.reason)
+			}
+		})
+	}
+}
+
+func TestProjectCreateMainBranch(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		mainBranch string
+		want       url.Values
+	}{
+		"Set": {
+			reason:     "The main branch should be named when a name is given.",
+			mainBranch: "main",
+			want:       url.Values{"organization": {"org"}, "name": {"name"}, "project": {"key"}, "mainBranch": {"main"}},
+		},
+		"Unset": {
+			reason: "The main branch should get the default name when no name is given.",
+			want:   url.Values{"organization": {"org"}, "name": {"name"}, "project": {"key"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				params = r.Form
+				_, _ = w.Write([]byte(`{"project":{"key":"key","name":"name"}}`))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if _, _, err := c.Create(context.Background(), "org", "name", "key", "", tc.mainBranch); err != nil {
+				t.Fatalf("\n%s\nc.Create(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, params); diff != "" {
+				t.Errorf("\n%s\nc.Create(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultOrganization(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		organization string
+		want         string
+	}{
+		"Inherited": {
+			reason: "The default organization should be sent when a call is given none.",
+			want:   "default",
+		},
+		"Overridden": {
+			reason:       "The organization given to a call should take precedence over the default.",
+			organization: "org",
+			want:         "org",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.FormValue("organization")
+				_ = json.NewEncoder(w).Encode(ProjectPage{Projects: []Project{{Key: "key"}}})
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, Organization: "default"})
+			if _, err := c.GetByProjectKey(context.Background(), tc.organization, "key"); err != nil {
+				t.Fatalf("\n%s\nc.GetByProjectKey(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.GetByProjectKey(...): -want organization, +got organization:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateKeyPrefixHint(t *testing.T) {
+	type want struct {
+		hint bool
+	}
+
+	cases := map[string]struct {
+		reason       string
+		organization string
+		key          string
+		status       int
+		want         want
+	}{
+		"Unprefixed": {
+			reason:       "A rejected key without the organization prefix should suggest the prefixed key.",
+			organization: "myorg",
+			key:          "myproject",
+			status:       http.StatusBadRequest,
+			want:         want{hint: true},
+		},
+		"Prefixed": {
+			reason:       "A rejected key that already has the organization prefix should not suggest a prefix.",
+			organization: "myorg",
+			key:          "myorg_myproject",
+			status:       http.StatusBadRequest,
+		},
+		"NoOrganization": {
+			reason: "No prefix should be suggested without an organization, as on SonarQube.",
+			key:    "myproject",
+			status: http.StatusBadRequest,
+		},
+		"Forbidden": {
+			reason:       "No prefix should be suggested when the create was not rejected as invalid.",
+			organization: "myorg",
+			key:          "myproject",
+			status:       http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(`{"errors":[{"msg":"Could not create Project"}]}`))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			_, _, err := c.Create(context.Background(), tc.organization, "name", tc.key, "private", "")
+
+			var apiErr *SonarAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("\n%s\nc.Create(...): want a *SonarAPIError, got %v", tc.reason, err)
+			}
+			hint := strings.Contains(err.Error(), `"myorg_myproject"`)
+			if hint != tc.want.hint {
+				t.Errorf("\n%s\nc.Create(...): want hint %t, got %q", tc.reason, tc.want.hint, err.Error())
+			}
+		})
+	}
+}
+
+func TestCreateProjectLimitReached(t *testing.T) {
+	type want struct {
+		limit bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+		body   string
+		want   want
+	}{
+		"PrivateNotAllowed": {
+			reason: "A free organization refusing a private project should report the project limit.",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"msg":"This organization cannot use project private"}]}`,
+			want:   want{limit: true},
+		},
+		"LimitReached": {
+			reason: "An organization at its private project limit should report the project limit.",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"msg":"Organization has reached the limit of private projects"}]}`,
+			want:   want{limit: true},
+		},
+		"OtherBadRequest": {
+			reason: "Other rejected creates should not report the project limit.",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"msg":"Malformed key for Project: 'my project'"}]}`,
+		},
+		"Forbidden": {
+			reason: "A create that is not allowed should not report the project limit.",
+			status: http.StatusForbidden,
+			body:   `{"errors":[{"msg":"Insufficient privileges to create private project"}]}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			_, _, err := c.Create(context.Background(), "myorg", "name", "myorg_key", "private", "")
+
+			if got := errors.Is(err, ErrProjectLimitReached); got != tc.want.limit {
+				t.Errorf("\n%s\nc.Create(...): want ErrProjectLimitReached %t, got %v", tc.reason, tc.want.limit, err)
+			}
+			var apiErr *SonarAPIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+				t.Errorf("\n%s\nc.Create(...): want a *SonarAPIError with status %d, got %v", tc.reason, tc.status, err)
+			}
+		})
+	}
+}
+
+func TestBulkDelete(t *testing.T) {
+	type want struct {
+		params url.Values
+		err    error
+		anyErr bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		options SearchOptions
+		confirm bool
+		want    want
+	}{
+		"Confirmed": {
+			reason:  "The filters should be sent when the deletion is confirmed.",
+			options: SearchOptions{Query: "tmp-", AnalyzedBefore: time.Date(2022, time.November, 10, 0, 0, 0, 0, time.UTC), OnProvisionedOnly: true, Page: 2, PageSize: 50},
+			confirm: true,
+			want:    want{params: url.Values{"organization": {"org"}, "q": {"tmp-"}, "analyzedBefore": {"2022-11-10T00:00:00+0000"}, "onProvisionedOnly": {"true"}}},
+		},
+		"NotConfirmed": {
+			reason:  "Nothing should be deleted when the deletion is not confirmed.",
+			options: SearchOptions{Query: "tmp-"},
+			want:    want{err: ErrBulkDeleteNotConfirmed},
+		},
+		"NoFilter": {
+			reason:  "Nothing should be deleted when no filter restricts the deletion.",
+			confirm: true,
+			want:    want{anyErr: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.URL.Path != "/api/projects/bulk_delete" || r.Method != http.MethodPost {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				got = r.Form
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := c.BulkDelete(context.Background(), "org", tc.options, tc.confirm)
+			if tc.want.err != nil && !errors.Is(err, tc.want.err) {
+				t.Errorf("\n%s\nc.BulkDelete(...): want error %v, got %v", tc.reason, tc.want.err, err)
+			}
+			if (err != nil) != (tc.want.err != nil || tc.want.anyErr) {
+				t.Errorf("\n%s\nc.BulkDelete(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.params, got); diff != "" {
+				t.Errorf("\n%s\nc.BulkDelete(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSearchAllPagination(t *testing.T) {
+	pages := [][]Project{
+		{{Key: "a"}, {Key: "b"}},
+		{{Key: "c"}},
+	}
+
+	cases := map[string]struct {
+		reason        string
+		serverVersion string
+		searchV2      bool
+		want          []string
+	}{
+		"PageNumbers": {
+			reason:        "Instances before SonarQube 10 should be paged with page numbers.",
+			serverVersion: "9.9.1.69595",
+			searchV2:      true,
+			want:          []string{"/api/projects/search p=1", "/api/projects/search p=2"},
+		},
+		"UnknownVersion": {
+			reason: "Instances of unknown version, such as SonarCloud, should be paged with page numbers.",
+			want:   []string{"/api/projects/search p=1", "/api/projects/search p=2"},
+		},
+		"PageTokens": {
+			reason:        "SonarQube 10 and later should be paged with page tokens.",
+			serverVersion: "10.2.1.78527",
+			searchV2:      true,
+			want:          []string{"/api/projects/search_v2 pageToken=", "/api/projects/search_v2 pageToken=page-2"},
+		},
+		"PageTokensUnsupported": {
+			reason:        "Page numbers should be used when the instance has no search_v2 endpoint.",
+			serverVersion: "10.2.1.78527",
+			want:          []string{"/api/projects/search_v2 pageToken=", "/api/projects/search page=1", "/api/projects/search page=2"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requested []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				q := r.Form
+				switch r.URL.Path {
+				case "/api/projects/search_v2":
+					requested = append(requested, r.URL.Path+" pageToken="+q.Get("pageToken"))
+					if !tc.searchV2 {
+						w.WriteHeader(http.StatusNotFound)
+						_, _ = w.Write([]byte(`{"errors":[{"msg":"Unknown url : /api/projects/search_v2"}]}`))
+						return
+					}
+					if q.Get("pageToken") == "" {
+						_ = json.NewEncoder(w).Encode(ProjectPage{Projects: pages[0], NextPageToken: "page-2"})
+						return
+					}
+					_ = json.NewEncoder(w).Encode(ProjectPage{Projects: pages[1]})
+				case "/api/projects/search":
+					name := "p"
+					if q.Has("page") {
+						name = "page"
+					}
+					requested = append(requested, r.URL.Path+" "+name+"="+q.Get(name))
+					p, _ := strconv.Atoi(q.Get(name))
+					_ = json.NewEncoder(w).Encode(ProjectPage{Paging: SonarPaging{PageIndex: p, PageSize: 2, Total: 3}, Projects: pages[p-1]})
+				}
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, ServerVersion: tc.serverVersion, MaxAttempts: 1})
+			got, err := c.SearchAll(context.Background(), "org", SearchOptions{PageSize: 2})
+			if err != nil {
+				t.Fatalf("\n%s\nc.SearchAll(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff([]Project{{Key: "a"}, {Key: "b"}, {Key: "c"}}, got); diff != "" {
+				t.Errorf("\n%s\nc.SearchAll(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, requested); diff != "" {
+				t.Errorf("\n%s\nc.SearchAll(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSearchPagingParams(t *testing.T) {
+	cases := map[string]struct {
+		reason        string
+		serverVersion string
+		want          url.Values
+	}{
+		"UnknownVersion": {
+			reason: "Instances of unknown version, such as SonarCloud, should be sent p and ps.",
+			want:   url.Values{"organization": {"org"}, "p": {"2"}, "ps": {"50"}},
+		},
+		"SonarQube9": {
+			reason:        "SonarQube before 10 should be sent p and ps.",
+			serverVersion: "9.9.1.69595",
+			want:          url.Values{"organization": {"org"}, "p": {"2"}, "ps": {"50"}},
+		},
+		"SonarQube10": {
+			reason:        "SonarQube 10 and later should be sent page and pageSize.",
+			serverVersion: "10.2.1.78527",
+			want:          url.Values{"organization": {"org"}, "page": {"2"}, "pageSize": {"50"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = r.Form
+				_ = json.NewEncoder(w).Encode(ProjectPage{})
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, ServerVersion: tc.serverVersion})
+			if _, err := c.Search(context.Background(), "org", SearchOptions{Page: 2, PageSize: 50}); err != nil {
+				t.Fatalf("\n%s\nc.Search(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSearchAllProjectBatches(t *testing.T) {
+	keys := make([]string, 120)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%03d", i)
+	}
+
+	var batches []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested := strings.Split(r.FormValue("projects"), ",")
+		batches = append(batches, len(requested))
+
+		page := ProjectPage{Paging: SonarPaging{PageIndex: 1, PageSize: MaxPageSize, Total: len(requested)}}
+		for _, k := range requested {
+			page.Projects = append(page.Projects, Project{Key: k})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.SearchAll(context.Background(), "org", SearchOptions{Projects: keys})
+	if err != nil {
+		t.Fatalf("c.SearchAll(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{50, 50, 20}, batches); diff != "" {
+		t.Errorf("c.SearchAll(...): -want keys per request, +got keys per request:\n%s\n", diff)
+	}
+	gotKeys := make([]string, 0, len(got))
+	for _, p := range got {
+		gotKeys = append(gotKeys, p.Key)
+	}
+	if diff := cmp.Diff(keys, gotKeys); diff != "" {
+		t.Errorf("c.SearchAll(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,63 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+)
+
+// ProjectAnalysisEventQualityGate is the category of the events recorded
+// when the quality gate status of a project changes.
+const ProjectAnalysisEventQualityGate = "QUALITY_GATE"
+
+// ProjectAnalysisEvent is an event recorded by an analysis, such as a change
+// of the quality gate status or of the project version.
+type ProjectAnalysisEvent struct {
+	Key      string `json:"key"`
+	Category string `json:"category"`
+	// Name of the event, e.g. Red (was Green) for a quality gate event.
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// QualityGate is only set on quality gate events.
+	QualityGate *ProjectAnalysisQualityGate `json:"qualityGate,omitempty"`
+}
+
+// ProjectAnalysisQualityGate is the quality gate status a project changed to.
+type ProjectAnalysisQualityGate struct {
+	// Status is OK or ERROR.
+	Status       string `json:"status"`
+	StillFailing bool   `json:"stillFailing"`
+}
+
+type ProjectAnalysis struct {
+	Key            string                 `json:"key"`
+	Date           SonarTime              `json:"date"`
+	Revision       string                 `json:"revision,omitempty"`
+	ProjectVersion string                 `json:"projectVersion,omitempty"`
+	Events         []ProjectAnalysisEvent `json:"events"`
+}
+
+type ProjectAnalysesClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Project Analyses Client
+func NewProjectAnalysesClient(options SonarApiOptions) ProjectAnalysesClient {
+	return ProjectAnalysesClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Search the recent analyses of a project, newest first
+// https://sonarcloud.io/web_api/api/project_analyses/search
+func (projectAnalysesClient ProjectAnalysesClient) Search(ctx context.Context, project string) ([]ProjectAnalysis, error) {
+	params := url.Values{}
+	params.Add("project", project)
+
+	var response struct {
+		Analyses []ProjectAnalysis `json:"analyses"`
+	}
+	if err := projectAnalysesClient.sonarApi.do(ctx, "GET", "/api/project_analyses/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Analyses, nil
+}
@@ -0,0 +1,98 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProjectAnalysesSearch(t *testing.T) {
+	// A sample response of a project whose quality gate failed on its latest
+	// analysis, after a version was set on the one before.
+	response := `{
+		"paging": {"pageIndex": 1, "pageSize": 100, "total": 3},
+		"analyses": [
+			{
+				"key": "AU-TpxcA-iU5OvuD2FL3",
+				"date": "2023-05-04T14:08:19+0200",
+				"projectVersion": "1.2",
+				"revision": "9d3b1c5",
+				"events": [
+					{
+						"key": "AU-TpxcA-iU5OvuD2FL4",
+						"category": "QUALITY_GATE",
+						"name": "Red (was Green)",
+						"description": "Coverage on New Code < 80",
+						"qualityGate": {"status": "ERROR", "stillFailing": false, "failing": []}
+					}
+				]
+			},
+			{
+				"key": "AU-TpxcA-iU5OvuD2FL1",
+				"date": "2023-05-03T10:00:00+0200",
+				"projectVersion": "1.2",
+				"events": [
+					{"key": "AU-TpxcA-iU5OvuD2FL2", "category": "VERSION", "name": "1.2"}
+				]
+			},
+			{
+				"key": "AU-TpxcA-iU5OvuD2FL0",
+				"date": "2023-05-02T09:00:00+0200",
+				"events": []
+			}
+		]
+	}`
+
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewProjectAnalysesClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Search(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.Search(...): unexpected error: %v", err)
+	}
+
+	cest := time.FixedZone("", 2*60*60)
+	want := []ProjectAnalysis{
+		{
+			Key:            "AU-TpxcA-iU5OvuD2FL3",
+			Date:           SonarTime{time.Date(2023, 5, 4, 14, 8, 19, 0, cest)},
+			Revision:       "9d3b1c5",
+			ProjectVersion: "1.2",
+			Events: []ProjectAnalysisEvent{{
+				Key:         "AU-TpxcA-iU5OvuD2FL4",
+				Category:    ProjectAnalysisEventQualityGate,
+				Name:        "Red (was Green)",
+				Description: "Coverage on New Code < 80",
+				QualityGate: &ProjectAnalysisQualityGate{Status: "ERROR"},
+			}},
+		},
+		{
+			Key:            "AU-TpxcA-iU5OvuD2FL1",
+			Date:           SonarTime{time.Date(2023, 5, 3, 10, 0, 0, 0, cest)},
+			ProjectVersion: "1.2",
+			Events:         []ProjectAnalysisEvent{{Key: "AU-TpxcA-iU5OvuD2FL2", Category: "VERSION", Name: "1.2"}},
+		},
+		{
+			Key:    "AU-TpxcA-iU5OvuD2FL0",
+			Date:   SonarTime{time.Date(2023, 5, 2, 9, 0, 0, 0, cest)},
+			Events: []ProjectAnalysisEvent{},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b SonarTime) bool { return a.Equal(b.Time) })); diff != "" {
+		t.Errorf("c.Search(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"project": {"key"}}, params); diff != "" {
+		t.Errorf("c.Search(...): -want params, +got params:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,94 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ErrMainBranchNotFound is returned when a project has no main branch.
+var ErrMainBranchNotFound = errors.New("Main branch not found")
+
+type Branch struct {
+	Name         string    `json:"name"`
+	IsMain       bool      `json:"isMain"`
+	Type         string    `json:"type"`
+	AnalysisDate SonarTime `json:"analysisDate"`
+	// ExcludedFromPurge is true when the branch is kept when inactive rather
+	// than deleted automatically. The main branch is always kept.
+	ExcludedFromPurge bool `json:"excludedFromPurge"`
+}
+
+type ProjectBranchClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Project Branch Client
+func NewProjectBranchClient(options SonarApiOptions) ProjectBranchClient {
+	return ProjectBranchClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// List the branches of a project
+// https://sonarcloud.io/web_api/api/project_branches/list
+func (projectBranchClient ProjectBranchClient) List(ctx context.Context, project string) ([]Branch, error) {
+	params := url.Values{}
+	params.Add("project", project)
+
+	var response struct {
+		Branches []Branch `json:"branches"`
+	}
+	if err := projectBranchClient.sonarApi.do(ctx, "GET", "/api/project_branches/list", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Branches, nil
+}
+
+// GetMainBranch returns the main branch of a project.
+func (projectBranchClient ProjectBranchClient) GetMainBranch(ctx context.Context, project string) (Branch, error) {
+	branches, err := projectBranchClient.List(ctx, project)
+	if err != nil {
+		return Branch{}, err
+	}
+
+	for _, b := range branches {
+		if b.IsMain {
+			return b, nil
+		}
+	}
+	return Branch{}, ErrMainBranchNotFound
+}
+
+// Rename the main branch of a project
+// https://sonarcloud.io/web_api/api/project_branches/rename
+func (projectBranchClient ProjectBranchClient) RenameMainBranch(ctx context.Context, project string, name string) error {
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("name", name)
+
+	return projectBranchClient.sonarApi.do(ctx, "POST", "/api/project_branches/rename", params, nil)
+}
+
+// Protect a branch of a project from automatic deletion when it is inactive,
+// or lift the protection. The protection of the main branch cannot be lifted.
+// https://next.sonarqube.com/sonarqube/web_api/api/project_branches/set_automatic_deletion_protection
+func (projectBranchClient ProjectBranchClient) SetAutomaticDeletionProtection(ctx context.Context, project string, branch string, value bool) error {
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("branch", branch)
+	params.Add("value", strconv.FormatBool(value))
+
+	return projectBranchClient.sonarApi.do(ctx, "POST", "/api/project_branches/set_automatic_deletion_protection", params, nil)
+}
+
+// Delete a branch of a project. The main branch cannot be deleted.
+// https://sonarcloud.io/web_api/api/project_branches/delete
+func (projectBranchClient ProjectBranchClient) Delete(ctx context.Context, project string, branch string) error {
+	params := url.Values{}
+	params.Add("project", project)
+	params.Add("branch", branch)
+
+	return projectBranchClient.sonarApi.do(ctx, "POST", "/api/project_branches/delete", params, nil)
+}
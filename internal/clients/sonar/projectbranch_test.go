@@ -0,0 +1,155 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestListBranches(t *testing.T) {
+	response := `{
+		"branches": [
+			{"name": "main", "isMain": true, "type": "LONG", "analysisDate": "2022-11-02T10:15:00+0000", "excludedFromPurge": true},
+			{"name": "release/1.x", "isMain": false, "type": "LONG", "excludedFromPurge": true},
+			{"name": "feature/payments", "isMain": false, "type": "SHORT", "excludedFromPurge": false}
+		]
+	}`
+
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewProjectBranchClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.List(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.List(...): unexpected error: %v", err)
+	}
+
+	want := []Branch{
+		{Name: "main", IsMain: true, Type: "LONG", AnalysisDate: SonarTime{time.Date(2022, 11, 2, 10, 15, 0, 0, time.UTC)}, ExcludedFromPurge: true},
+		{Name: "release/1.x", Type: "LONG", ExcludedFromPurge: true},
+		{Name: "feature/payments", Type: "SHORT"},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b SonarTime) bool { return a.Equal(b.Time) })); diff != "" {
+		t.Errorf("c.List(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"project": {"key"}}, params); diff != "" {
+		t.Errorf("c.List(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestGetMainBranch(t *testing.T) {
+	type want struct {
+		branch Branch
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		response string
+		want     want
+	}{
+		"Found": {
+			reason:   "The branch marked as main should be returned.",
+			response: `{"branches": [{"name": "develop"}, {"name": "main", "isMain": true}]}`,
+			want:     want{branch: Branch{Name: "main", IsMain: true}},
+		},
+		"NotFound": {
+			reason:   "ErrMainBranchNotFound should be returned when no branch is marked as main.",
+			response: `{"branches": [{"name": "develop"}]}`,
+			want:     want{err: ErrMainBranchNotFound},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewProjectBranchClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.GetMainBranch(context.Background(), "key")
+			if !errors.Is(err, tc.want.err) {
+				t.Fatalf("\n%s\nc.GetMainBranch(...): want error %v, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.branch, got); diff != "" {
+				t.Errorf("\n%s\nc.GetMainBranch(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBranchMutations(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+	}
+
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, c ProjectBranchClient) error
+		want   want
+	}{
+		"RenameMainBranch": {
+			reason: "RenameMainBranch should send the project and the new name.",
+			call: func(ctx context.Context, c ProjectBranchClient) error {
+				return c.RenameMainBranch(ctx, "key", "main")
+			},
+			want: want{path: "/api/project_branches/rename", params: url.Values{"project": {"key"}, "name": {"main"}}},
+		},
+		"Protect": {
+			reason: "SetAutomaticDeletionProtection should send the project, the branch and the protection.",
+			call: func(ctx context.Context, c ProjectBranchClient) error {
+				return c.SetAutomaticDeletionProtection(ctx, "key", "release/1.x", true)
+			},
+			want: want{path: "/api/project_branches/set_automatic_deletion_protection", params: url.Values{"project": {"key"}, "branch": {"release/1.x"}, "value": {"true"}}},
+		},
+		"Unprotect": {
+			reason: "SetAutomaticDeletionProtection should send a lifted protection as false.",
+			call: func(ctx context.Context, c ProjectBranchClient) error {
+				return c.SetAutomaticDeletionProtection(ctx, "key", "release/1.x", false)
+			},
+			want: want{path: "/api/project_branches/set_automatic_deletion_protection", params: url.Values{"project": {"key"}, "branch": {"release/1.x"}, "value": {"false"}}},
+		},
+		"Delete": {
+			reason: "Delete should send the project and the branch.",
+			call: func(ctx context.Context, c ProjectBranchClient) error {
+				return c.Delete(ctx, "key", "feature/payments")
+			},
+			want: want{path: "/api/project_branches/delete", params: url.Values{"project": {"key"}, "branch": {"feature/payments"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.Method != http.MethodPost {
+					t.Errorf("%s: want method POST, got %s", name, r.Method)
+				}
+				got = want{path: r.URL.Path, params: r.Form}
+			}))
+			defer srv.Close()
+
+			c := NewProjectBranchClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if err := tc.call(context.Background(), c); err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
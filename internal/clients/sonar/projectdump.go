@@ -0,0 +1,57 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+)
+
+type ProjectDumpClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Project Dump Client
+func NewProjectDumpClient(options SonarApiOptions) ProjectDumpClient {
+	return ProjectDumpClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// ProjectDumpTask is the background task started by an export or an import.
+type ProjectDumpTask struct {
+	TaskId      string `json:"taskId"`
+	ProjectKey  string `json:"projectKey,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+}
+
+// Export a project to a dump file on the server. Only available on the
+// Enterprise edition and above.
+// https://next.sonarqube.com/sonarqube/web_api/api/project_dump/export
+func (projectDumpClient ProjectDumpClient) Export(ctx context.Context, projectKey string) (ProjectDumpTask, error) {
+	return projectDumpClient.start(ctx, "/api/project_dump/export", projectKey)
+}
+
+// Import a project from the dump file placed on the server. The project must
+// exist and never have been analyzed. Only available on the Enterprise edition
+// and above.
+// https://next.sonarqube.com/sonarqube/web_api/api/project_dump/import
+func (projectDumpClient ProjectDumpClient) Import(ctx context.Context, projectKey string) (ProjectDumpTask, error) {
+	return projectDumpClient.start(ctx, "/api/project_dump/import", projectKey)
+}
+
+func (projectDumpClient ProjectDumpClient) start(ctx context.Context, path string, projectKey string) (ProjectDumpTask, error) {
+	params := url.Values{}
+	params.Add("key", projectKey)
+
+	var task ProjectDumpTask
+	err := projectDumpClient.sonarApi.do(ctx, "POST", path, params, &task)
+	if isUnknownUrl(err) {
+		return ProjectDumpTask{}, unsupportedEdition(ctx, projectDumpClient.sonarApi, "project dumps", EditionEnterprise)
+	}
+	return task, err
+}
+
+// WaitForTask waits for the background task of an export or an import to
+// finish. It returns ErrCeTaskFailed when the task failed or was canceled.
+func (projectDumpClient ProjectDumpClient) WaitForTask(ctx context.Context, taskId string) (CeTask, error) {
+	return CeClient{sonarApi: projectDumpClient.sonarApi}.WaitForTask(ctx, taskId)
+}
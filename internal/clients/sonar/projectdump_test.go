@@ -0,0 +1,64 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProjectDumpStart(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, c ProjectDumpClient) (ProjectDumpTask, error)
+		want   string
+	}{
+		"Export": {
+			reason: "Export should start an export of the project.",
+			call: func(ctx context.Context, c ProjectDumpClient) (ProjectDumpTask, error) {
+				return c.Export(ctx, "key")
+			},
+			want: "/api/project_dump/export",
+		},
+		"Import": {
+			reason: "Import should start an import of the project.",
+			call: func(ctx context.Context, c ProjectDumpClient) (ProjectDumpTask, error) {
+				return c.Import(ctx, "key")
+			},
+			want: "/api/project_dump/import",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path, method string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				path, method, params = r.URL.Path, r.Method, r.Form
+				_, _ = w.Write([]byte(`{"taskId":"AU-Tpxb--iU5OvuD2FLy","projectKey":"key","projectName":"Name"}`))
+			}))
+			defer srv.Close()
+
+			got, err := tc.call(context.Background(), NewProjectDumpClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL}))
+			if err != nil {
+				t.Fatalf("\n%s\nunexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, path); diff != "" {
+				t.Errorf("\n%s\n-want path, +got path:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff("POST", method); diff != "" {
+				t.Errorf("\n%s\n-want method, +got method:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(url.Values{"key": {"key"}}, params); diff != "" {
+				t.Errorf("\n%s\n-want params, +got params:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(ProjectDumpTask{TaskId: "AU-Tpxb--iU5OvuD2FLy", ProjectKey: "key", ProjectName: "Name"}, got); diff != "" {
+				t.Errorf("\n%s\n-want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+)
+
+// ProjectLinkTypeCustom is the type of the links created through the API.
+// Links of the other types, e.g. homepage or scm, are provided by the scanner.
+const ProjectLinkTypeCustom = "custom"
+
+type ProjectLink struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+type ProjectLinkClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Project Link Client
+func NewProjectLinkClient(options SonarApiOptions) ProjectLinkClient {
+	return ProjectLinkClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Search the links of a project
+// https://sonarcloud.io/web_api/api/project_links/search
+func (projectLinkClient ProjectLinkClient) Search(ctx context.Context, projectKey string) ([]ProjectLink, error) {
+	params := url.Values{}
+	params.Add("projectKey", projectKey)
+
+	var response struct {
+		Links []ProjectLink `json:"links"`
+	}
+	if err := projectLinkClient.sonarApi.do(ctx, "GET", "/api/project_links/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Links, nil
+}
+
+// Create new link on a project
+// https://sonarcloud.io/web_api/api/project_links/create
+func (projectLinkClient ProjectLinkClient) Create(ctx context.Context, projectKey string, name string, linkUrl string) (ProjectLink, error) {
+	params := url.Values{}
+	params.Add("projectKey", projectKey)
+	params.Add("name", name)
+	params.Add("url", linkUrl)
+
+	var response struct {
+		Link ProjectLink `json:"link"`
+	}
+	err := projectLinkClient.sonarApi.do(ctx, "POST", "/api/project_links/create", params, &response)
+	return response.Link, err
+}
+
+// Delete a link of a project, identified by its id
+// https://sonarcloud.io/web_api/api/project_links/delete
+func (projectLinkClient ProjectLinkClient) Delete(ctx context.Context, id string) error {
+	params := url.Values{}
+	params.Add("id", id)
+
+	return projectLinkClient.sonarApi.do(ctx, "POST", "/api/project_links/delete", params, nil)
+}
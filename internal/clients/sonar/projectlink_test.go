@@ -0,0 +1,98 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSearchProjectLinks(t *testing.T) {
+	response := `{
+		"links": [
+			{"id": "1", "type": "homepage", "url": "https://example.com"},
+			{"id": "2", "name": "CI", "type": "custom", "url": "https://ci.example.com/payments"}
+		]
+	}`
+
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewProjectLinkClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Search(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("c.Search(...): unexpected error: %v", err)
+	}
+
+	want := []ProjectLink{
+		{Id: "1", Type: "homepage", Url: "https://example.com"},
+		{Id: "2", Name: "CI", Type: ProjectLinkTypeCustom, Url: "https://ci.example.com/payments"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.Search(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"projectKey": {"key"}}, params); diff != "" {
+		t.Errorf("c.Search(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestCreateProjectLink(t *testing.T) {
+	var method string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method = r.Method
+		params = r.Form
+		_, _ = w.Write([]byte(`{"link":{"id":"3","name":"Issues","url":"https://issues.example.com"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewProjectLinkClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Create(context.Background(), "key", "Issues", "https://issues.example.com")
+	if err != nil {
+		t.Fatalf("c.Create(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(ProjectLink{Id: "3", Name: "Issues", Url: "https://issues.example.com"}, got); diff != "" {
+		t.Errorf("c.Create(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("POST", method); diff != "" {
+		t.Errorf("c.Create(...): -want method, +got method:\n%s\n", diff)
+	}
+	want := url.Values{"projectKey": {"key"}, "name": {"Issues"}, "url": {"https://issues.example.com"}}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("c.Create(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestDeleteProjectLink(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewProjectLinkClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.Delete(context.Background(), "3"); err != nil {
+		t.Fatalf("c.Delete(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff("/api/project_links/delete", path); diff != "" {
+		t.Errorf("c.Delete(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"id": {"3"}}, params); diff != "" {
+		t.Errorf("c.Delete(...): -want params, +got params:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,121 @@
+package sonar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+)
+
+const (
+	errGetCreds      = "cannot get credentials"
+	errBaseURL       = "invalid ProviderConfig base URL"
+	errCABundle      = "cannot load ProviderConfig CA bundle"
+	errValidateCreds = "cannot validate credentials"
+)
+
+// versionCacheTTL is how long the detected version of an instance is used
+// before it is detected again, e.g. after an upgrade.
+const versionCacheTTL = time.Hour
+
+// versionCache holds the versions detected by OptionsFromProviderConfig, keyed
+// like the authentication circuit by the credentials they were detected with,
+// since clients are created for every reconcile.
+type versionCache struct {
+	mu       sync.Mutex
+	versions map[string]cachedVersion
+	now      func() time.Time
+}
+
+type cachedVersion struct {
+	version string
+	expires time.Time
+}
+
+var serverVersions = &versionCache{versions: map[string]cachedVersion{}, now: time.Now}
+
+// get returns the version of the instance of options, detecting it with
+// SystemClient.Version unless it was detected recently. A version that cannot
+// be detected is not cached, and returned empty so that the endpoints
+// supported by every version are used.
+func (c *versionCache) get(ctx context.Context, options SonarApiOptions) string {
+	key := breakerKey(options)
+
+	c.mu.Lock()
+	v, ok := c.versions[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(v.expires) {
+		return v.version
+	}
+
+	version, err := NewSystemClient(options).Version(ctx)
+	if err != nil {
+		if options.Logger != nil {
+			options.Logger.Debug("Cannot detect the version of the instance", "error", err)
+		}
+		return ""
+	}
+
+	c.mu.Lock()
+	c.versions[key] = cachedVersion{version: version, expires: c.now().Add(versionCacheTTL)}
+	c.mu.Unlock()
+	return version
+}
+
+// OptionsFromProviderConfig returns the options of the clients of the managed
+// resources that use a ProviderConfig: its token, read from its credentials,
+// its base URL and CA bundle, and the settings of its requests. Requests are
+// logged to logger, the logger of the controller. The credentials are checked
+// against the instance when the ProviderConfig validates them. The version of
+// a SonarQube instance is detected, which selects the search endpoints of
+// SonarQube 10 and later.
+func OptionsFromProviderConfig(ctx context.Context, kube client.Client, pc *apisv1alpha1.ProviderConfig, logger logging.Logger) (SonarApiOptions, error) {
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return SonarApiOptions{}, fmt.Errorf("%s: %w", errGetCreds, err)
+	}
+	token, err := CredentialsToken(data)
+	if err != nil {
+		return SonarApiOptions{}, fmt.Errorf("%s: %w", errGetCreds, err)
+	}
+
+	if err := ValidateBaseUrl(pc.Spec.BaseURL); err != nil {
+		return SonarApiOptions{}, fmt.Errorf("%s: %w", errBaseURL, err)
+	}
+
+	caBundle, err := LoadCABundle(ctx, kube, pc.Spec.CABundleSecretRef)
+	if err != nil {
+		return SonarApiOptions{}, fmt.Errorf("%s: %w", errCABundle, err)
+	}
+
+	options := SonarApiOptions{
+		Key:                  token,
+		BaseUrl:              pc.Spec.BaseURL,
+		Organization:         pc.Spec.Organization,
+		InstanceMode:         InstanceMode(pc.Spec.InstanceMode),
+		CABundle:             caBundle,
+		AuthMode:             AuthMode(pc.Spec.AuthMode),
+		TokenType:            pc.Spec.TokenType,
+		RequestsPerSecond:    float64(pc.Spec.RequestsPerSecond),
+		AuthFailureForbidden: pc.Spec.CountForbiddenAsAuthFailure,
+		Logger:               logger,
+	}
+	if pc.Spec.ValidateCredentials {
+		if err := NewSystemClient(options).ValidateCredentials(ctx); err != nil {
+			return SonarApiOptions{}, fmt.Errorf("%s: %w", errValidateCreds, err)
+		}
+	}
+	// SonarCloud has a single version, supported by the default endpoints.
+	if options.InstanceMode == InstanceModeSonarQube {
+		options.ServerVersion = serverVersions.get(ctx, options)
+	}
+	return options, nil
+}
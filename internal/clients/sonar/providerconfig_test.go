@@ -0,0 +1,459 @@
+package sonar
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+)
+
+func TestOptionsFromProviderConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		pc        *apisv1alpha1.ProviderConfig
+		secretErr error
+	}
+
+	type want struct {
+		options SonarApiOptions
+		err     error
+	}
+
+	tlsSrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsSrv.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsSrv.Certificate().Raw})
+
+	validate := func(valid string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/authentication/validate" {
+				t.Errorf("OptionsFromProviderConfig(...): unexpected request %s", r.URL.Path)
+			}
+			_, _ = w.Write([]byte(`{"valid":` + valid + `}`))
+		}))
+	}
+	validSrv, invalidSrv := validate("true"), validate("false")
+	defer validSrv.Close()
+	defer invalidSrv.Close()
+
+	secret := func(obj client.Object) error {
+		if s, ok := obj.(*corev1.Secret); ok {
+			s.Data = map[string][]byte{"credentials": []byte("token"), "padded": []byte("token\n"), "empty": []byte(" \n"), "ca.crt": caBundle}
+		}
+		return nil
+	}
+
+	caBundleRef := func(key string) *xpv1.SecretKeySelector {
+		return &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "sonar-ca", Namespace: "crossplane-system"},
+			Key:             key,
+		}
+	}
+
+	credentials := apisv1alpha1.ProviderCredentials{
+		Source: xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+			SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: "sonar", Namespace: "crossplane-system"},
+				Key:             "credentials",
+			},
+		},
+	}
+
+	// Tokens mounted from files, or set in the environment from them, often
+	// end with a newline.
+	t.Setenv("SONAR_TOKEN", "token\n")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SecretCredentialsTrimmed": {
+			reason: "A token read from a secret should be used without its trailing newline.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+						Source: xpv1.CredentialsSourceSecret,
+						CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+							SecretRef: &xpv1.SecretKeySelector{
+								SecretReference: xpv1.SecretReference{Name: "sonar", Namespace: "crossplane-system"},
+								Key:             "padded",
+							},
+						},
+					}},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token"},
+			},
+		},
+		"EmptyCredentials": {
+			reason: "Credentials that hold no token should be rejected, without forming a client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+						Source: xpv1.CredentialsSourceSecret,
+						CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+							SecretRef: &xpv1.SecretKeySelector{
+								SecretReference: xpv1.SecretReference{Name: "sonar", Namespace: "crossplane-system"},
+								Key:             "empty",
+							},
+						},
+					}},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("%s: %w", errGetCreds, ErrEmptyCredentials),
+			},
+		},
+		"EnvironmentCredentials": {
+			reason: "A token read from an environment variable should be used as the key, without its trailing newline.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+						Source: xpv1.CredentialsSourceEnvironment,
+						CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+							Env: &xpv1.EnvSelector{Name: "SONAR_TOKEN"},
+						},
+					}},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token"},
+			},
+		},
+		"FilesystemCredentials": {
+			reason: "A token read from a file should be used as the key, without its trailing newline.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+						Source: xpv1.CredentialsSourceFilesystem,
+						CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+							Fs: &xpv1.FsSelector{Path: tokenFile},
+						},
+					}},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token"},
+			},
+		},
+		"DefaultBaseURL": {
+			reason: "The client should fall back to the SonarCloud default when the ProviderConfig omits a base URL.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: credentials},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token"},
+			},
+		},
+		"CustomBaseURL": {
+			reason: "The base URL configured on the ProviderConfig should be passed to the client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials: credentials,
+						BaseURL:     "https://sonarqube.example.org",
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", BaseUrl: "https://sonarqube.example.org"},
+			},
+		},
+		"CountForbiddenAsAuthFailure": {
+			reason: "403 responses should count as authentication failures when the ProviderConfig asks for it.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:                 credentials,
+						CountForbiddenAsAuthFailure: true,
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", AuthFailureForbidden: true},
+			},
+		},
+		"AuthMode": {
+			reason: "The credentials should be passed as the key, with the auth mode configured on the ProviderConfig.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials: credentials,
+						BaseURL:     "https://sonarqube.example.org",
+						AuthMode:    "bearer",
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", BaseUrl: "https://sonarqube.example.org", AuthMode: AuthModeBearer},
+			},
+		},
+		"GetCredentialsError": {
+			reason: "An error extracting the credentials should be returned, without forming a client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{Credentials: credentials},
+				},
+				secretErr: errBoom,
+			},
+			want: want{
+				err: fmt.Errorf("%s: cannot get credentials secret: %w", errGetCreds, errBoom),
+			},
+		},
+		"DefaultOrganization": {
+			reason: "The organization configured on the ProviderConfig should be passed to the client as the default.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:  credentials,
+						Organization: "gbsandbox",
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", Organization: "gbsandbox"},
+			},
+		},
+		"CABundle": {
+			reason: "The CA bundle referenced by the ProviderConfig should be passed to the client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:       credentials,
+						CABundleSecretRef: caBundleRef("ca.crt"),
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", CABundle: caBundle},
+			},
+		},
+		"MissingCABundle": {
+			reason: "A CA bundle reference to a missing key should be rejected, without forming a client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:       credentials,
+						CABundleSecretRef: caBundleRef("tls.crt"),
+					},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("%s: %w", errCABundle, errors.New(`secret crossplane-system/sonar-ca has no key "tls.crt"`)),
+			},
+		},
+		"InvalidCABundle": {
+			reason: "A CA bundle without any PEM encoded certificate should be rejected, without forming a client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:       credentials,
+						CABundleSecretRef: caBundleRef("credentials"),
+					},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("%s: %w", errCABundle, errors.New("invalid CA bundle: no PEM encoded certificate found")),
+			},
+		},
+		"ValidCredentials": {
+			reason: "Credentials accepted by the API should form a client when they are validated.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:         credentials,
+						BaseURL:             validSrv.URL,
+						ValidateCredentials: true,
+					},
+				},
+			},
+			want: want{
+				options: SonarApiOptions{Key: "token", BaseUrl: validSrv.URL},
+			},
+		},
+		"InvalidCredentials": {
+			reason: "Credentials rejected by the API should fail the connection when they are validated, without forming a client.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials:         credentials,
+						BaseURL:             invalidSrv.URL,
+						ValidateCredentials: true,
+					},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("%s: %w", errValidateCreds, ErrInvalidCredentials),
+			},
+		},
+		"InvalidBaseURL": {
+			reason: "A base URL that is not an absolute http or https URL should be rejected.",
+			args: args{
+				pc: &apisv1alpha1.ProviderConfig{
+					Spec: apisv1alpha1.ProviderConfigSpec{
+						Credentials: credentials,
+						BaseURL:     "ftp://sonarqube.example.org",
+					},
+				},
+			},
+			want: want{
+				err: fmt.Errorf("%s: %w", errBaseURL, errors.New(`base url "ftp://sonarqube.example.org" must use the http or https scheme`)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.args.secretErr != nil {
+						return tc.args.secretErr
+					}
+					return secret(obj)
+				},
+			}
+
+			got, err := OptionsFromProviderConfig(context.Background(), kube, tc.args.pc, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOptionsFromProviderConfig(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.options, got); diff != "" {
+				t.Errorf("\n%s\nOptionsFromProviderConfig(...): -want options, +got options:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOptionsFromProviderConfigLogger(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok {
+				s.Data = map[string][]byte{"credentials": []byte("token")}
+			}
+			return nil
+		},
+	}
+	pc := &apisv1alpha1.ProviderConfig{
+		Spec: apisv1alpha1.ProviderConfigSpec{Credentials: apisv1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{Key: "credentials"},
+			},
+		}},
+	}
+
+	// The requests of every controller are logged to its own logger.
+	logger := logging.NewNopLogger().WithValues("controller", "test")
+	options, err := OptionsFromProviderConfig(context.Background(), kube, pc, logger)
+	if err != nil {
+		t.Fatalf("OptionsFromProviderConfig(...): unexpected error: %v", err)
+	}
+	if options.Logger != logger {
+		t.Errorf("OptionsFromProviderConfig(...): want the supplied logger, got %v", options.Logger)
+	}
+}
+
+func TestOptionsFromProviderConfigServerVersion(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/server/version":
+			_, _ = w.Write([]byte("10.2.1.78527"))
+		case "/api/projects/search_v2":
+			_, _ = w.Write([]byte(`{"components":[{"key":"key","name":"Name"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok {
+				s.Data = map[string][]byte{"credentials": []byte("token")}
+			}
+			return nil
+		},
+	}
+	pc := func(mode string) *apisv1alpha1.ProviderConfig {
+		return &apisv1alpha1.ProviderConfig{
+			Spec: apisv1alpha1.ProviderConfigSpec{
+				Credentials: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+						SecretRef: &xpv1.SecretKeySelector{Key: "credentials"},
+					},
+				},
+				BaseURL:      srv.URL,
+				InstanceMode: mode,
+			},
+		}
+	}
+
+	t.Run("SonarCloud", func(t *testing.T) {
+		got = nil
+		options, err := OptionsFromProviderConfig(context.Background(), kube, pc(string(InstanceModeSonarCloud)), nil)
+		if err != nil {
+			t.Fatalf("OptionsFromProviderConfig(...): unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("", options.ServerVersion); diff != "" {
+			t.Errorf("OptionsFromProviderConfig(...): -want version, +got version:\n%s\n", diff)
+		}
+		if diff := cmp.Diff([]string(nil), got); diff != "" {
+			t.Errorf("OptionsFromProviderConfig(...): the version of SonarCloud should not be detected: -want requests, +got requests:\n%s\n", diff)
+		}
+	})
+
+	t.Run("SonarQube", func(t *testing.T) {
+		got = nil
+		// The version is detected once for the credentials, rather than for
+		// every reconcile.
+		var options SonarApiOptions
+		for i := 0; i < 2; i++ {
+			var err error
+			options, err = OptionsFromProviderConfig(context.Background(), kube, pc(string(InstanceModeSonarQube)), nil)
+			if err != nil {
+				t.Fatalf("OptionsFromProviderConfig(...): unexpected error: %v", err)
+			}
+		}
+		if diff := cmp.Diff("10.2.1.78527", options.ServerVersion); diff != "" {
+			t.Errorf("OptionsFromProviderConfig(...): -want version, +got version:\n%s\n", diff)
+		}
+
+		// The detected version selects the search endpoint of SonarQube 10.
+		projects, err := NewProjectClient(options).SearchAll(context.Background(), "", SearchOptions{})
+		if err != nil {
+			t.Fatalf("SearchAll(...): unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]Project{{Key: "key", Name: "Name"}}, projects); diff != "" {
+			t.Errorf("SearchAll(...): -want, +got:\n%s\n", diff)
+		}
+		want := []string{"/api/server/version", "/api/projects/search_v2"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("-want requests, +got requests:\n%s\n", diff)
+		}
+	})
+}
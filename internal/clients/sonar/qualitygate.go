@@ -0,0 +1,329 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+var ErrQualityGateNotFound = errors.New("Quality gate not found")
+
+// ID is an identifier returned by the Sonar API. Older SonarQube versions
+// return numeric identifiers while SonarCloud returns strings, so both are
+// accepted and kept as a string.
+type ID string
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = ID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = ID(n.String())
+	return nil
+}
+
+type QualityGateCondition struct {
+	Id     ID     `json:"id,omitempty"`
+	Metric string `json:"metric"`
+	Op     string `json:"op"`
+	Error  string `json:"error"`
+}
+
+type QualityGate struct {
+	Id         ID                     `json:"id"`
+	Name       string                 `json:"name"`
+	IsBuiltIn  bool                   `json:"isBuiltIn"`
+	IsDefault  bool                   `json:"isDefault"`
+	Conditions []QualityGateCondition `json:"conditions"`
+}
+
+// Operators of a quality gate condition.
+const (
+	ConditionOpLessThan    = "LT"
+	ConditionOpGreaterThan = "GT"
+)
+
+type QualityGateClient struct {
+	sonarApi SonarApi
+	// metrics caches the metrics catalog that conditions are validated
+	// against, shared by the copies of this client.
+	metrics *metricCatalog
+}
+
+// metricCatalog holds the metrics of the instance, keyed by metric key, once
+// they were loaded.
+type metricCatalog struct {
+	mu      sync.Mutex
+	metrics map[string]Metric
+}
+
+// Creates a new Quality Gate Client
+func NewQualityGateClient(options SonarApiOptions) QualityGateClient {
+	return QualityGateClient{
+		sonarApi: NewSonarApi(options),
+		metrics:  &metricCatalog{},
+	}
+}
+
+// Create new quality gate
+// https://sonarcloud.io/web_api/api/qualitygates/create
+func (qualityGateClient QualityGateClient) Create(ctx context.Context, organization string, name string) (QualityGate, error) {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("name", name)
+
+	var gate QualityGate
+	err := qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/create", params, &gate)
+	return gate, err
+}
+
+// Delete quality gate
+// https://sonarcloud.io/web_api/api/qualitygates/destroy
+func (qualityGateClient QualityGateClient) Delete(ctx context.Context, organization string, id string) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("id", id)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/destroy", params, nil)
+}
+
+// Get a single quality gate, including its conditions, by name
+// https://sonarcloud.io/web_api/api/qualitygates/show
+func (qualityGateClient QualityGateClient) GetByName(ctx context.Context, organization string, name string) (QualityGate, error) {
+	return qualityGateClient.show(ctx, organization, "name", name)
+}
+
+// Get a single quality gate, including its conditions, by id. Unlike its name,
+// the id of a quality gate never changes.
+// https://sonarcloud.io/web_api/api/qualitygates/show
+func (qualityGateClient QualityGateClient) GetById(ctx context.Context, organization string, id string) (QualityGate, error) {
+	return qualityGateClient.show(ctx, organization, "id", id)
+}
+
+func (qualityGateClient QualityGateClient) show(ctx context.Context, organization string, param string, value string) (QualityGate, error) {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add(param, value)
+
+	var gate QualityGate
+	err := qualityGateClient.sonarApi.do(ctx, "GET", "/api/qualitygates/show", params, &gate)
+
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return QualityGate{}, ErrQualityGateNotFound
+	}
+	if err != nil {
+		return QualityGate{}, err
+	}
+
+	return gate, nil
+}
+
+// Rename a quality gate, identified by its id, keeping its conditions
+// https://sonarcloud.io/web_api/api/qualitygates/rename
+func (qualityGateClient QualityGateClient) Rename(ctx context.Context, organization string, id string, name string) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("id", id)
+	params.Add("name", name)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/rename", params, nil)
+}
+
+// ValidateConditions checks that the metric of every condition exists and can
+// be used with the operator of the condition, returning an
+// *InvalidConditionError for the first one that cannot. The metrics catalog is
+// loaded on first use and cached by the client.
+func (qualityGateClient QualityGateClient) ValidateConditions(ctx context.Context, conditions []QualityGateCondition) error {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	metrics, err := qualityGateClient.metricCatalog(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		if err := validateCondition(c, metrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (qualityGateClient QualityGateClient) metricCatalog(ctx context.Context) (map[string]Metric, error) {
+	catalog := qualityGateClient.metrics
+	if catalog == nil {
+		catalog = &metricCatalog{}
+	}
+
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	if catalog.metrics != nil {
+		return catalog.metrics, nil
+	}
+
+	metrics, err := MetricsClient{sonarApi: qualityGateClient.sonarApi}.Search(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load metrics catalog: %w", err)
+	}
+	catalog.metrics = make(map[string]Metric, len(metrics))
+	for _, m := range metrics {
+		catalog.metrics[m.Key] = m
+	}
+	return catalog.metrics, nil
+}
+
+// validateCondition checks a condition against the rules the API enforces:
+// the metric must exist, be visible and numeric, and rating metrics only
+// accept GT, e.g. worse than A.
+func validateCondition(condition QualityGateCondition, metrics map[string]Metric) error {
+	invalid := func(reason string, args ...any) error {
+		return &InvalidConditionError{Condition: condition, Reason: fmt.Sprintf(reason, args...)}
+	}
+
+	m, ok := metrics[condition.Metric]
+	if !ok {
+		return invalid("metric does not exist")
+	}
+	if m.Hidden {
+		return invalid("metric is hidden")
+	}
+	switch m.Type {
+	case MetricTypeBool, MetricTypeData, MetricTypeDistrib, MetricTypeString:
+		return invalid("metrics of type %s cannot be used in conditions", m.Type)
+	}
+	if condition.Op != ConditionOpLessThan && condition.Op != ConditionOpGreaterThan {
+		return invalid("operator must be %s or %s", ConditionOpLessThan, ConditionOpGreaterThan)
+	}
+	if m.Type == MetricTypeRating && condition.Op != ConditionOpGreaterThan {
+		return invalid("metrics of type %s only accept the %s operator", MetricTypeRating, ConditionOpGreaterThan)
+	}
+	return nil
+}
+
+// Add a condition to a quality gate
+// https://sonarcloud.io/web_api/api/qualitygates/create_condition
+func (qualityGateClient QualityGateClient) CreateCondition(ctx context.Context, organization string, gateName string, condition QualityGateCondition) (QualityGateCondition, error) {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("gateName", gateName)
+	params.Add("metric", condition.Metric)
+	params.Add("op", condition.Op)
+	params.Add("error", condition.Error)
+
+	var created QualityGateCondition
+	err := qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/create_condition", params, &created)
+	return created, err
+}
+
+// Update a condition of a quality gate, identified by its id
+// https://sonarcloud.io/web_api/api/qualitygates/update_condition
+func (qualityGateClient QualityGateClient) UpdateCondition(ctx context.Context, organization string, condition QualityGateCondition) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("id", string(condition.Id))
+	params.Add("metric", condition.Metric)
+	params.Add("op", condition.Op)
+	params.Add("error", condition.Error)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/update_condition", params, nil)
+}
+
+// Remove a condition from a quality gate
+// https://sonarcloud.io/web_api/api/qualitygates/delete_condition
+func (qualityGateClient QualityGateClient) DeleteCondition(ctx context.Context, organization string, id string) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("id", id)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/delete_condition", params, nil)
+}
+
+// Select the quality gate of a project
+// https://sonarcloud.io/web_api/api/qualitygates/select
+func (qualityGateClient QualityGateClient) SelectForProject(ctx context.Context, organization string, gateName string, project string) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("gateName", gateName)
+	params.Add("projectKey", project)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/select", params, nil)
+}
+
+// Get the quality gate of a project
+// https://sonarcloud.io/web_api/api/qualitygates/get_by_project
+func (qualityGateClient QualityGateClient) GetGateForProject(ctx context.Context, organization string, project string) (QualityGate, error) {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("project", project)
+
+	var response struct {
+		QualityGate QualityGate `json:"qualityGate"`
+	}
+	if err := qualityGateClient.sonarApi.do(ctx, "GET", "/api/qualitygates/get_by_project", params, &response); err != nil {
+		return QualityGate{}, err
+	}
+	return response.QualityGate, nil
+}
+
+// List the quality gates
+// https://sonarcloud.io/web_api/api/qualitygates/list
+func (qualityGateClient QualityGateClient) List(ctx context.Context, organization string) ([]QualityGate, error) {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+
+	var response struct {
+		QualityGates []QualityGate `json:"qualitygates"`
+		// Default is the id of the default quality gate, returned instead of
+		// isDefault by older SonarQube versions.
+		Default ID `json:"default"`
+	}
+	if err := qualityGateClient.sonarApi.do(ctx, "GET", "/api/qualitygates/list", params, &response); err != nil {
+		return nil, err
+	}
+
+	for i := range response.QualityGates {
+		if response.Default != "" && response.QualityGates[i].Id == response.Default {
+			response.QualityGates[i].IsDefault = true
+		}
+	}
+	return response.QualityGates, nil
+}
+
+// GetDefault returns the default quality gate, which applies to the projects
+// that did not select one.
+func (qualityGateClient QualityGateClient) GetDefault(ctx context.Context, organization string) (QualityGate, error) {
+	gates, err := qualityGateClient.List(ctx, organization)
+	if err != nil {
+		return QualityGate{}, err
+	}
+
+	for _, gate := range gates {
+		if gate.IsDefault {
+			return gate, nil
+		}
+	}
+	return QualityGate{}, ErrQualityGateNotFound
+}
+
+// SetAsDefault makes a quality gate the default, in place of the current one
+// https://sonarcloud.io/web_api/api/qualitygates/set_as_default
+func (qualityGateClient QualityGateClient) SetAsDefault(ctx context.Context, organization string, gateName string) error {
+	params := url.Values{}
+	qualityGateClient.sonarApi.addOrganization(params, organization)
+	params.Add("name", gateName)
+
+	return qualityGateClient.sonarApi.do(ctx, "POST", "/api/qualitygates/set_as_default", params, nil)
+}
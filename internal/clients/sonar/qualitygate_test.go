@@ -0,0 +1,340 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQualityGateConditions(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+		result QualityGateCondition
+	}
+
+	cases := map[string]struct {
+		reason   string
+		response string
+		call     func(ctx context.Context, c QualityGateClient) (QualityGateCondition, error)
+		want     want
+	}{
+		"CreateCondition": {
+			reason:   "CreateCondition should send the gate name and condition and return the created condition with its id.",
+			response: `{"id":"AXJ1","metric":"new_coverage","op":"LT","error":"80"}`,
+			call: func(ctx context.Context, c QualityGateClient) (QualityGateCondition, error) {
+				return c.CreateCondition(ctx, "org", "gate", QualityGateCondition{Metric: "new_coverage", Op: "LT", Error: "80"})
+			},
+			want: want{
+				path:   "/api/qualitygates/create_condition",
+				params: url.Values{"organization": {"org"}, "gateName": {"gate"}, "metric": {"new_coverage"}, "op": {"LT"}, "error": {"80"}},
+				result: QualityGateCondition{Id: "AXJ1", Metric: "new_coverage", Op: "LT", Error: "80"},
+			},
+		},
+		"CreateConditionNumericID": {
+			reason:   "CreateCondition should accept the numeric ids returned by older SonarQube versions.",
+			response: `{"id":42,"metric":"new_coverage","op":"LT","error":"80"}`,
+			call: func(ctx context.Context, c QualityGateClient) (QualityGateCondition, error) {
+				return c.CreateCondition(ctx, "org", "gate", QualityGateCondition{Metric: "new_coverage", Op: "LT", Error: "80"})
+			},
+			want: want{
+				path:   "/api/qualitygates/create_condition",
+				params: url.Values{"organization": {"org"}, "gateName": {"gate"}, "metric": {"new_coverage"}, "op": {"LT"}, "error": {"80"}},
+				result: QualityGateCondition{Id: "42", Metric: "new_coverage", Op: "LT", Error: "80"},
+			},
+		},
+		"UpdateCondition": {
+			reason: "UpdateCondition should target the condition by its id.",
+			call: func(ctx context.Context, c QualityGateClient) (QualityGateCondition, error) {
+				return QualityGateCondition{}, c.UpdateCondition(ctx, "org", QualityGateCondition{Id: "AXJ1", Metric: "new_coverage", Op: "LT", Error: "90"})
+			},
+			want: want{
+				path:   "/api/qualitygates/update_condition",
+				params: url.Values{"organization": {"org"}, "id": {"AXJ1"}, "metric": {"new_coverage"}, "op": {"LT"}, "error": {"90"}},
+			},
+		},
+		"DeleteCondition": {
+			reason: "DeleteCondition should target the condition by its id.",
+			call: func(ctx context.Context, c QualityGateClient) (QualityGateCondition, error) {
+				return QualityGateCondition{}, c.DeleteCondition(ctx, "org", "AXJ1")
+			},
+			want: want{
+				path:   "/api/qualitygates/delete_condition",
+				params: url.Values{"organization": {"org"}, "id": {"AXJ1"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				path = r.URL.Path
+				params = r.Form
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := tc.call(context.Background(), c)
+			if err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want path, +got path:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want params, +got params:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestQualityGateGetByNameNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.GetByName(context.Background(), "org", "gate"); err != ErrQualityGateNotFound {
+		t.Errorf("GetByName(...): want ErrQualityGateNotFound, got %v", err)
+	}
+}
+
+func TestQualityGateForProject(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		_, _ = w.Write([]byte(`{"qualityGate":{"id":"AXG1","name":"strict","default":false}}`))
+	}))
+	defer srv.Close()
+
+	c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+
+	if err := c.SelectForProject(context.Background(), "org", "strict", "key"); err != nil {
+		t.Fatalf("SelectForProject(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("/api/qualitygates/select", path); diff != "" {
+		t.Errorf("SelectForProject(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"organization": {"org"}, "gateName": {"strict"}, "projectKey": {"key"}}, params); diff != "" {
+		t.Errorf("SelectForProject(...): -want params, +got params:\n%s\n", diff)
+	}
+
+	got, err := c.GetGateForProject(context.Background(), "org", "key")
+	if err != nil {
+		t.Fatalf("GetGateForProject(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("/api/qualitygates/get_by_project", path); diff != "" {
+		t.Errorf("GetGateForProject(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"organization": {"org"}, "project": {"key"}}, params); diff != "" {
+		t.Errorf("GetGateForProject(...): -want params, +got params:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(QualityGate{Id: "AXG1", Name: "strict"}, got); diff != "" {
+		t.Errorf("GetGateForProject(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestQualityGateGetDefault(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		response string
+		want     string
+	}{
+		"IsDefault": {
+			reason:   "The gate flagged isDefault should be returned.",
+			response: `{"qualitygates":[{"id":"1","name":"Sonar way","isBuiltIn":true,"isDefault":false},{"id":"2","name":"strict","isDefault":true}]}`,
+			want:     "strict",
+		},
+		"DefaultId": {
+			reason:   "The gate whose id is the default of older versions should be returned.",
+			response: `{"qualitygates":[{"id":1,"name":"Sonar way","isBuiltIn":true},{"id":2,"name":"strict"}],"default":1}`,
+			want:     "Sonar way",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.GetDefault(context.Background(), "org")
+			if err != nil {
+				t.Fatalf("\n%s\nc.GetDefault(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got.Name); diff != "" {
+				t.Errorf("\n%s\nc.GetDefault(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestQualityGateSetAsDefault(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.SetAsDefault(context.Background(), "org", "strict"); err != nil {
+		t.Fatalf("c.SetAsDefault(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("/api/qualitygates/set_as_default", path); diff != "" {
+		t.Errorf("c.SetAsDefault(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"organization": {"org"}, "name": {"strict"}}, params); diff != "" {
+		t.Errorf("c.SetAsDefault(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestQualityGateRename(t *testing.T) {
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params := r.Form
+		params.Set("path", r.URL.Path)
+		requests = append(requests, params)
+		if r.URL.Path == "/api/qualitygates/show" {
+			_, _ = w.Write([]byte(`{"id":"AU-Tpxb--iU5OvuD2FLy","name":"stricter","conditions":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.Rename(context.Background(), "org", "AU-Tpxb--iU5OvuD2FLy", "stricter"); err != nil {
+		t.Fatalf("c.Rename(...): unexpected error: %v", err)
+	}
+	gate, err := c.GetById(context.Background(), "org", "AU-Tpxb--iU5OvuD2FLy")
+	if err != nil {
+		t.Fatalf("c.GetById(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("stricter", gate.Name); diff != "" {
+		t.Errorf("c.GetById(...): -want name, +got name:\n%s\n", diff)
+	}
+
+	want := []url.Values{
+		{"path": {"/api/qualitygates/rename"}, "organization": {"org"}, "id": {"AU-Tpxb--iU5OvuD2FLy"}, "name": {"stricter"}},
+		{"path": {"/api/qualitygates/show"}, "organization": {"org"}, "id": {"AU-Tpxb--iU5OvuD2FLy"}},
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("c.Rename(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	catalog := `{"metrics":[` +
+		`{"key":"new_coverage","type":"PERCENT","domain":"Coverage"},` +
+		`{"key":"bugs","type":"INT","domain":"Reliability"},` +
+		`{"key":"new_reliability_rating","type":"RATING","domain":"Reliability"},` +
+		`{"key":"ncloc_language_distribution","type":"DATA","domain":"Size"},` +
+		`{"key":"quality_profiles","type":"DATA","domain":"General","hidden":true}` +
+		`],"total":5,"p":1,"ps":500}`
+
+	cases := map[string]struct {
+		reason     string
+		conditions []QualityGateCondition
+		want       string
+	}{
+		"Valid": {
+			reason: "Numeric metrics with LT or GT and rating metrics with GT should be accepted.",
+			conditions: []QualityGateCondition{
+				{Metric: "new_coverage", Op: "LT", Error: "80"},
+				{Metric: "bugs", Op: "GT", Error: "0"},
+				{Metric: "new_reliability_rating", Op: "GT", Error: "1"},
+			},
+		},
+		"RatingLessThan": {
+			reason:     "A rating metric with LT should be rejected, naming the condition.",
+			conditions: []QualityGateCondition{{Metric: "new_coverage", Op: "LT", Error: "80"}, {Metric: "new_reliability_rating", Op: "LT", Error: "2"}},
+			want:       `invalid quality gate condition on metric "new_reliability_rating" with operator "LT": metrics of type RATING only accept the GT operator`,
+		},
+		"UnknownOperator": {
+			reason:     "An operator other than LT or GT should be rejected.",
+			conditions: []QualityGateCondition{{Metric: "bugs", Op: "EQ", Error: "0"}},
+			want:       `invalid quality gate condition on metric "bugs" with operator "EQ": operator must be LT or GT`,
+		},
+		"DataMetric": {
+			reason:     "A metric that is not numeric should be rejected.",
+			conditions: []QualityGateCondition{{Metric: "ncloc_language_distribution", Op: "GT", Error: "0"}},
+			want:       `invalid quality gate condition on metric "ncloc_language_distribution" with operator "GT": metrics of type DATA cannot be used in conditions`,
+		},
+		"HiddenMetric": {
+			reason:     "A hidden metric should be rejected.",
+			conditions: []QualityGateCondition{{Metric: "quality_profiles", Op: "GT", Error: "0"}},
+			want:       `invalid quality gate condition on metric "quality_profiles" with operator "GT": metric is hidden`,
+		},
+		"UnknownMetric": {
+			reason:     "A metric missing from the catalog should be rejected.",
+			conditions: []QualityGateCondition{{Metric: "new_coverge", Op: "LT", Error: "80"}},
+			want:       `invalid quality gate condition on metric "new_coverge" with operator "LT": metric does not exist`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(catalog))
+			}))
+			defer srv.Close()
+
+			c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := c.ValidateConditions(context.Background(), tc.conditions)
+			got := ""
+			if err != nil {
+				got = err.Error()
+				if !errors.Is(err, ErrInvalidCondition) {
+					t.Errorf("\n%s\nc.ValidateConditions(...): want ErrInvalidCondition, got %v", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.ValidateConditions(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateConditionsCachesCatalog(t *testing.T) {
+	searches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		_, _ = w.Write([]byte(`{"metrics":[{"key":"bugs","type":"INT"}],"total":1,"p":1,"ps":500}`))
+	}))
+	defer srv.Close()
+
+	c := NewQualityGateClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	copied := c
+	for _, client := range []QualityGateClient{c, c, copied} {
+		if err := client.ValidateConditions(context.Background(), []QualityGateCondition{{Metric: "bugs", Op: "GT", Error: "0"}}); err != nil {
+			t.Fatalf("c.ValidateConditions(...): unexpected error: %v", err)
+		}
+	}
+	if err := c.ValidateConditions(context.Background(), nil); err != nil {
+		t.Fatalf("c.ValidateConditions(...): unexpected error: %v", err)
+	}
+	if searches != 1 {
+		t.Errorf("c.ValidateConditions(...): want the metrics catalog searched once, got %d", searches)
+	}
+}
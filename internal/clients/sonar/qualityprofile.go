@@ -0,0 +1,185 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+var ErrQualityProfileNotFound = errors.New("Quality profile not found")
+
+type QualityProfile struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Language  string `json:"language"`
+	IsDefault bool   `json:"isDefault"`
+	IsBuiltIn bool   `json:"isBuiltIn"`
+}
+
+type QualityProfileClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Quality Profile Client
+func NewQualityProfileClient(options SonarApiOptions) QualityProfileClient {
+	return QualityProfileClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Create new quality profile
+// https://sonarcloud.io/web_api/api/qualityprofiles/create
+func (qualityProfileClient QualityProfileClient) Create(ctx context.Context, organization string, language string, name string) (QualityProfile, error) {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("language", language)
+	params.Add("name", name)
+
+	var response struct {
+		Profile QualityProfile `json:"profile"`
+	}
+	err := qualityProfileClient.sonarApi.do(ctx, "POST", "/api/qualityprofiles/create", params, &response)
+	return response.Profile, err
+}
+
+// Delete quality profile, identified by its language and name
+// https://sonarcloud.io/web_api/api/qualityprofiles/delete
+func (qualityProfileClient QualityProfileClient) Delete(ctx context.Context, organization string, language string, name string) error {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("language", language)
+	params.Add("qualityProfile", name)
+
+	return qualityProfileClient.sonarApi.do(ctx, "POST", "/api/qualityprofiles/delete", params, nil)
+}
+
+// Rename a quality profile, identified by its key
+// https://sonarcloud.io/web_api/api/qualityprofiles/rename
+func (qualityProfileClient QualityProfileClient) Rename(ctx context.Context, key string, name string) error {
+	params := url.Values{}
+	params.Add("key", key)
+	params.Add("name", name)
+
+	return qualityProfileClient.sonarApi.do(ctx, "POST", "/api/qualityprofiles/rename", params, nil)
+}
+
+// Search the quality profiles of an organization for a language
+// https://sonarcloud.io/web_api/api/qualityprofiles/search
+func (qualityProfileClient QualityProfileClient) Search(ctx context.Context, organization string, language string) ([]QualityProfile, error) {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("language", language)
+
+	var response struct {
+		Profiles []QualityProfile `json:"profiles"`
+	}
+	if err := qualityProfileClient.sonarApi.do(ctx, "GET", "/api/qualityprofiles/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Profiles, nil
+}
+
+// SearchForProject returns the quality profiles used by a project, one per
+// language. Languages without a profile associated with the project use the
+// default profile, which is returned with isDefault set.
+// https://sonarcloud.io/web_api/api/qualityprofiles/search
+func (qualityProfileClient QualityProfileClient) SearchForProject(ctx context.Context, organization string, project string) ([]QualityProfile, error) {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("project", project)
+
+	var response struct {
+		Profiles []QualityProfile `json:"profiles"`
+	}
+	if err := qualityProfileClient.sonarApi.do(ctx, "GET", "/api/qualityprofiles/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Profiles, nil
+}
+
+// Get a single quality profile by language and name
+func (qualityProfileClient QualityProfileClient) GetByName(ctx context.Context, organization string, language string, name string) (QualityProfile, error) {
+	profiles, err := qualityProfileClient.Search(ctx, organization, language)
+	if err != nil {
+		return QualityProfile{}, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Language == language && profile.Name == name {
+			return profile, nil
+		}
+	}
+
+	return QualityProfile{}, ErrQualityProfileNotFound
+}
+
+// Get a single quality profile by language and key
+func (qualityProfileClient QualityProfileClient) GetByKey(ctx context.Context, organization string, language string, key string) (QualityProfile, error) {
+	profiles, err := qualityProfileClient.Search(ctx, organization, language)
+	if err != nil {
+		return QualityProfile{}, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Key == key {
+			return profile, nil
+		}
+	}
+
+	return QualityProfile{}, ErrQualityProfileNotFound
+}
+
+// Projects returns the keys of every project associated with a quality profile
+// https://sonarcloud.io/web_api/api/qualityprofiles/projects
+func (qualityProfileClient QualityProfileClient) Projects(ctx context.Context, key string) ([]string, error) {
+	var projects []string
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("key", key)
+		params.Add("selected", "selected")
+		params.Add("p", strconv.Itoa(page))
+		params.Add("ps", strconv.Itoa(MaxPageSize))
+
+		var response struct {
+			Paging  SonarPaging `json:"paging"`
+			Results []struct {
+				Key string `json:"key"`
+			} `json:"results"`
+		}
+		if err := qualityProfileClient.sonarApi.do(ctx, "GET", "/api/qualityprofiles/projects", params, &response); err != nil {
+			return nil, err
+		}
+
+		for _, r := range response.Results {
+			projects = append(projects, r.Key)
+		}
+		if len(response.Results) == 0 || len(projects) >= response.Paging.Total {
+			return projects, nil
+		}
+	}
+}
+
+// Associate a project with a quality profile
+// https://sonarcloud.io/web_api/api/qualityprofiles/add_project
+func (qualityProfileClient QualityProfileClient) AddProject(ctx context.Context, organization string, language string, name string, project string) error {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("language", language)
+	params.Add("qualityProfile", name)
+	params.Add("project", project)
+
+	return qualityProfileClient.sonarApi.do(ctx, "POST", "/api/qualityprofiles/add_project", params, nil)
+}
+
+// Remove a project's association with a quality profile
+// https://sonarcloud.io/web_api/api/qualityprofiles/remove_project
+func (qualityProfileClient QualityProfileClient) RemoveProject(ctx context.Context, organization string, language string, name string, project string) error {
+	params := url.Values{}
+	qualityProfileClient.sonarApi.addOrganization(params, organization)
+	params.Add("language", language)
+	params.Add("qualityProfile", name)
+	params.Add("project", project)
+
+	return qualityProfileClient.sonarApi.do(ctx, "POST", "/api/qualityprofiles/remove_project", params, nil)
+}
@@ -0,0 +1,84 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQualityProfileGetByName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"profiles":[{"key":"AXP1","name":"strict","language":"js"},{"key":"AXP2","name":"strict","language":"java"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewQualityProfileClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.GetByName(context.Background(), "org", "java", "strict")
+	if err != nil {
+		t.Fatalf("c.GetByName(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(QualityProfile{Key: "AXP2", Name: "strict", Language: "java"}, got); diff != "" {
+		t.Errorf("c.GetByName(...): -want, +got:\n%s\n", diff)
+	}
+
+	if _, err := c.GetByName(context.Background(), "org", "java", "lenient"); err != ErrQualityProfileNotFound {
+		t.Errorf("c.GetByName(...): want ErrQualityProfileNotFound, got %v", err)
+	}
+}
+
+func TestQualityProfileProjects(t *testing.T) {
+	pages := [][]string{{"a", "b"}, {"c"}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _ := strconv.Atoi(r.FormValue("p"))
+		results := []map[string]string{}
+		for _, key := range pages[p-1] {
+			results = append(results, map[string]string{"key": key})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"paging":  SonarPaging{PageIndex: p, PageSize: 2, Total: 3},
+			"results": results,
+		})
+	}))
+	defer srv.Close()
+
+	c := NewQualityProfileClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Projects(context.Background(), "AXP1")
+	if err != nil {
+		t.Fatalf("c.Projects(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, got); diff != "" {
+		t.Errorf("c.Projects(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestQualityProfileSearchForProject(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		query = r.Form.Encode()
+		_, _ = w.Write([]byte(`{"profiles":[{"key":"AXP1","name":"strict","language":"java"},{"key":"AXP2","name":"Sonar way","language":"js","isDefault":true,"isBuiltIn":true}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewQualityProfileClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.SearchForProject(context.Background(), "org", "key")
+	if err != nil {
+		t.Fatalf("c.SearchForProject(...): unexpected error: %v", err)
+	}
+	want := []QualityProfile{
+		{Key: "AXP1", Name: "strict", Language: "java"},
+		{Key: "AXP2", Name: "Sonar way", Language: "js", IsDefault: true, IsBuiltIn: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.SearchForProject(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("organization=org&project=key", query); diff != "" {
+		t.Errorf("c.SearchForProject(...): -want query, +got query:\n%s\n", diff)
+	}
+}
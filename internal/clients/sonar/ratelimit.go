@@ -0,0 +1,49 @@
+package sonar
+
+import (
+	"math"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// requestLimiters paces the requests sent with each credential, so that a
+// large number of resources reconciled at once stays within the rate limits
+// of the instance. Like the authentication circuit breaker, their state is
+// shared by every client, since clients are created for every reconcile.
+type requestLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+var rateLimiters = &requestLimiters{limiters: map[string]*rate.Limiter{}}
+
+// get returns the limiter of key, allowing perSecond requests per second with
+// bursts of burst requests. A limiter that already exists is updated to the
+// supplied rate. It returns nil when perSecond is not positive, which leaves
+// requests unlimited.
+func (l *requestLimiters) get(key string, perSecond float64, burst int) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(perSecond))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+		l.limiters[key] = limiter
+		return limiter
+	}
+	if limiter.Limit() != rate.Limit(perSecond) {
+		limiter.SetLimit(rate.Limit(perSecond))
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+	return limiter
+}
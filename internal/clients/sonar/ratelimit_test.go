@@ -0,0 +1,96 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("10.2.1.78527"))
+	}))
+	defer srv.Close()
+
+	// The key is unique to this test, since limiters are shared by every
+	// client with the same credentials.
+	c := NewSystemClient(SonarApiOptions{Key: t.Name(), BaseUrl: srv.URL, RequestsPerSecond: 20, RequestBurst: 1})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := c.Version(context.Background()); err != nil {
+			t.Fatalf("c.Version(...): unexpected error: %v", err)
+		}
+	}
+
+	// The first request is sent at once, and the next five every 50ms.
+	if elapsed := time.Since(start); elapsed < 225*time.Millisecond {
+		t.Errorf("c.Version(...): want 6 requests at 20 per second to take at least 250ms, took %s", elapsed)
+	}
+}
+
+func TestRateLimitShared(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		other  SonarApiOptions
+		shared bool
+	}{
+		"SameCredentials": {
+			reason: "Clients with the same credentials should share a limiter.",
+			other:  SonarApiOptions{Key: "token", BaseUrl: "https://sonarcloud.io", RequestsPerSecond: 5},
+			shared: true,
+		},
+		"OtherToken": {
+			reason: "Clients with another token should not share a limiter.",
+			other:  SonarApiOptions{Key: "other", BaseUrl: "https://sonarcloud.io", RequestsPerSecond: 5},
+		},
+		"OtherInstance": {
+			reason: "Clients of another instance should not share a limiter.",
+			other:  SonarApiOptions{Key: "token", BaseUrl: "https://sonarqube.example.org", RequestsPerSecond: 5},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: "https://sonarcloud.io", RequestsPerSecond: 5})
+			b := NewSonarApi(tc.other)
+			if got := a.limiter == b.limiter; got != tc.shared {
+				t.Errorf("\n%s\nNewSonarApi(...): want a shared limiter %t, got %t", tc.reason, tc.shared, got)
+			}
+		})
+	}
+
+	if l := NewSonarApi(SonarApiOptions{Key: "token"}).limiter; l != nil {
+		t.Errorf("NewSonarApi(...): want no limiter without RequestsPerSecond, got %v", l)
+	}
+}
+
+func TestRateLimitCanceled(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("10.2.1.78527"))
+	}))
+	defer srv.Close()
+
+	c := NewSystemClient(SonarApiOptions{Key: t.Name(), BaseUrl: srv.URL, RequestsPerSecond: 0.1, RequestBurst: 1})
+	if _, err := c.Version(context.Background()); err != nil {
+		t.Fatalf("c.Version(...): unexpected error: %v", err)
+	}
+
+	// The next turn is 10s away.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := c.Version(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("c.Version(...): want context.Canceled when the context is canceled while waiting, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("c.Version(...): want 1 request sent, got %d", requests)
+	}
+}
@@ -0,0 +1,114 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrSettingNotFound is returned when a setting is not defined.
+var ErrSettingNotFound = errors.New("Setting not found")
+
+// A Setting holds a single value in Value, several values in Values, or the
+// values of the fields of a property set in FieldValues, depending on its
+// type.
+type Setting struct {
+	Key         string              `json:"key"`
+	Value       string              `json:"value,omitempty"`
+	Values      []string            `json:"values,omitempty"`
+	FieldValues []map[string]string `json:"fieldValues,omitempty"`
+	// Inherited is true when the value is not set on the requested
+	// component, e.g. when it is the default value.
+	Inherited bool `json:"inherited"`
+}
+
+type SettingsClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Settings Client
+func NewSettingsClient(options SonarApiOptions) SettingsClient {
+	return SettingsClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Values of settings. Global settings are returned when component is empty,
+// the settings of the component otherwise.
+// https://sonarcloud.io/web_api/api/settings/values
+func (settingsClient SettingsClient) Values(ctx context.Context, component string, keys ...string) ([]Setting, error) {
+	params := url.Values{}
+	params.Add("keys", strings.Join(keys, ","))
+	if component != "" {
+		params.Add("component", component)
+	}
+
+	var response struct {
+		Settings []Setting `json:"settings"`
+	}
+	if err := settingsClient.sonarApi.do(ctx, "GET", "/api/settings/values", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Settings, nil
+}
+
+// Get a setting by its key. ErrSettingNotFound is returned when it is not
+// defined.
+func (settingsClient SettingsClient) Get(ctx context.Context, component string, key string) (Setting, error) {
+	settings, err := settingsClient.Values(ctx, component, key)
+	if err != nil {
+		return Setting{}, err
+	}
+
+	for _, s := range settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, ErrSettingNotFound
+}
+
+// Set a setting. Only one of Value, Values and FieldValues is sent, in this
+// order of precedence.
+// https://sonarcloud.io/web_api/api/settings/set
+func (settingsClient SettingsClient) Set(ctx context.Context, component string, setting Setting) error {
+	params := url.Values{}
+	params.Add("key", setting.Key)
+	if component != "" {
+		params.Add("component", component)
+	}
+
+	switch {
+	case setting.Value != "":
+		params.Add("value", setting.Value)
+	case len(setting.Values) > 0:
+		for _, v := range setting.Values {
+			params.Add("values", v)
+		}
+	default:
+		for _, f := range setting.FieldValues {
+			b, err := json.Marshal(f)
+			if err != nil {
+				return fmt.Errorf("cannot encode field values: %w", err)
+			}
+			params.Add("fieldValues", string(b))
+		}
+	}
+
+	return settingsClient.sonarApi.do(ctx, "POST", "/api/settings/set", params, nil)
+}
+
+// Reset settings to their default value
+// https://sonarcloud.io/web_api/api/settings/reset
+func (settingsClient SettingsClient) Reset(ctx context.Context, component string, keys ...string) error {
+	params := url.Values{}
+	params.Add("keys", strings.Join(keys, ","))
+	if component != "" {
+		params.Add("component", component)
+	}
+
+	return settingsClient.sonarApi.do(ctx, "POST", "/api/settings/reset", params, nil)
+}
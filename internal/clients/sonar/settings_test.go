@@ -0,0 +1,153 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetSetting(t *testing.T) {
+	response := `{
+		"settings": [
+			{"key": "sonar.coverage.jacoco.xmlReportPaths", "value": "target/jacoco.xml"},
+			{"key": "sonar.exclusions", "values": ["**/generated/**", "**/*.pb.go"], "inherited": true},
+			{"key": "sonar.issue.ignore.multicriteria", "fieldValues": [{"ruleKey": "go:S100", "resourceKey": "**/*_test.go"}]}
+		]
+	}`
+
+	type want struct {
+		setting Setting
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		key    string
+		want   want
+	}{
+		"Scalar": {
+			reason: "A single value setting should be returned with its value.",
+			key:    "sonar.coverage.jacoco.xmlReportPaths",
+			want:   want{setting: Setting{Key: "sonar.coverage.jacoco.xmlReportPaths", Value: "target/jacoco.xml"}},
+		},
+		"MultiValue": {
+			reason: "A multi-value setting should be returned with its values in order.",
+			key:    "sonar.exclusions",
+			want:   want{setting: Setting{Key: "sonar.exclusions", Values: []string{"**/generated/**", "**/*.pb.go"}, Inherited: true}},
+		},
+		"PropertySet": {
+			reason: "A property set setting should be returned with its field values.",
+			key:    "sonar.issue.ignore.multicriteria",
+			want:   want{setting: Setting{Key: "sonar.issue.ignore.multicriteria", FieldValues: []map[string]string{{"ruleKey": "go:S100", "resourceKey": "**/*_test.go"}}}},
+		},
+		"NotFound": {
+			reason: "A setting that is not returned should be reported as not found.",
+			key:    "sonar.unknown",
+			want:   want{err: ErrSettingNotFound},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				params = r.Form
+				_, _ = w.Write([]byte(response))
+			}))
+			defer srv.Close()
+
+			c := NewSettingsClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.Get(context.Background(), "key", tc.key)
+			if !errors.Is(err, tc.want.err) {
+				t.Fatalf("\n%s\nc.Get(...): want error %v, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.setting, got); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(url.Values{"keys": {tc.key}, "component": {"key"}}, params); diff != "" {
+				t.Errorf("\n%s\nc.Get(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetSetting(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		component string
+		setting   Setting
+		want      url.Values
+	}{
+		"Scalar": {
+			reason:  "A single value setting should be sent as value, without a component when global.",
+			setting: Setting{Key: "sonar.coverage.jacoco.xmlReportPaths", Value: "target/jacoco.xml"},
+			want:    url.Values{"key": {"sonar.coverage.jacoco.xmlReportPaths"}, "value": {"target/jacoco.xml"}},
+		},
+		"MultiValue": {
+			reason:    "Every value of a multi-value setting should be sent as a values parameter, in order.",
+			component: "key",
+			setting:   Setting{Key: "sonar.exclusions", Values: []string{"**/generated/**", "**/*.pb.go"}},
+			want:      url.Values{"key": {"sonar.exclusions"}, "component": {"key"}, "values": {"**/generated/**", "**/*.pb.go"}},
+		},
+		"PropertySet": {
+			reason:    "Every entry of a property set setting should be sent JSON encoded as a fieldValues parameter.",
+			component: "key",
+			setting:   Setting{Key: "sonar.issue.ignore.multicriteria", FieldValues: []map[string]string{{"ruleKey": "go:S100", "resourceKey": "**/*_test.go"}}},
+			want:      url.Values{"key": {"sonar.issue.ignore.multicriteria"}, "component": {"key"}, "fieldValues": {`{"resourceKey":"**/*_test.go","ruleKey":"go:S100"}`}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var method, path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				method, path = r.Method, r.URL.Path
+				params = r.Form
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			c := NewSettingsClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			if err := c.Set(context.Background(), tc.component, tc.setting); err != nil {
+				t.Fatalf("\n%s\nc.Set(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff("POST /api/settings/set", method+" "+path); diff != "" {
+				t.Errorf("\n%s\nc.Set(...): -want request, +got request:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, params); diff != "" {
+				t.Errorf("\n%s\nc.Set(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResetSetting(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewSettingsClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.Reset(context.Background(), "key", "sonar.exclusions"); err != nil {
+		t.Fatalf("c.Reset(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("/api/settings/reset", path); diff != "" {
+		t.Errorf("c.Reset(...): -want path, +got path:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(url.Values{"keys": {"sonar.exclusions"}, "component": {"key"}}, params); diff != "" {
+		t.Errorf("c.Reset(...): -want params, +got params:\n%s\n", diff)
+	}
+}
@@ -2,19 +2,178 @@ package sonar
 
 import (
 	"context"
+	crand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
-	"log"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/internal/version"
+)
+
+// DefaultTimeout is the request timeout used when SonarApiOptions does not set
+// one.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxAttempts is the number of attempts made for a request when
+// SonarApiOptions does not set one.
+const DefaultMaxAttempts = 4
+
+// DefaultRetryBackoff is the wait before the first retry when SonarApiOptions
+// does not set one. It doubles after every attempt.
+const DefaultRetryBackoff = 500 * time.Millisecond
+
+// DefaultTaskPollInterval is the wait before polling a background task again
+// when SonarApiOptions does not set one. It doubles after every poll.
+const DefaultTaskPollInterval = 2 * time.Second
+
+// maxRetryWait caps the wait between two attempts, including waits requested
+// by a Retry-After header.
+const maxRetryWait = 30 * time.Second
+
+// DefaultUserAgent is sent with every request when SonarApiOptions does not
+// set a UserAgent.
+var DefaultUserAgent = "crossplane-provider-sonar/" + version.Version
+
+// AuthMode selects how the token is sent to the Sonar API.
+type AuthMode string
+
+const (
+	// AuthModeBasic sends the token as the basic auth username. This is
+	// supported by SonarCloud and every SonarQube version.
+	AuthModeBasic AuthMode = "basic"
+	// AuthModeBearer sends the token in an "Authorization: Bearer" header,
+	// as supported by SonarQube 10 and by proxies that strip basic auth.
+	AuthModeBearer AuthMode = "bearer"
+)
+
+// InstanceMode selects the kind of instance the Sonar API is served by.
+type InstanceMode string
+
+const (
+	// InstanceModeSonarCloud is SonarCloud, whose resources belong to
+	// organizations.
+	InstanceModeSonarCloud InstanceMode = "sonarcloud"
+	// InstanceModeSonarQube is a self-hosted SonarQube, which has no
+	// organizations. The organization parameter is never sent to it.
+	InstanceModeSonarQube InstanceMode = "sonarqube"
 )
 
 type SonarApiOptions struct {
 	Key     string
 	BaseUrl string
+	// ServerVersion of the instance, e.g. 10.2. It selects the search
+	// endpoints of SonarQube 10 and later, and the names of their paging
+	// parameters. OptionsFromProviderConfig detects it for SonarQube with
+	// SystemClient.Version. The endpoints supported by SonarCloud and every
+	// SonarQube version are used when it is empty.
+	ServerVersion string
+	// Organization used by calls that are not given one. Only SonarCloud has
+	// organizations, so it is ignored by InstanceModeSonarQube.
+	Organization string
+	// InstanceMode of the instance. Defaults to InstanceModeSonarCloud.
+	InstanceMode InstanceMode
+	// OrgInPath sends the organization of a call in the path of its
+	// endpoint, e.g. /api/orgs/{organization}/projects/search, rather than
+	// as the organization parameter, as some SonarCloud enterprise
+	// deployments expect.
+	OrgInPath bool
+	// Timeout of a single request, including reading the response body.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// AuthMode used to send Key. Defaults to AuthModeBasic.
+	AuthMode AuthMode
+	// TokenType of Key, one of the UserTokenType constants. Requests that
+	// change the instance fail early with ErrTokenScope for analysis tokens,
+	// and so do refused requests. Unknown when empty.
+	TokenType string
+	// MaxAttempts of a request answered with 429, or with 5xx for an
+	// idempotent request, including the first one. Defaults to
+	// DefaultMaxAttempts. Set to 1 to disable retries.
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, doubled after every
+	// attempt. A random part of up to half of every wait is left out. A
+	// Retry-After header takes precedence. Defaults to DefaultRetryBackoff.
+	RetryBackoff time.Duration
+	// ProxyUrl of the proxy requests are sent through. Defaults to the
+	// proxy configured by the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	// environment variables.
+	ProxyUrl string
+	// CABundle holds PEM encoded certificates trusted in addition to the
+	// system roots, for SonarQube servers using a private CA.
+	CABundle []byte
+	// InsecureSkipVerify disables the verification of the server
+	// certificate. It should only be used for development.
+	InsecureSkipVerify bool
+	// AuthFailureThreshold is the number of consecutive 401 responses, and
+	// 403 responses when AuthFailureForbidden is set, after which requests
+	// with the same credentials are short-circuited. Defaults to
+	// DefaultAuthFailureThreshold.
+	AuthFailureThreshold int
+	// AuthFailureForbidden counts 403 responses as authentication failures,
+	// e.g. for credentials whose permissions were all revoked. It is off by
+	// default, since a 403 is also returned for a single resource the
+	// credentials lack a permission on.
+	AuthFailureForbidden bool
+	// AuthFailureCooldown is how long requests are short-circuited once
+	// AuthFailureThreshold is reached. Defaults to DefaultAuthFailureCooldown.
+	AuthFailureCooldown time.Duration
+	// DryRun logs the requests that would change the instance instead of
+	// sending them, and reports them as successful. Requests that only read
+	// are still sent.
+	DryRun bool
+	// Logger every request is logged to at debug level, with its method,
+	// path, status and latency but never its query or credentials. The
+	// requests skipped by DryRun are logged to it too. Defaults to a logger
+	// that discards everything.
+	Logger logging.Logger
+	// UserAgent sent with every request, so that the traffic of the provider
+	// can be told apart in the logs of the instance. Defaults to
+	// DefaultUserAgent.
+	UserAgent string
+	// RequestsPerSecond limits the rate of the requests sent with Key, which
+	// is shared by every client using the same credentials. Requests wait
+	// for their turn until their context is done. Requests are not limited
+	// when it is not positive, the default.
+	RequestsPerSecond float64
+	// RequestBurst is the number of requests that can be sent at once before
+	// RequestsPerSecond applies. Defaults to RequestsPerSecond, rounded up.
+	RequestBurst int
+	// TaskPollInterval is the wait before polling a background task again
+	// while waiting for it to finish, doubled after every poll. Defaults to
+	// DefaultTaskPollInterval.
+	TaskPollInterval time.Duration
 }
 
 type SonarApi struct {
 	Options SonarApiOptions
+	client  *http.Client
+	// err is set when the options cannot be applied, and is returned by
+	// every request.
+	err error
+	// breakerKey identifies the credentials in the authentication circuit
+	// breaker.
+	breakerKey string
+	// limiter paces the requests sent with the credentials. It is nil when
+	// requests are not limited.
+	limiter *rate.Limiter
 }
 
 type SonarPaging struct {
@@ -32,24 +191,480 @@ func NewSonarApi(options SonarApiOptions) SonarApi {
 	if options.BaseUrl == "" {
 		options.BaseUrl = opt.BaseUrl
 	}
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.AuthMode == "" {
+		options.AuthMode = AuthModeBasic
+	}
+	if options.InstanceMode == "" {
+		options.InstanceMode = InstanceModeSonarCloud
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultRetryBackoff
+	}
+	if options.AuthFailureThreshold <= 0 {
+		options.AuthFailureThreshold = DefaultAuthFailureThreshold
+	}
+	if options.AuthFailureCooldown <= 0 {
+		options.AuthFailureCooldown = DefaultAuthFailureCooldown
+	}
+	if options.Logger == nil {
+		options.Logger = logging.NewNopLogger()
+	}
+	if options.UserAgent == "" {
+		options.UserAgent = DefaultUserAgent
+	}
+	if options.TaskPollInterval <= 0 {
+		options.TaskPollInterval = DefaultTaskPollInterval
+	}
+
+	client, err := httpClients.get(options)
+	key := breakerKey(options)
 
 	return SonarApi{
-		Options: options,
+		Options:    options,
+		client:     client,
+		err:        err,
+		breakerKey: key,
+		limiter:    rateLimiters.get(key, options.RequestsPerSecond, options.RequestBurst),
 	}
 }
 
-func (sonarApi SonarApi) GetUrl(uri string) *url.URL {
+// newTransport returns a transport configured with the proxy and TLS settings
+// of options. The default transport is returned alongside any error.
+func newTransport(options SonarApiOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = MaxIdleConnsPerHost
+	transport.IdleConnTimeout = IdleConnTimeout
+
+	if options.ProxyUrl != "" {
+		proxy, err := url.Parse(options.ProxyUrl)
+		if err != nil {
+			return transport, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if len(options.CABundle) > 0 || options.InsecureSkipVerify {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: options.InsecureSkipVerify, // #nosec G402 -- opt-in for development
+		}
+		if len(options.CABundle) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(options.CABundle) {
+				return transport, errInvalidCABundle
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
+
+// errInvalidCABundle is returned for a CA bundle holding no certificate.
+var errInvalidCABundle = errors.New("invalid CA bundle: no PEM encoded certificate found")
+
+// ValidateCABundle checks that caBundle holds at least one PEM encoded
+// certificate.
+func ValidateCABundle(caBundle []byte) error {
+	if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+		return errInvalidCABundle
+	}
+	return nil
+}
+
+// LoadCABundle reads the CA bundle held by the referenced secret key and
+// checks that it holds PEM encoded certificates. It returns nil when ref is
+// nil.
+func LoadCABundle(ctx context.Context, kube client.Client, ref *xpv1.SecretKeySelector) ([]byte, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	caBundle, err := resource.ExtractSecret(ctx, kube, xpv1.CommonCredentialSelectors{SecretRef: ref})
+	if err != nil {
+		return nil, err
+	}
+	if len(caBundle) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	if err := ValidateCABundle(caBundle); err != nil {
+		return nil, err
+	}
+	return caBundle, nil
+}
+
+// ErrEmptyCredentials is returned by CredentialsToken for credentials that
+// hold no token.
+var ErrEmptyCredentials = errors.New("credentials are empty: set them to a Sonar token")
+
+// CredentialsToken returns the token held by the credentials of a
+// ProviderConfig, whichever their source. Surrounding whitespace, such as the
+// trailing newline of a token mounted from a file or the spaces of a token
+// copied from the UI, is trimmed, since it would be sent as part of the token
+// and fail authentication. ErrEmptyCredentials is returned when no token is
+// left.
+func CredentialsToken(credentials []byte) (string, error) {
+	token := strings.TrimSpace(string(credentials))
+	if token == "" {
+		return "", ErrEmptyCredentials
+	}
+	return token, nil
+}
+
+// ValidateBaseUrl checks that baseUrl is an absolute http or https URL. An
+// empty baseUrl is valid and selects the SonarCloud default.
+func ValidateBaseUrl(baseUrl string) error {
+	if baseUrl == "" {
+		return nil
+	}
+
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return err
+	}
+
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("base url %q must be absolute", baseUrl)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("base url %q must use the http or https scheme", baseUrl)
+	}
+
+	return nil
+}
+
+// addOrganization adds the organization parameter, falling back to
+// Options.Organization when organization is empty. The parameter is omitted
+// when both are empty, and always for InstanceModeSonarQube: SonarQube has no
+// organizations and rejects it, so it is only sent to SonarCloud.
+func (sonarApi SonarApi) addOrganization(params url.Values, organization string) {
+	if sonarApi.Options.InstanceMode == InstanceModeSonarQube {
+		return
+	}
+	if organization == "" {
+		organization = sonarApi.Options.Organization
+	}
+	if organization != "" {
+		params.Add("organization", organization)
+	}
+}
+
+// PagingParamsVersion is the first major version of SonarQube whose project
+// searches name their paging parameters page and pageSize rather than p and
+// ps.
+const PagingParamsVersion = 10
+
+// addPaging adds the page number and page size parameters, each only when
+// positive, under the names used by Options.ServerVersion. The names used by
+// SonarCloud and older SonarQube versions apply when the version is unknown.
+func (sonarApi SonarApi) addPaging(params url.Values, page int, pageSize int) {
+	pageName, pageSizeName := "p", "ps"
+	if major, err := MajorVersion(sonarApi.Options.ServerVersion); err == nil && major >= PagingParamsVersion {
+		pageName, pageSizeName = "page", "pageSize"
+	}
+
+	if page > 0 {
+		params.Add(pageName, strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		params.Add(pageSizeName, strconv.Itoa(pageSize))
+	}
+}
+
+// orgPath is the path an API endpoint is moved under when Options.OrgInPath
+// is set.
+const orgPath = "/api/orgs/{organization}/"
+
+// GetUrl returns the URL of uri on the instance. The placeholders of uri, such
+// as {organization}, are replaced with the escaped values of vars, which holds
+// pairs of placeholder names and values.
+func (sonarApi SonarApi) GetUrl(uri string, vars ...string) (*url.URL, error) {
 	u, err := url.Parse(sonarApi.Options.BaseUrl)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	if len(vars)%2 != 0 {
+		return nil, fmt.Errorf("cannot expand %s: placeholder %q has no value", uri, vars[len(vars)-1])
+	}
+	for i := 0; i < len(vars); i += 2 {
+		uri = strings.ReplaceAll(uri, "{"+vars[i]+"}", url.PathEscape(vars[i+1]))
+	}
+	if strings.ContainsAny(uri, "{}") {
+		return nil, fmt.Errorf("cannot expand %s: placeholder without a value", uri)
+	}
+
+	return u.JoinPath(uri), nil
+}
+
+// endpointUrl returns the URL of an API endpoint called with params. The
+// organization parameter is moved to the path when Options.OrgInPath is set,
+// in which case a copy of params without it is returned.
+func (sonarApi SonarApi) endpointUrl(path string, params url.Values) (*url.URL, url.Values, error) {
+	org := params.Get("organization")
+	if !sonarApi.Options.OrgInPath || org == "" || !strings.HasPrefix(path, "/api/") {
+		u, err := sonarApi.GetUrl(path)
+		return u, params, err
 	}
 
-	return u.JoinPath(uri)
+	rest := make(url.Values, len(params))
+	for name, values := range params {
+		if name != "organization" {
+			rest[name] = values
+		}
+	}
+	u, err := sonarApi.GetUrl(orgPath+strings.TrimPrefix(path, "/api/"), "organization", org)
+	return u, rest, err
 }
 
 func (sonarApi SonarApi) NewRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
-	req.SetBasicAuth(sonarApi.Options.Key, "")
+	if sonarApi.err != nil {
+		return nil, sonarApi.err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if sonarApi.Options.AuthMode == AuthModeBearer {
+		req.Header.Set("Authorization", "Bearer "+sonarApi.Options.Key)
+	} else {
+		req.SetBasicAuth(sonarApi.Options.Key, "")
+	}
+	req.Header.Set("User-Agent", sonarApi.Options.UserAgent)
+
+	return req, nil
+}
+
+// do sends a request with the supplied method to the supplied API path, with
+// params encoded in the query string of a GET, and otherwise in a form body,
+// which unlike the URL is not recorded in access logs. A non-2xx response is returned as a
+// *SonarAPIError. The response body is decoded into out when it is not nil,
+// or copied as plain text when out is a *string.
+func (sonarApi SonarApi) do(ctx context.Context, method string, path string, params url.Values, out any) error {
+	u, params, err := sonarApi.endpointUrl(path, params)
+	if err != nil {
+		return err
+	}
+	if method == http.MethodGet {
+		u.RawQuery = params.Encode()
+	}
+
+	if sonarApi.Options.DryRun && method != http.MethodGet {
+		// Only the names of the parameters are logged, since values can be
+		// secrets such as passwords.
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		sonarApi.Options.Logger.Info("Dry run, request not sent", "method", method, "path", path, "params", names)
+		return nil
+	}
+
+	// Analysis tokens can only read, and only some of what a user can.
+	if isAnalysisToken(sonarApi.Options.TokenType) && method != http.MethodGet {
+		return &TokenScopeError{TokenType: sonarApi.Options.TokenType, Method: method, Path: path}
+	}
+
+	if err := authBreakers.allow(sonarApi.breakerKey); err != nil {
+		return err
+	}
+
+	requests.start()
+	defer requests.done()
+
+	var body io.Reader
+	if method != http.MethodGet {
+		body = strings.NewReader(params.Encode())
+	}
+	req, err := sonarApi.NewRequest(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	resp, err := sonarApi.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	err = checkResponse(resp)
+	authBreakers.record(sonarApi.breakerKey, resp.StatusCode, err, sonarApi.Options.AuthFailureThreshold, sonarApi.Options.AuthFailureCooldown, sonarApi.Options.AuthFailureForbidden)
+	if resp.StatusCode == http.StatusForbidden && isAnalysisToken(sonarApi.Options.TokenType) {
+		return &TokenScopeError{TokenType: sonarApi.Options.TokenType, Method: method, Path: path, Err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
 
-	return req, err
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if s, ok := out.(*string); ok {
+		*s = strings.TrimSpace(string(responseData))
+		return nil
+	}
+
+	if err := json.Unmarshal(responseData, out); err != nil {
+		return newDecodeError(resp.Header.Get("Content-Type"), responseData, err)
+	}
+
+	return nil
+}
+
+// Do sends req, retrying with exponential backoff while the response is 429,
+// or 5xx when the method of req is idempotent, up to Options.MaxAttempts. A
+// POST answered with 5xx may have been applied, so it is not sent again.
+// Retries stop when the context of req is done. Every attempt waits for its
+// turn when Options.RequestsPerSecond is set. Every attempt is recorded in the
+// provider_sonar_* metrics and logged with a request ID shared by the
+// attempts.
+func (sonarApi SonarApi) Do(req *http.Request) (*http.Response, error) {
+	backoff := sonarApi.Options.RetryBackoff
+	requestId := newRequestId()
+
+	for attempt := 1; ; attempt++ {
+		if sonarApi.limiter != nil {
+			if err := sonarApi.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
+		resp, err := sonarApi.client.Do(req)
+		latency := time.Since(start)
+		if err != nil {
+			recordRequest(req.URL.Path, 0, latency)
+			sonarApi.logRequest(req, requestId, attempt, latency, "error", redactUrl(err))
+			return nil, err
+		}
+		recordRequest(req.URL.Path, resp.StatusCode, latency)
+		sonarApi.logRequest(req, requestId, attempt, latency, "status", resp.StatusCode)
+		if !retryable(req.Method, resp.StatusCode) || attempt >= sonarApi.Options.MaxAttempts {
+			return resp, nil
+		}
+
+		wait := retryAfter(resp, jitter(backoff))
+		backoff *= 2
+
+		// The response is discarded, so drain it to allow the connection to
+		// be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+}
+
+// logRequest logs an attempt of req at debug level. Only the path of req is
+// logged, since its query can hold secrets such as passwords.
+func (sonarApi SonarApi) logRequest(req *http.Request, requestId string, attempt int, latency time.Duration, keysAndValues ...any) {
+	if sonarApi.Options.Logger == nil {
+		return
+	}
+	sonarApi.Options.Logger.Debug("Sonar API request", append([]any{
+		"requestId", requestId,
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"latency", latency.String(),
+	}, keysAndValues...)...)
+}
+
+// newRequestId returns a random ID correlating the log lines of a request.
+func newRequestId() string {
+	b := make([]byte, 8)
+	_, _ = crand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// redactUrl returns the cause of a transport error without the URL it
+// carries, whose query can hold secrets.
+func redactUrl(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// jitter returns a random wait between half of backoff and backoff, so that
+// clients throttled together do not retry together.
+func jitter(backoff time.Duration) time.Duration {
+	half := int64(backoff / 2)
+	return time.Duration(half + rand.Int63n(half+1)) // #nosec G404 -- not used for security
+}
+
+// retryable returns true if a request with the supplied method answered with
+// the supplied status code may succeed when retried. A request answered with
+// 429 was not processed, so it is always retried. Only idempotent requests are
+// retried after a 5xx.
+func retryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return statusCode >= 500 && idempotent(method)
+}
+
+// idempotent returns true if sending a request with the supplied method
+// several times has the same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait requested by the Retry-After header of resp, or
+// backoff when the header is absent or invalid. The wait is capped at
+// maxRetryWait.
+func retryAfter(resp *http.Response, backoff time.Duration) time.Duration {
+	wait := backoff
+	if h := resp.Header.Get("Retry-After"); h != "" {
+		if seconds, err := strconv.Atoi(h); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		} else if t, err := http.ParseTime(h); err == nil {
+			wait = time.Until(t)
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > maxRetryWait {
+		wait = maxRetryWait
+	}
+	return wait
 }
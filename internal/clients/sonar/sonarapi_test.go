@@ -0,0 +1,1092 @@
+package sonar
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestNewRequest(t *testing.T) {
+	type args struct {
+		method string
+		body   io.Reader
+	}
+
+	type want struct {
+		method string
+		body   string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetWithoutBody": {
+			reason: "A GET request should keep its method and carry no body.",
+			args: args{
+				method: "GET",
+			},
+			want: want{
+				method: "GET",
+			},
+		},
+		"PostWithBody": {
+			reason: "A POST request should keep its method and pass the body through.",
+			args: args{
+				method: "POST",
+				body:   strings.NewReader("name=test"),
+			},
+			want: want{
+				method: "POST",
+				body:   "name=test",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewSonarApi(SonarApiOptions{Key: "token"})
+			req, err := api.NewRequest(context.Background(), tc.args.method, "https://sonarcloud.io/api/projects/search", tc.args.body)
+			if err != nil {
+				t.Fatalf("\n%s\napi.NewRequest(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.method, req.Method); diff != "" {
+				t.Errorf("\n%s\napi.NewRequest(...): -want method, +got method:\n%s\n", tc.reason, diff)
+			}
+
+			var body string
+			if req.Body != nil {
+				b, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("\n%s\nio.ReadAll(...): unexpected error: %v", tc.reason, err)
+				}
+				body = string(b)
+			}
+			if diff := cmp.Diff(tc.want.body, body); diff != "" {
+				t.Errorf("\n%s\napi.NewRequest(...): -want body, +got body:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateBaseUrl(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		baseUrl string
+		wantErr bool
+	}{
+		"Empty": {
+			reason:  "An empty base url should be valid and select the default.",
+			baseUrl: "",
+		},
+		"Https": {
+			reason:  "An absolute https url should be valid.",
+			baseUrl: "https://sonarqube.example.org",
+		},
+		"HttpWithPath": {
+			reason:  "An absolute http url with a context path should be valid.",
+			baseUrl: "http://sonarqube.example.org:9000/sonar",
+		},
+		"Relative": {
+			reason:  "A relative url should be rejected.",
+			baseUrl: "sonarqube.example.org",
+			wantErr: true,
+		},
+		"UnsupportedScheme": {
+			reason:  "A url with a scheme other than http or https should be rejected.",
+			baseUrl: "ftp://sonarqube.example.org",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateBaseUrl(tc.baseUrl)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("\n%s\nValidateBaseUrl(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCredentialsToken(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		credentials string
+		want        string
+		wantErr     error
+	}{
+		"Token": {
+			reason:      "A token should be returned as is.",
+			credentials: "squ_0123456789abcdef",
+			want:        "squ_0123456789abcdef",
+		},
+		"TrailingNewline": {
+			reason:      "The trailing newline of a token mounted from a file should be trimmed.",
+			credentials: "squ_0123456789abcdef\n",
+			want:        "squ_0123456789abcdef",
+		},
+		"CarriageReturn": {
+			reason:      "A trailing carriage return and newline should be trimmed.",
+			credentials: "squ_0123456789abcdef\r\n",
+			want:        "squ_0123456789abcdef",
+		},
+		"Padded": {
+			reason:      "The spaces around a token copied from the UI should be trimmed.",
+			credentials: "  squ_0123456789abcdef\t ",
+			want:        "squ_0123456789abcdef",
+		},
+		"Empty": {
+			reason:      "Empty credentials should be rejected.",
+			credentials: "",
+			wantErr:     ErrEmptyCredentials,
+		},
+		"Whitespace": {
+			reason:      "Credentials of only whitespace should be rejected.",
+			credentials: " \n",
+			wantErr:     ErrEmptyCredentials,
+		},
+		"UserAndPassword": {
+			reason:      "A user and password should keep the spaces within them.",
+			credentials: "admin:pass word\n",
+			want:        "admin:pass word",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CredentialsToken([]byte(tc.credentials))
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("\n%s\nCredentialsToken(...): want error %v, got %v", tc.reason, tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCredentialsToken(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetUrl(t *testing.T) {
+	type want struct {
+		url string
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		options SonarApiOptions
+		uri     string
+		vars    []string
+		want    want
+	}{
+		"DefaultBaseUrl": {
+			reason: "SonarCloud should be used when no base url is configured.",
+			uri:    "/api/projects/search",
+			want:   want{url: "https://sonarcloud.io/api/projects/search"},
+		},
+		"CustomBaseUrl": {
+			reason:  "A configured base url, including its context path, should be honored.",
+			options: SonarApiOptions{BaseUrl: "https://sonarqube.example.org/sonar"},
+			uri:     "/api/projects/search",
+			want:    want{url: "https://sonarqube.example.org/sonar/api/projects/search"},
+		},
+		"Template": {
+			reason: "The placeholders of the path should be replaced with their values.",
+			uri:    "/api/orgs/{organization}/projects/search",
+			vars:   []string{"organization", "gbsandbox"},
+			want:   want{url: "https://sonarcloud.io/api/orgs/gbsandbox/projects/search"},
+		},
+		"TemplateEscaped": {
+			reason: "The values of placeholders should be escaped, so that they cannot change the path.",
+			uri:    "/api/orgs/{organization}/projects/search",
+			vars:   []string{"organization", "gb/../sandbox"},
+			want:   want{url: "https://sonarcloud.io/api/orgs/gb%2F..%2Fsandbox/projects/search"},
+		},
+		"TemplateMissingValue": {
+			reason: "A placeholder without a value should be an error rather than sent as is.",
+			uri:    "/api/orgs/{organization}/projects/search",
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := NewSonarApi(tc.options).GetUrl(tc.uri, tc.vars...)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("\n%s\napi.GetUrl(...): -want error, +got error:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.url, u.String()); diff != "" {
+				t.Errorf("\n%s\napi.GetUrl(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOrgInPath(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		orgInPath    bool
+		organization string
+		want         string
+	}{
+		"QueryParameter": {
+			reason:       "The organization should be sent as a parameter by default.",
+			organization: "gbsandbox",
+			want:         "/api/projects/search organization=gbsandbox&projects=key",
+		},
+		"Path": {
+			reason:       "The organization should be sent in the path when OrgInPath is set.",
+			orgInPath:    true,
+			organization: "gbsandbox",
+			want:         "/api/orgs/gbsandbox/projects/search projects=key",
+		},
+		"PathWithoutOrganization": {
+			reason:    "A call without an organization should keep its path when OrgInPath is set.",
+			orgInPath: true,
+			want:      "/api/projects/search projects=key",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = r.URL.EscapedPath() + " " + r.Form.Encode()
+				_, _ = w.Write([]byte(`{"components":[]}`))
+			}))
+			defer srv.Close()
+
+			c := NewProjectClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL, OrgInPath: tc.orgInPath})
+			if _, err := c.Search(context.Background(), tc.organization, SearchOptions{Projects: []string{"key"}}); err != nil {
+				t.Fatalf("\n%s\nc.Search(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nc.Search(...): -want request, +got request:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		options SonarApiOptions
+		timeout time.Duration
+	}{
+		"ContextDeadline": {
+			reason:  "A request should return promptly once the context deadline is exceeded.",
+			timeout: 50 * time.Millisecond,
+		},
+		"ClientTimeout": {
+			reason:  "A request should return promptly once the configured client timeout elapses.",
+			options: SonarApiOptions{Timeout: 50 * time.Millisecond},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			release := make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer srv.Close()
+			defer close(release)
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			tc.options.BaseUrl = srv.URL
+			c := NewProjectClient(tc.options)
+
+			start := time.Now()
+			_, err := c.Search(ctx, "org", SearchOptions{})
+			if err == nil {
+				t.Fatalf("\n%s\nc.Search(...): want error, got nil", tc.reason)
+			}
+			if tc.timeout > 0 && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("\n%s\nc.Search(...): want context.DeadlineExceeded, got %v", tc.reason, err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("\n%s\nc.Search(...): returned after %s, want prompt return", tc.reason, elapsed)
+			}
+		})
+	}
+}
+
+func TestNewSonarApiSharesClient(t *testing.T) {
+	api := NewSonarApi(SonarApiOptions{})
+	if api.client == nil {
+		t.Fatal("NewSonarApi(...): want a shared http client, got nil")
+	}
+	if diff := cmp.Diff(DefaultTimeout, api.client.Timeout); diff != "" {
+		t.Errorf("NewSonarApi(...): -want timeout, +got timeout:\n%s\n", diff)
+	}
+	if c := NewProjectClient(SonarApiOptions{}); c.sonarApi.client == nil {
+		t.Error("NewProjectClient(...): want a shared http client, got nil")
+	}
+}
+
+func TestNewRequestAuthMode(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		authMode AuthMode
+		want     string
+	}{
+		"Default": {
+			reason: "The token should be sent as the basic auth username by default.",
+			want:   "Basic dG9rZW46",
+		},
+		"Basic": {
+			reason:   "The token should be sent as the basic auth username in basic mode.",
+			authMode: AuthModeBasic,
+			want:     "Basic dG9rZW46",
+		},
+		"Bearer": {
+			reason:   "The token should be sent as a bearer token in bearer mode.",
+			authMode: AuthModeBearer,
+			want:     "Bearer token",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewSonarApi(SonarApiOptions{Key: "token", AuthMode: tc.authMode})
+			req, err := api.NewRequest(context.Background(), "GET", "https://sonarcloud.io/api/projects/search", nil)
+			if err != nil {
+				t.Fatalf("\n%s\napi.NewRequest(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, req.Header.Get("Authorization")); diff != "" {
+				t.Errorf("\n%s\napi.NewRequest(...): -want Authorization, +got Authorization:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		userAgent string
+		want      string
+	}{
+		"Default": {
+			reason: "Requests should identify the provider by default.",
+			want:   DefaultUserAgent,
+		},
+		"Override": {
+			reason:    "Requests should carry the configured User-Agent.",
+			userAgent: "platform-team/1.0",
+			want:      "platform-team/1.0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			api := NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: srv.URL, UserAgent: tc.userAgent})
+			if err := api.do(context.Background(), "GET", "/api/projects/search", nil, nil); err != nil {
+				t.Fatalf("\n%s\napi.do(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\napi.do(...): -want User-Agent, +got User-Agent:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	if !strings.HasPrefix(DefaultUserAgent, "crossplane-provider-sonar/") {
+		t.Errorf("DefaultUserAgent: want a crossplane-provider-sonar/ prefix, got %q", DefaultUserAgent)
+	}
+}
+
+func TestDoRetries(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		method      string
+		statuses    []int
+		maxAttempts int
+		want        int
+		wantCalls   int
+	}{
+		"RateLimitedThenOK": {
+			reason:    "A request answered with 429 twice should be retried until it succeeds.",
+			statuses:  []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			want:      http.StatusOK,
+			wantCalls: 3,
+		},
+		"UnavailableThenOK": {
+			reason:    "A request answered with 503 should be retried.",
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:      http.StatusOK,
+			wantCalls: 2,
+		},
+		"PostRateLimitedThenOK": {
+			reason:    "A POST answered with 429 was not processed, and should be retried.",
+			method:    "POST",
+			statuses:  []int{http.StatusTooManyRequests, http.StatusOK},
+			want:      http.StatusOK,
+			wantCalls: 2,
+		},
+		"PostUnavailable": {
+			reason:    "A POST answered with 503 may have been applied, and should not be retried.",
+			method:    "POST",
+			statuses:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:      http.StatusServiceUnavailable,
+			wantCalls: 1,
+		},
+		"NotRetryable": {
+			reason:    "A request answered with 400 should not be retried.",
+			statuses:  []int{http.StatusBadRequest, http.StatusOK},
+			want:      http.StatusBadRequest,
+			wantCalls: 1,
+		},
+		"MaxAttempts": {
+			reason:      "The last response should be returned once the maximum number of attempts is reached.",
+			statuses:    []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			maxAttempts: 2,
+			want:        http.StatusTooManyRequests,
+			wantCalls:   2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statuses[calls])
+				calls++
+			}))
+			defer srv.Close()
+
+			method := "GET"
+			if tc.method != "" {
+				method = tc.method
+			}
+			api := NewSonarApi(SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: tc.maxAttempts, RetryBackoff: time.Millisecond})
+			req, err := api.NewRequest(context.Background(), method, srv.URL, nil)
+			if err != nil {
+				t.Fatalf("\n%s\napi.NewRequest(...): unexpected error: %v", tc.reason, err)
+			}
+			resp, err := api.Do(req)
+			if err != nil {
+				t.Fatalf("\n%s\napi.Do(...): unexpected error: %v", tc.reason, err)
+			}
+			_ = resp.Body.Close()
+
+			if diff := cmp.Diff(tc.want, resp.StatusCode); diff != "" {
+				t.Errorf("\n%s\napi.Do(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.wantCalls, calls); diff != "" {
+				t.Errorf("\n%s\napi.Do(...): -want calls, +got calls:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDoRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Cancel while the client waits for the requested minute.
+		cancel()
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	api := NewSonarApi(SonarApiOptions{BaseUrl: srv.URL})
+	req, err := api.NewRequest(ctx, "GET", srv.URL, nil)
+	if err != nil {
+		t.Fatalf("api.NewRequest(...): unexpected error: %v", err)
+	}
+	if _, err := api.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("api.Do(...): want context.Canceled, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		header string
+		want   time.Duration
+	}{
+		"Absent": {
+			reason: "The backoff should be used when no Retry-After header is sent.",
+			want:   time.Second,
+		},
+		"Seconds": {
+			reason: "A Retry-After header in seconds should take precedence over the backoff.",
+			header: "5",
+			want:   5 * time.Second,
+		},
+		"Capped": {
+			reason: "A Retry-After header should be capped at the maximum wait.",
+			header: "3600",
+			want:   maxRetryWait,
+		},
+		"Invalid": {
+			reason: "The backoff should be used when the Retry-After header is invalid.",
+			header: "soon",
+			want:   time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.header != "" {
+				resp.Header.Set("Retry-After", tc.header)
+			}
+			if diff := cmp.Diff(tc.want, retryAfter(resp, time.Second)); diff != "" {
+				t.Errorf("\n%s\nretryAfter(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	type result struct {
+		Name string `json:"name"`
+	}
+
+	type want struct {
+		method string
+		path   string
+		query  string
+		form   string
+		out    result
+		err    string
+	}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		status int
+		body   string
+		out    any
+		want   want
+	}{
+		"Success": {
+			reason: "The response body of a 2xx response should be decoded into out.",
+			status: http.StatusOK,
+			body:   `{"name":"project"}`,
+			out:    &result{},
+			want: want{
+				method: "POST",
+				path:   "/api/projects/create",
+				form:   "name=project&organization=org",
+				out:    result{Name: "project"},
+			},
+		},
+		"Get": {
+			reason: "The params of a GET should be sent in its query string.",
+			method: "GET",
+			status: http.StatusOK,
+			body:   `{"name":"project"}`,
+			out:    &result{},
+			want: want{
+				method: "GET",
+				path:   "/api/projects/create",
+				query:  "name=project&organization=org",
+				out:    result{Name: "project"},
+			},
+		},
+		"NoOutput": {
+			reason: "The response body should be ignored when out is nil.",
+			status: http.StatusNoContent,
+			want: want{
+				method: "POST",
+				path:   "/api/projects/create",
+				form:   "name=project&organization=org",
+			},
+		},
+		"NotSuccess": {
+			reason: "A non-2xx response should be returned as a *SonarAPIError.",
+			status: http.StatusBadRequest,
+			body:   `{"errors":[{"msg":"Name is too long"}]}`,
+			out:    &result{},
+			want: want{
+				method: "POST",
+				path:   "/api/projects/create",
+				form:   "name=project&organization=org",
+				err:    "error calling sonar api: 400 Bad Request: Name is too long",
+			},
+		},
+		"DecodeError": {
+			reason: "A response body that cannot be decoded should be returned as an error.",
+			status: http.StatusOK,
+			body:   `<html></html>`,
+			out:    &result{},
+			want: want{
+				method: "POST",
+				path:   "/api/projects/create",
+				form:   "name=project&organization=org",
+				err:    `cannot decode sonar api response of content type "text/html; charset=utf-8": invalid character '<' looking for beginning of value: body starts with "<html></html>"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got.method = r.Method
+				got.path = r.URL.Path
+				// The params of a POST are sent in a form body, and not in
+				// the query string recorded in access logs.
+				got.query = r.URL.RawQuery
+				if r.Method == http.MethodPost {
+					if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+						t.Errorf("\n%s\napi.do(...): want a form body, got content type %q", tc.reason, ct)
+					}
+					b, _ := io.ReadAll(r.Body)
+					got.form = string(b)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			api := NewSonarApi(SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1})
+			params := url.Values{"organization": {"org"}, "name": {"project"}}
+			method := "POST"
+			if tc.method != "" {
+				method = tc.method
+			}
+			err := api.do(context.Background(), method, "/api/projects/create", params, tc.out)
+			if err != nil {
+				got.err = err.Error()
+			}
+			if r, ok := tc.out.(*result); ok {
+				got.out = *r
+			}
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\napi.do(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewSonarApiProxy(t *testing.T) {
+	api := NewSonarApi(SonarApiOptions{ProxyUrl: "http://proxy.example.org:3128"})
+	req, err := api.NewRequest(context.Background(), "GET", "https://sonarcloud.io/api/projects/search", nil)
+	if err != nil {
+		t.Fatalf("api.NewRequest(...): unexpected error: %v", err)
+	}
+
+	got, err := api.client.Transport.(*http.Transport).Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("http://proxy.example.org:3128", got.String()); diff != "" {
+		t.Errorf("Proxy(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestNewSonarApiProxyFromEnvironment(t *testing.T) {
+	api := NewSonarApi(SonarApiOptions{})
+
+	got := reflect.ValueOf(api.client.Transport.(*http.Transport).Proxy).Pointer()
+	want := reflect.ValueOf(http.ProxyFromEnvironment).Pointer()
+	if got != want {
+		t.Error("NewSonarApi(...): want the proxy to be read from the environment when no proxy url is set")
+	}
+}
+
+func TestNewSonarApiTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	cases := map[string]struct {
+		reason  string
+		options SonarApiOptions
+		wantErr bool
+	}{
+		"Untrusted": {
+			reason:  "A server certificate signed by an unknown CA should be rejected.",
+			options: SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1},
+			wantErr: true,
+		},
+		"CABundle": {
+			reason:  "A server certificate signed by a CA from the bundle should be trusted.",
+			options: SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1, CABundle: caBundle},
+		},
+		"InsecureSkipVerify": {
+			reason:  "The server certificate should not be verified when verification is disabled.",
+			options: SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1, InsecureSkipVerify: true},
+		},
+		"InvalidCABundle": {
+			reason:  "A CA bundle without any PEM encoded certificate should be rejected.",
+			options: SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1, CABundle: []byte("not a certificate")},
+			wantErr: true,
+		},
+		"InvalidProxyUrl": {
+			reason:  "A proxy url that cannot be parsed should be rejected.",
+			options: SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1, ProxyUrl: "http://proxy.example.org:port"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			api := NewSonarApi(tc.options)
+			err := api.do(context.Background(), "GET", "/api/server/version", nil, nil)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("\n%s\napi.do(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	ref := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "sonar-ca"}, Key: "ca.crt"}
+
+	cases := map[string]struct {
+		reason  string
+		ref     *xpv1.SecretKeySelector
+		data    map[string][]byte
+		getErr  error
+		want    []byte
+		wantErr bool
+	}{
+		"NoReference": {
+			reason: "No CA bundle should be loaded when none is referenced.",
+		},
+		"Valid": {
+			reason: "The PEM encoded certificates of the referenced key should be loaded.",
+			ref:    ref,
+			data:   map[string][]byte{"ca.crt": caBundle},
+			want:   caBundle,
+		},
+		"MissingKey": {
+			reason:  "A secret without the referenced key should be rejected.",
+			ref:     ref,
+			data:    map[string][]byte{"tls.crt": caBundle},
+			wantErr: true,
+		},
+		"InvalidPEM": {
+			reason:  "A key holding no PEM encoded certificate should be rejected.",
+			ref:     ref,
+			data:    map[string][]byte{"ca.crt": []byte("not a certificate")},
+			wantErr: true,
+		},
+		"GetError": {
+			reason:  "An error getting the secret should be returned.",
+			ref:     ref,
+			getErr:  errors.New("boom"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+					if key.Namespace != "crossplane-system" || key.Name != "sonar-ca" {
+						t.Errorf("\n%s\nLoadCABundle(...): unexpected secret %s", tc.reason, key)
+					}
+					obj.(*corev1.Secret).Data = tc.data
+					return tc.getErr
+				},
+			}
+
+			got, err := LoadCABundle(context.Background(), kube, tc.ref)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("\n%s\nLoadCABundle(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLoadCABundle(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.want == nil {
+				return
+			}
+
+			// The loaded bundle must be trusted by the client it configures.
+			api := NewSonarApi(SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1, CABundle: got})
+			if api.client.Transport.(*http.Transport).TLSClientConfig.RootCAs == nil {
+				t.Errorf("\n%s\nNewSonarApi(...): want the CA bundle in the root CAs", tc.reason)
+			}
+			if err := api.do(context.Background(), "GET", "/api/server/version", nil, nil); err != nil {
+				t.Errorf("\n%s\napi.do(...): unexpected error: %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, options SonarApiOptions) error
+		want   []string
+	}{
+		"Create": {
+			reason: "Create should not be sent in dry-run mode.",
+			call: func(ctx context.Context, options SonarApiOptions) error {
+				_, _, err := NewProjectClient(options).Create(ctx, "org", "name", "key", "private", "")
+				return err
+			},
+		},
+		"Delete": {
+			reason: "Delete should not be sent in dry-run mode.",
+			call: func(ctx context.Context, options SonarApiOptions) error {
+				return NewProjectClient(options).Delete(ctx, "key")
+			},
+		},
+		"UpdateVisibility": {
+			reason: "UpdateVisibility should not be sent in dry-run mode.",
+			call: func(ctx context.Context, options SonarApiOptions) error {
+				return NewProjectClient(options).UpdateVisibility(ctx, "key", "public")
+			},
+		},
+		"AddUser": {
+			reason: "Mutating calls of other clients should not be sent in dry-run mode.",
+			call: func(ctx context.Context, options SonarApiOptions) error {
+				return NewUserGroupClient(options).AddUser(ctx, "developers", "jdoe")
+			},
+		},
+		"Search": {
+			reason: "Read calls should still be sent in dry-run mode.",
+			call: func(ctx context.Context, options SonarApiOptions) error {
+				_, err := NewProjectClient(options).Search(ctx, "org", SearchOptions{})
+				return err
+			},
+			want: []string{"GET /api/projects/search"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.Method+" "+r.URL.Path)
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			options := SonarApiOptions{Key: "token", BaseUrl: srv.URL, DryRun: true}
+			if err := tc.call(context.Background(), options); err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want requests, +got requests:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("dial tcp: lookup sonar.example.com: no such host")
+}
+
+func TestDoTransportFailure(t *testing.T) {
+	sonarApi := NewSonarApi(SonarApiOptions{Key: "token", BaseUrl: "https://sonar.example.com", MaxAttempts: 1})
+	sonarApi.client = &http.Client{Transport: failingTransport{}}
+
+	cases := map[string]struct {
+		reason string
+		method string
+		out    any
+	}{
+		"Read": {
+			reason: "A read should return an error, not panic, when no response is received.",
+			method: http.MethodGet,
+			out:    &struct{}{},
+		},
+		"Write": {
+			reason: "A write should return an error, not panic, when no response is received.",
+			method: http.MethodPost,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := sonarApi.do(context.Background(), tc.method, "/api/projects/search", url.Values{}, tc.out); err == nil {
+				t.Errorf("\n%s\ndo(...): want error, got nil", tc.reason)
+			}
+		})
+	}
+}
+
+func TestClientsConnectionFailure(t *testing.T) {
+	ctx := context.Background()
+	options := unreachableOptions()
+
+	calls := map[string]func() error{
+		"AlmBindingClient.Set": func() error {
+			return NewAlmBindingClient(options).Set(ctx, "key", AlmBinding{})
+		},
+		"AlmBindingClient.Get": func() error {
+			_, err := NewAlmBindingClient(options).Get(ctx, "key")
+			return err
+		},
+		"ApplicationClient.Create": func() error {
+			return NewApplicationClient(options).Create(ctx, Application{Key: "key"})
+		},
+		"ApplicationClient.Get": func() error {
+			_, err := NewApplicationClient(options).Get(ctx, "key")
+			return err
+		},
+		"MeasuresClient.GetMeasures": func() error {
+			_, err := NewMeasuresClient(options).GetMeasures(ctx, "key", []string{"coverage"})
+			return err
+		},
+		"PermissionTemplateClient.Create": func() error {
+			_, err := NewPermissionTemplateClient(options).Create(ctx, "org", PermissionTemplate{Name: "name"})
+			return err
+		},
+		"PermissionTemplateClient.Groups": func() error {
+			_, err := NewPermissionTemplateClient(options).Groups(ctx, "org", "id")
+			return err
+		},
+		"PortfolioClient.Create": func() error {
+			return NewPortfolioClient(options).Create(ctx, Portfolio{Key: "key"})
+		},
+		"PortfolioClient.Get": func() error {
+			_, err := NewPortfolioClient(options).Get(ctx, "key")
+			return err
+		},
+		"QualityGateClient.Create": func() error {
+			_, err := NewQualityGateClient(options).Create(ctx, "org", "name")
+			return err
+		},
+		"QualityGateClient.GetByName": func() error {
+			_, err := NewQualityGateClient(options).GetByName(ctx, "org", "name")
+			return err
+		},
+		"QualityProfileClient.Create": func() error {
+			_, err := NewQualityProfileClient(options).Create(ctx, "org", "java", "name")
+			return err
+		},
+		"QualityProfileClient.Projects": func() error {
+			_, err := NewQualityProfileClient(options).Projects(ctx, "key")
+			return err
+		},
+		"UserClient.Create": func() error {
+			_, err := NewUserClient(options).Create(ctx, User{Login: "jdoe"}, "password")
+			return err
+		},
+		"UserClient.GetByLogin": func() error {
+			_, err := NewUserClient(options).GetByLogin(ctx, "jdoe")
+			return err
+		},
+		"UserGroupClient.Create": func() error {
+			_, err := NewUserGroupClient(options).Create(ctx, "developers", "")
+			return err
+		},
+		"UserGroupClient.Members": func() error {
+			_, err := NewUserGroupClient(options).Members(ctx, "developers")
+			return err
+		},
+		"WebhookClient.Create": func() error {
+			_, err := NewWebhookClient(options).Create(ctx, WebhookOptions{Name: "name"})
+			return err
+		},
+		"WebhookClient.List": func() error {
+			_, err := NewWebhookClient(options).List(ctx, "org", "")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); err == nil {
+				t.Errorf("%s(...): want error when the API is unreachable, got nil", name)
+			}
+		})
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	const (
+		token    = "squ_0123456789abcdef"
+		password = "s3cret-passw0rd"
+	)
+
+	cases := map[string]struct {
+		reason  string
+		baseUrl func(srv *httptest.Server) string
+		want    []string
+	}{
+		"Response": {
+			reason:  "A request should be logged with its ID, method, path and status, without its query or credentials.",
+			baseUrl: func(srv *httptest.Server) string { return srv.URL },
+			want:    []string{`"requestId"=`, `"method"="POST"`, `"path"="/api/users/create"`, `"status"=200`, `"latency"=`},
+		},
+		"TransportFailure": {
+			reason:  "A request that failed without a response should be logged without the URL of the error.",
+			baseUrl: func(_ *httptest.Server) string { return unreachableOptions().BaseUrl },
+			want:    []string{`"path"="/api/users/create"`, `"error"=`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"user":{"login":"jdoe"}}`))
+			}))
+			defer srv.Close()
+
+			var out strings.Builder
+			logger := logging.NewLogrLogger(funcr.New(func(prefix, args string) {
+				out.WriteString(prefix + " " + args + "\n")
+			}, funcr.Options{Verbosity: 1}))
+
+			c := NewUserClient(SonarApiOptions{Key: token, BaseUrl: tc.baseUrl(srv), Logger: logger})
+			_, _ = c.Create(context.Background(), User{Login: "jdoe", Name: "John Doe"}, password)
+
+			got := out.String()
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("\n%s\nc.Create(...): want %s in the log, got:\n%s", tc.reason, w, got)
+				}
+			}
+			for _, secret := range []string{token, password} {
+				if strings.Contains(got, secret) {
+					t.Errorf("\n%s\nc.Create(...): the log should not contain %q, got:\n%s", tc.reason, secret, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Edition of a SonarQube instance.
+type Edition string
+
+// Editions of SonarQube, from the least to the most featured.
+const (
+	EditionCommunity  Edition = "community"
+	EditionDeveloper  Edition = "developer"
+	EditionEnterprise Edition = "enterprise"
+	EditionDatacenter Edition = "datacenter"
+)
+
+// editionRanks orders the editions by features. Every feature of an edition
+// is available in the editions ranked above it.
+var editionRanks = map[Edition]int{
+	EditionCommunity:  1,
+	EditionDeveloper:  2,
+	EditionEnterprise: 3,
+	EditionDatacenter: 4,
+}
+
+type SystemClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new System Client
+func NewSystemClient(options SonarApiOptions) SystemClient {
+	return SystemClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Version of the instance, e.g. 10.2.1.78527
+// https://next.sonarqube.com/sonarqube/web_api/api/server/version
+func (systemClient SystemClient) Version(ctx context.Context) (string, error) {
+	var version string
+	err := systemClient.sonarApi.do(ctx, "GET", "/api/server/version", nil, &version)
+	return version, err
+}
+
+// ValidateCredentials returns ErrInvalidCredentials when the token of the client
+// is rejected. SonarCloud reports an unknown token as not valid, while
+// SonarQube may refuse the request altogether.
+// https://sonarcloud.io/web_api/api/authentication/validate
+func (systemClient SystemClient) ValidateCredentials(ctx context.Context) error {
+	var response struct {
+		Valid bool `json:"valid"`
+	}
+	err := systemClient.sonarApi.do(ctx, "GET", "/api/authentication/validate", nil, &response)
+
+	var apiErr *SonarAPIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	if !response.Valid {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// MajorVersion returns the major version of a version such as 10.2.1.78527.
+func MajorVersion(version string) (int, error) {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, fmt.Errorf("invalid server version %q", version)
+	}
+	return n, nil
+}
+
+// Edition of the instance. It is empty when the instance does not report one,
+// as SonarCloud does.
+// https://next.sonarqube.com/sonarqube/web_api/api/navigation/global
+func (systemClient SystemClient) Edition(ctx context.Context) (Edition, error) {
+	var response struct {
+		Edition Edition `json:"edition"`
+	}
+	err := systemClient.sonarApi.do(ctx, "GET", "/api/navigation/global", nil, &response)
+	return response.Edition, err
+}
+
+// RequireEdition returns ErrUnsupportedEdition, naming the edition of the
+// instance, when the instance runs an edition below minimum. feature names
+// what requires the edition, e.g. "portfolios". Instances that do not report
+// their edition are assumed to support every feature.
+func (systemClient SystemClient) RequireEdition(ctx context.Context, feature string, minimum Edition) error {
+	edition, err := systemClient.Edition(ctx)
+	if err != nil {
+		return err
+	}
+	return checkEdition(edition, feature, minimum)
+}
+
+// checkEdition returns ErrUnsupportedEdition when edition is known and below
+// minimum.
+func checkEdition(edition Edition, feature string, minimum Edition) error {
+	rank, ok := editionRanks[edition]
+	if !ok || rank >= editionRanks[minimum] {
+		return nil
+	}
+	return fmt.Errorf("%w: %s require the %s edition or above, this instance runs the %s edition", ErrUnsupportedEdition, feature, minimum, edition)
+}
+
+// unsupportedEdition returns the ErrUnsupportedEdition reported when the
+// endpoint of feature is missing. It names the edition of the instance when
+// it can be detected.
+func unsupportedEdition(ctx context.Context, sonarApi SonarApi, feature string, minimum Edition) error {
+	edition, err := SystemClient{sonarApi: sonarApi}.Edition(ctx)
+	if err == nil {
+		if err := checkEdition(edition, feature, minimum); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s require the %s edition or above", ErrUnsupportedEdition, feature, minimum)
+}
@@ -0,0 +1,196 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVersion(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("10.2.1.78527\n"))
+	}))
+	defer srv.Close()
+
+	c := NewSystemClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Version(context.Background())
+	if err != nil {
+		t.Fatalf("c.Version(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("10.2.1.78527", got); diff != "" {
+		t.Errorf("c.Version(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("/api/server/version", path); diff != "" {
+		t.Errorf("c.Version(...): -want path, +got path:\n%s\n", diff)
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		body   string
+		want   error
+	}{
+		"Valid": {
+			reason: "A token reported as valid should be accepted.",
+			status: http.StatusOK,
+			body:   `{"valid":true}`,
+		},
+		"NotValid": {
+			reason: "A token reported as not valid should be rejected.",
+			status: http.StatusOK,
+			body:   `{"valid":false}`,
+			want:   ErrInvalidCredentials,
+		},
+		"Unauthorized": {
+			reason: "A token the API refuses to answer should be rejected.",
+			status: http.StatusUnauthorized,
+			want:   ErrInvalidCredentials,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewSystemClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := c.ValidateCredentials(context.Background())
+			if !errors.Is(err, tc.want) {
+				t.Errorf("\n%s\nc.ValidateCredentials(...): want error %v, got %v", tc.reason, tc.want, err)
+			}
+			if diff := cmp.Diff("/api/authentication/validate", path); diff != "" {
+				t.Errorf("\n%s\nc.ValidateCredentials(...): -want path, +got path:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMajorVersion(t *testing.T) {
+	type want struct {
+		major int
+		err   bool
+	}
+
+	cases := map[string]struct {
+		version string
+		want    want
+	}{
+		"SonarQube9":  {version: "9.9.1.69595", want: want{major: 9}},
+		"SonarQube10": {version: "10.2.1.78527", want: want{major: 10}},
+		"MajorOnly":   {version: "10", want: want{major: 10}},
+		"Empty":       {version: "", want: want{err: true}},
+		"Invalid":     {version: "latest", want: want{err: true}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MajorVersion(tc.version)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("MajorVersion(%q): want error %t, got %v", tc.version, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.major, got); diff != "" {
+				t.Errorf("MajorVersion(%q): -want, +got:\n%s\n", tc.version, diff)
+			}
+		})
+	}
+}
+
+func TestEdition(t *testing.T) {
+	cases := map[string]struct {
+		body string
+		want Edition
+	}{
+		"Community":  {body: `{"edition":"community","version":"10.2"}`, want: EditionCommunity},
+		"Developer":  {body: `{"edition":"developer","version":"10.2"}`, want: EditionDeveloper},
+		"Enterprise": {body: `{"edition":"enterprise","version":"10.2"}`, want: EditionEnterprise},
+		"SonarCloud": {body: `{"canAdmin":false}`, want: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c := NewSystemClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := c.Edition(context.Background())
+			if err != nil {
+				t.Fatalf("c.Edition(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("c.Edition(...): -want, +got:\n%s\n", diff)
+			}
+			if diff := cmp.Diff("/api/navigation/global", path); diff != "" {
+				t.Errorf("c.Edition(...): -want path, +got path:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestRequireEdition(t *testing.T) {
+	cases := map[string]struct {
+		edition Edition
+		minimum Edition
+		want    bool
+	}{
+		"CommunityBelowDeveloper":   {edition: EditionCommunity, minimum: EditionDeveloper, want: true},
+		"DeveloperBelowEnterprise":  {edition: EditionDeveloper, minimum: EditionEnterprise, want: true},
+		"EnterpriseMeetsEnterprise": {edition: EditionEnterprise, minimum: EditionEnterprise},
+		"DatacenterAboveDeveloper":  {edition: EditionDatacenter, minimum: EditionDeveloper},
+		"Unknown":                   {edition: "", minimum: EditionEnterprise},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"edition":"` + string(tc.edition) + `"}`))
+			}))
+			defer srv.Close()
+
+			c := NewSystemClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			err := c.RequireEdition(context.Background(), "portfolios", tc.minimum)
+			if got := errors.Is(err, ErrUnsupportedEdition); got != tc.want {
+				t.Errorf("c.RequireEdition(...): want ErrUnsupportedEdition %t, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestUnsupportedEditionNamesEdition(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/navigation/global" {
+			_, _ = w.Write([]byte(`{"edition":"community"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Unknown url : ` + r.URL.Path + `"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewApplicationClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	_, err := c.Get(context.Background(), "payments")
+	if !errors.Is(err, ErrUnsupportedEdition) {
+		t.Fatalf("c.Get(...): want ErrUnsupportedEdition, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "this instance runs the community edition") {
+		t.Errorf("c.Get(...): want the detected edition in %q", err.Error())
+	}
+}
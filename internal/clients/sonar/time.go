@@ -0,0 +1,44 @@
+package sonar
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SonarTimeLayout is the layout of the times returned by the sonar api. The
+// offset has no colon, so it is not RFC 3339.
+const SonarTimeLayout = "2006-01-02T15:04:05-0700"
+
+// SonarTime is a time returned by the sonar api, e.g. 2022-11-10T19:33:53+0100.
+// A missing, null or empty time is the zero time.
+type SonarTime struct {
+	time.Time
+}
+
+// UnmarshalJSON parses a time in SonarTimeLayout.
+func (t *SonarTime) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil || *s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.Parse(SonarTimeLayout, *s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalJSON formats a time in SonarTimeLayout, and the zero time as an
+// empty string.
+func (t SonarTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return json.Marshal("")
+	}
+	return json.Marshal(t.Format(SonarTimeLayout))
+}
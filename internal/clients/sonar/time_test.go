@@ -0,0 +1,88 @@
+package sonar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSonarTimeUnmarshalJSON(t *testing.T) {
+	type want struct {
+		t   time.Time
+		err bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   string
+		want   want
+	}{
+		"SonarCloudLayout": {
+			reason: "A time with an offset without colon should be parsed.",
+			data:   `{"lastAnalysisDate":"2022-11-10T19:33:53+0100"}`,
+			want:   want{t: time.Date(2022, time.November, 10, 18, 33, 53, 0, time.UTC)},
+		},
+		"Missing": {
+			reason: "A missing time should be the zero time.",
+			data:   `{}`,
+		},
+		"Empty": {
+			reason: "An empty time should be the zero time.",
+			data:   `{"lastAnalysisDate":""}`,
+		},
+		"Null": {
+			reason: "A null time should be the zero time.",
+			data:   `{"lastAnalysisDate":null}`,
+		},
+		"RFC3339": {
+			reason: "A time in another layout should be rejected.",
+			data:   `{"lastAnalysisDate":"2022-11-10T19:33:53+01:00"}`,
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var p Project
+			err := json.Unmarshal([]byte(tc.data), &p)
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\njson.Unmarshal(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if !p.LastAnalysisDate.Equal(tc.want.t) {
+				t.Errorf("\n%s\njson.Unmarshal(...): want %v, got %v", tc.reason, tc.want.t, p.LastAnalysisDate.Time)
+			}
+		})
+	}
+}
+
+func TestSonarTimeMarshalJSON(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		t      SonarTime
+		want   string
+	}{
+		"Time": {
+			reason: "A time should be formatted in the SonarCloud layout.",
+			t:      SonarTime{Time: time.Date(2022, time.November, 10, 19, 33, 53, 0, time.FixedZone("CET", 3600))},
+			want:   `"2022-11-10T19:33:53+0100"`,
+		},
+		"Zero": {
+			reason: "The zero time should be formatted as an empty string.",
+			want:   `""`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(tc.t)
+			if err != nil {
+				t.Fatalf("\n%s\njson.Marshal(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\njson.Marshal(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+var ErrUserNotFound = errors.New("User not found")
+
+type User struct {
+	Login  string `json:"login"`
+	Name   string `json:"name"`
+	Email  string `json:"email,omitempty"`
+	Active bool   `json:"active"`
+	Local  bool   `json:"local"`
+}
+
+type UserClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new User Client
+func NewUserClient(options SonarApiOptions) UserClient {
+	return UserClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Create new local user, or reactivate a deactivated one with the same login
+// https://next.sonarqube.com/sonarqube/web_api/api/users/create
+func (userClient UserClient) Create(ctx context.Context, user User, password string) (User, error) {
+	params := url.Values{}
+	params.Add("login", user.Login)
+	params.Add("name", user.Name)
+	if user.Email != "" {
+		params.Add("email", user.Email)
+	}
+	if password != "" {
+		params.Add("password", password)
+	}
+	params.Add("local", "true")
+
+	var response struct {
+		User User `json:"user"`
+	}
+	err := userClient.sonarApi.do(ctx, "POST", "/api/users/create", params, &response)
+	return response.User, err
+}
+
+// Update the name and email of a user
+// https://next.sonarqube.com/sonarqube/web_api/api/users/update
+func (userClient UserClient) Update(ctx context.Context, user User) error {
+	params := url.Values{}
+	params.Add("login", user.Login)
+	params.Add("name", user.Name)
+	if user.Email != "" {
+		params.Add("email", user.Email)
+	}
+
+	return userClient.sonarApi.do(ctx, "POST", "/api/users/update", params, nil)
+}
+
+// Deactivate a user. SonarQube never deletes users.
+// https://next.sonarqube.com/sonarqube/web_api/api/users/deactivate
+func (userClient UserClient) Deactivate(ctx context.Context, login string) error {
+	params := url.Values{}
+	params.Add("login", login)
+
+	return userClient.sonarApi.do(ctx, "POST", "/api/users/deactivate", params, nil)
+}
+
+// Search users by login, name or email
+// https://next.sonarqube.com/sonarqube/web_api/api/users/search
+func (userClient UserClient) Search(ctx context.Context, query string) ([]User, error) {
+	params := url.Values{}
+	params.Add("q", query)
+
+	var response struct {
+		Users []User `json:"users"`
+	}
+	if err := userClient.sonarApi.do(ctx, "GET", "/api/users/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Users, nil
+}
+
+// Get a single user by login. Search matches logins partially, so only an
+// exact match is returned.
+func (userClient UserClient) GetByLogin(ctx context.Context, login string) (User, error) {
+	users, err := userClient.Search(ctx, login)
+	if err != nil {
+		return User{}, err
+	}
+
+	for _, user := range users {
+		if user.Login == login {
+			return user, nil
+		}
+	}
+
+	return User{}, ErrUserNotFound
+}
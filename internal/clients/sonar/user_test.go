@@ -0,0 +1,112 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserClient(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+		result User
+	}
+
+	cases := map[string]struct {
+		reason   string
+		response string
+		call     func(ctx context.Context, c UserClient) (User, error)
+		want     want
+	}{
+		"Create": {
+			reason:   "Create should send the user and password and return the created user.",
+			response: `{"user":{"login":"jdoe","name":"John Doe","email":"jdoe@example.org","active":true,"local":true}}`,
+			call: func(ctx context.Context, c UserClient) (User, error) {
+				return c.Create(ctx, User{Login: "jdoe", Name: "John Doe", Email: "jdoe@example.org"}, "s3cr3t")
+			},
+			want: want{
+				path:   "/api/users/create",
+				params: url.Values{"login": {"jdoe"}, "name": {"John Doe"}, "email": {"jdoe@example.org"}, "password": {"s3cr3t"}, "local": {"true"}},
+				result: User{Login: "jdoe", Name: "John Doe", Email: "jdoe@example.org", Active: true, Local: true},
+			},
+		},
+		"Update": {
+			reason: "Update should send the name and email of the user.",
+			call: func(ctx context.Context, c UserClient) (User, error) {
+				return User{}, c.Update(ctx, User{Login: "jdoe", Name: "Jane Doe", Email: "jane@example.org"})
+			},
+			want: want{
+				path:   "/api/users/update",
+				params: url.Values{"login": {"jdoe"}, "name": {"Jane Doe"}, "email": {"jane@example.org"}},
+			},
+		},
+		"Deactivate": {
+			reason: "Deactivate should target the user by login.",
+			call: func(ctx context.Context, c UserClient) (User, error) {
+				return User{}, c.Deactivate(ctx, "jdoe")
+			},
+			want: want{
+				path:   "/api/users/deactivate",
+				params: url.Values{"login": {"jdoe"}},
+			},
+		},
+		"GetByLogin": {
+			reason:   "GetByLogin should return the user whose login matches exactly, not a partial match.",
+			response: `{"users":[{"login":"jdoe2","name":"Other","active":true},{"login":"jdoe","name":"John Doe","active":true}]}`,
+			call: func(ctx context.Context, c UserClient) (User, error) {
+				return c.GetByLogin(ctx, "jdoe")
+			},
+			want: want{
+				path:   "/api/users/search",
+				params: url.Values{"q": {"jdoe"}},
+				result: User{Login: "jdoe", Name: "John Doe", Active: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				path = r.URL.Path
+				params = r.Form
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewUserClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := tc.call(context.Background(), c)
+			if err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want path, +got path:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want params, +got params:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestUserGetByLoginNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"users":[{"login":"jdoe2","name":"Other","active":true}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewUserClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.GetByLogin(context.Background(), "jdoe"); err != ErrUserNotFound {
+		t.Errorf("GetByLogin(...): want ErrUserNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package sonar
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+var ErrGroupNotFound = errors.New("Group not found")
+
+type Group struct {
+	Id           ID     `json:"id,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	MembersCount int    `json:"membersCount"`
+	Default      bool   `json:"default"`
+}
+
+type UserGroupClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new User Group Client
+func NewUserGroupClient(options SonarApiOptions) UserGroupClient {
+	return UserGroupClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Create new group
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/create
+func (userGroupClient UserGroupClient) Create(ctx context.Context, name string, description string) (Group, error) {
+	params := url.Values{}
+	params.Add("name", name)
+	if description != "" {
+		params.Add("description", description)
+	}
+
+	var response struct {
+		Group Group `json:"group"`
+	}
+	err := userGroupClient.sonarApi.do(ctx, "POST", "/api/user_groups/create", params, &response)
+	return response.Group, err
+}
+
+// Update the description of a group, identified by its name
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/update
+func (userGroupClient UserGroupClient) Update(ctx context.Context, name string, description string) error {
+	params := url.Values{}
+	params.Add("currentName", name)
+	params.Add("description", description)
+
+	return userGroupClient.sonarApi.do(ctx, "POST", "/api/user_groups/update", params, nil)
+}
+
+// Delete group
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/delete
+func (userGroupClient UserGroupClient) Delete(ctx context.Context, name string) error {
+	params := url.Values{}
+	params.Add("name", name)
+
+	return userGroupClient.sonarApi.do(ctx, "POST", "/api/user_groups/delete", params, nil)
+}
+
+// Search groups by name
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/search
+func (userGroupClient UserGroupClient) Search(ctx context.Context, query string) ([]Group, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("ps", strconv.Itoa(MaxPageSize))
+
+	var response struct {
+		Groups []Group `json:"groups"`
+	}
+	if err := userGroupClient.sonarApi.do(ctx, "GET", "/api/user_groups/search", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Groups, nil
+}
+
+// Get a single group by name. Search matches names partially, so only an
+// exact match is returned.
+func (userGroupClient UserGroupClient) GetByName(ctx context.Context, name string) (Group, error) {
+	groups, err := userGroupClient.Search(ctx, name)
+	if err != nil {
+		return Group{}, err
+	}
+
+	for _, group := range groups {
+		if group.Name == name {
+			return group, nil
+		}
+	}
+
+	return Group{}, ErrGroupNotFound
+}
+
+// Members returns the logins of every member of a group
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/users
+func (userGroupClient UserGroupClient) Members(ctx context.Context, name string) ([]string, error) {
+	var members []string
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("name", name)
+		params.Add("selected", "selected")
+		params.Add("p", strconv.Itoa(page))
+		params.Add("ps", strconv.Itoa(MaxPageSize))
+
+		var response struct {
+			Total int `json:"total"`
+			Users []struct {
+				Login string `json:"login"`
+			} `json:"users"`
+		}
+		if err := userGroupClient.sonarApi.do(ctx, "GET", "/api/user_groups/users", params, &response); err != nil {
+			return nil, err
+		}
+
+		for _, u := range response.Users {
+			members = append(members, u.Login)
+		}
+		if len(response.Users) == 0 || len(members) >= response.Total {
+			return members, nil
+		}
+	}
+}
+
+// Add a user to a group
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/add_user
+func (userGroupClient UserGroupClient) AddUser(ctx context.Context, name string, login string) error {
+	params := url.Values{}
+	params.Add("name", name)
+	params.Add("login", login)
+
+	return userGroupClient.sonarApi.do(ctx, "POST", "/api/user_groups/add_user", params, nil)
+}
+
+// Remove a user from a group
+// https://next.sonarqube.com/sonarqube/web_api/api/user_groups/remove_user
+func (userGroupClient UserGroupClient) RemoveUser(ctx context.Context, name string, login string) error {
+	params := url.Values{}
+	params.Add("name", name)
+	params.Add("login", login)
+
+	return userGroupClient.sonarApi.do(ctx, "POST", "/api/user_groups/remove_user", params, nil)
+}
@@ -0,0 +1,53 @@
+package sonar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserGroupMembers(t *testing.T) {
+	pages := [][]string{{"alice", "bob"}, {"carol"}}
+
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.FormValue("name")+"/"+r.FormValue("selected")+"/"+r.FormValue("p"))
+		p, _ := strconv.Atoi(r.FormValue("p"))
+		users := make([]map[string]string, 0, len(pages[p-1]))
+		for _, login := range pages[p-1] {
+			users = append(users, map[string]string{"login": login})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"total": 3, "users": users})
+	}))
+	defer srv.Close()
+
+	c := NewUserGroupClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Members(context.Background(), "developers")
+	if err != nil {
+		t.Fatalf("c.Members(...): unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"alice", "bob", "carol"}, got); diff != "" {
+		t.Errorf("c.Members(...): -want, +got:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{"developers/selected/1", "developers/selected/2"}, requested); diff != "" {
+		t.Errorf("c.Members(...): -want pages requested, +got pages requested:\n%s\n", diff)
+	}
+}
+
+func TestUserGroupGetByNameNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"groups":[{"name":"developers-eu"}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewUserGroupClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if _, err := c.GetByName(context.Background(), "developers"); err != ErrGroupNotFound {
+		t.Errorf("GetByName(...): want ErrGroupNotFound, got %v", err)
+	}
+}
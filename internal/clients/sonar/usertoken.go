@@ -0,0 +1,86 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+)
+
+// Types of user token.
+const (
+	UserTokenTypeUser            = "USER_TOKEN"
+	UserTokenTypeGlobalAnalysis  = "GLOBAL_ANALYSIS_TOKEN"
+	UserTokenTypeProjectAnalysis = "PROJECT_ANALYSIS_TOKEN"
+)
+
+type UserToken struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type,omitempty"`
+	ProjectKey string    `json:"projectKey,omitempty"`
+	CreatedAt  SonarTime `json:"createdAt"`
+	// Token is only returned when the token is generated.
+	Token string `json:"token,omitempty"`
+}
+
+type UserTokenClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new User Token Client
+func NewUserTokenClient(options SonarApiOptions) UserTokenClient {
+	return UserTokenClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// Generate a token for the authenticated user. ProjectKey scopes a project
+// analysis token to a project.
+// https://sonarcloud.io/web_api/api/user_tokens/generate
+func (userTokenClient UserTokenClient) Generate(ctx context.Context, token UserToken) (UserToken, error) {
+	params := url.Values{}
+	params.Add("name", token.Name)
+	if token.Type != "" {
+		params.Add("type", token.Type)
+	}
+	if token.ProjectKey != "" {
+		params.Add("projectKey", token.ProjectKey)
+	}
+
+	var response UserToken
+	err := userTokenClient.sonarApi.do(ctx, "POST", "/api/user_tokens/generate", params, &response)
+	return response, err
+}
+
+// Revoke a token of the authenticated user, identified by its name
+// https://sonarcloud.io/web_api/api/user_tokens/revoke
+func (userTokenClient UserTokenClient) Revoke(ctx context.Context, name string) error {
+	params := url.Values{}
+	params.Add("name", name)
+
+	return userTokenClient.sonarApi.do(ctx, "POST", "/api/user_tokens/revoke", params, nil)
+}
+
+// Search the tokens of the authenticated user. The values of the tokens are
+// never returned.
+// https://sonarcloud.io/web_api/api/user_tokens/search
+func (userTokenClient UserTokenClient) Search(ctx context.Context) ([]UserToken, error) {
+	var response struct {
+		UserTokens []struct {
+			UserToken
+			Project *struct {
+				Key string `json:"key"`
+			} `json:"project"`
+		} `json:"userTokens"`
+	}
+	if err := userTokenClient.sonarApi.do(ctx, "GET", "/api/user_tokens/search", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]UserToken, 0, len(response.UserTokens))
+	for _, t := range response.UserTokens {
+		if t.Project != nil {
+			t.ProjectKey = t.Project.Key
+		}
+		tokens = append(tokens, t.UserToken)
+	}
+	return tokens, nil
+}
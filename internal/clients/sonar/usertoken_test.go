@@ -0,0 +1,109 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateUserToken(t *testing.T) {
+	response := `{
+		"login": "ci",
+		"name": "crossplane-key",
+		"token": "squ_0123456789abcdef",
+		"createdAt": "2022-11-02T10:15:00+0000",
+		"type": "PROJECT_ANALYSIS_TOKEN",
+		"projectKey": "key"
+	}`
+
+	var method string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method = r.Method
+		params = r.Form
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewUserTokenClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Generate(context.Background(), UserToken{Name: "crossplane-key", Type: UserTokenTypeProjectAnalysis, ProjectKey: "key"})
+	if err != nil {
+		t.Fatalf("c.Generate(...): unexpected error: %v", err)
+	}
+
+	want := UserToken{
+		Name:       "crossplane-key",
+		Type:       UserTokenTypeProjectAnalysis,
+		ProjectKey: "key",
+		CreatedAt:  SonarTime{time.Date(2022, 11, 2, 10, 15, 0, 0, time.UTC)},
+		Token:      "squ_0123456789abcdef",
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b SonarTime) bool { return a.Equal(b.Time) })); diff != "" {
+		t.Errorf("c.Generate(...): -want, +got:\n%s\n", diff)
+	}
+	if method != http.MethodPost {
+		t.Errorf("c.Generate(...): want method POST, got %s", method)
+	}
+	wantParams := url.Values{"name": {"crossplane-key"}, "type": {"PROJECT_ANALYSIS_TOKEN"}, "projectKey": {"key"}}
+	if diff := cmp.Diff(wantParams, params); diff != "" {
+		t.Errorf("c.Generate(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestRevokeUserToken(t *testing.T) {
+	var path string
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		path = r.URL.Path
+		params = r.Form
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := NewUserTokenClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	if err := c.Revoke(context.Background(), "crossplane-key"); err != nil {
+		t.Fatalf("c.Revoke(...): unexpected error: %v", err)
+	}
+	if path != "/api/user_tokens/revoke" {
+		t.Errorf("c.Revoke(...): want path /api/user_tokens/revoke, got %s", path)
+	}
+	if diff := cmp.Diff(url.Values{"name": {"crossplane-key"}}, params); diff != "" {
+		t.Errorf("c.Revoke(...): -want params, +got params:\n%s\n", diff)
+	}
+}
+
+func TestSearchUserTokens(t *testing.T) {
+	response := `{
+		"login": "ci",
+		"userTokens": [
+			{"name": "laptop", "createdAt": "2022-01-10T08:00:00+0000", "type": "USER_TOKEN"},
+			{"name": "crossplane-key", "createdAt": "2022-11-02T10:15:00+0000", "type": "PROJECT_ANALYSIS_TOKEN", "project": {"key": "key", "name": "Payments"}}
+		]
+	}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	c := NewUserTokenClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.Search(context.Background())
+	if err != nil {
+		t.Fatalf("c.Search(...): unexpected error: %v", err)
+	}
+
+	want := []UserToken{
+		{Name: "laptop", Type: UserTokenTypeUser, CreatedAt: SonarTime{time.Date(2022, 1, 10, 8, 0, 0, 0, time.UTC)}},
+		{Name: "crossplane-key", Type: UserTokenTypeProjectAnalysis, ProjectKey: "key", CreatedAt: SonarTime{time.Date(2022, 11, 2, 10, 15, 0, 0, time.UTC)}},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b SonarTime) bool { return a.Equal(b.Time) })); diff != "" {
+		t.Errorf("c.Search(...): -want, +got:\n%s\n", diff)
+	}
+}
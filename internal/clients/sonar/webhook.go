@@ -0,0 +1,139 @@
+package sonar
+
+import (
+	"context"
+	"net/url"
+)
+
+type Webhook struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	HasSecret bool   `json:"hasSecret"`
+}
+
+type WebhookClient struct {
+	sonarApi SonarApi
+}
+
+// Creates a new Webhook Client
+func NewWebhookClient(options SonarApiOptions) WebhookClient {
+	return WebhookClient{
+		sonarApi: NewSonarApi(options),
+	}
+}
+
+// WebhookOptions identify the owner of a webhook and describe its desired
+// state. Webhooks belong to the organization when Project is empty.
+type WebhookOptions struct {
+	Organization string
+	Project      string
+	Name         string
+	Url          string
+	Secret       string
+}
+
+// Create new webhook
+// https://sonarcloud.io/web_api/api/webhooks/create
+func (webhookClient WebhookClient) Create(ctx context.Context, options WebhookOptions) (Webhook, error) {
+	params := url.Values{}
+	webhookClient.sonarApi.addOrganization(params, options.Organization)
+	if options.Project != "" {
+		params.Add("project", options.Project)
+	}
+	params.Add("name", options.Name)
+	params.Add("url", options.Url)
+	if options.Secret != "" {
+		params.Add("secret", options.Secret)
+	}
+
+	var response struct {
+		Webhook Webhook `json:"webhook"`
+	}
+	err := webhookClient.sonarApi.do(ctx, "POST", "/api/webhooks/create", params, &response)
+	return response.Webhook, err
+}
+
+// Update a webhook, identified by its key. The secret is always sent, since
+// the API keeps the secret of a webhook when none is sent, so an empty secret
+// removes it.
+// https://sonarcloud.io/web_api/api/webhooks/update
+func (webhookClient WebhookClient) Update(ctx context.Context, key string, options WebhookOptions) error {
+	params := url.Values{}
+	params.Add("webhook", key)
+	params.Add("name", options.Name)
+	params.Add("url", options.Url)
+	params.Add("secret", options.Secret)
+
+	return webhookClient.sonarApi.do(ctx, "POST", "/api/webhooks/update", params, nil)
+}
+
+// Delete webhook
+// https://sonarcloud.io/web_api/api/webhooks/delete
+func (webhookClient WebhookClient) Delete(ctx context.Context, key string) error {
+	params := url.Values{}
+	params.Add("webhook", key)
+
+	return webhookClient.sonarApi.do(ctx, "POST", "/api/webhooks/delete", params, nil)
+}
+
+// List the webhooks of an organization, or of a project when one is given
+// https://sonarcloud.io/web_api/api/webhooks/list
+func (webhookClient WebhookClient) List(ctx context.Context, organization string, project string) ([]Webhook, error) {
+	params := url.Values{}
+	webhookClient.sonarApi.addOrganization(params, organization)
+	if project != "" {
+		params.Add("project", project)
+	}
+
+	var response struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+	if err := webhookClient.sonarApi.do(ctx, "GET", "/api/webhooks/list", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Webhooks, nil
+}
+
+// WebhookDelivery is a notification sent by a webhook.
+type WebhookDelivery struct {
+	Id           string    `json:"id"`
+	ComponentKey string    `json:"componentKey"`
+	CeTaskId     string    `json:"ceTaskId"`
+	Name         string    `json:"name"`
+	Url          string    `json:"url"`
+	At           SonarTime `json:"at"`
+	Success      bool      `json:"success"`
+	HttpStatus   int       `json:"httpStatus"`
+	DurationMs   int       `json:"durationMs"`
+	// Payload is only returned by GetDelivery.
+	Payload string `json:"payload,omitempty"`
+}
+
+// List the most recent deliveries of a webhook, the latest first
+// https://sonarcloud.io/web_api/api/webhooks/deliveries
+func (webhookClient WebhookClient) ListDeliveries(ctx context.Context, webhookKey string) ([]WebhookDelivery, error) {
+	params := url.Values{}
+	params.Add("webhook", webhookKey)
+
+	var response struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+	}
+	if err := webhookClient.sonarApi.do(ctx, "GET", "/api/webhooks/deliveries", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Deliveries, nil
+}
+
+// Get a single delivery of a webhook, including its payload
+// https://sonarcloud.io/web_api/api/webhooks/delivery
+func (webhookClient WebhookClient) GetDelivery(ctx context.Context, deliveryId string) (WebhookDelivery, error) {
+	params := url.Values{}
+	params.Add("deliveryId", deliveryId)
+
+	var response struct {
+		Delivery WebhookDelivery `json:"delivery"`
+	}
+	err := webhookClient.sonarApi.do(ctx, "GET", "/api/webhooks/delivery", params, &response)
+	return response.Delivery, err
+}
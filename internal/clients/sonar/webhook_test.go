@@ -0,0 +1,186 @@
+package sonar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebhookClient(t *testing.T) {
+	type want struct {
+		method string
+		path   string
+		params url.Values
+		result Webhook
+	}
+
+	cases := map[string]struct {
+		reason   string
+		response string
+		call     func(ctx context.Context, c WebhookClient) (Webhook, error)
+		want     want
+	}{
+		"Create": {
+			reason:   "Create should send the owner, name, url and secret and return the created webhook.",
+			response: `{"webhook":{"key":"AXW1","name":"ci","url":"https://ci.example.com/hook","hasSecret":true}}`,
+			call: func(ctx context.Context, c WebhookClient) (Webhook, error) {
+				return c.Create(ctx, WebhookOptions{Organization: "org", Project: "key", Name: "ci", Url: "https://ci.example.com/hook", Secret: "s3cr3t"})
+			},
+			want: want{
+				method: "POST",
+				path:   "/api/webhooks/create",
+				params: url.Values{"organization": {"org"}, "project": {"key"}, "name": {"ci"}, "url": {"https://ci.example.com/hook"}, "secret": {"s3cr3t"}},
+				result: Webhook{Key: "AXW1", Name: "ci", Url: "https://ci.example.com/hook", HasSecret: true},
+			},
+		},
+		"CreateOrganizationWebhook": {
+			reason:   "Create should omit the project and secret when they are not set.",
+			response: `{"webhook":{"key":"AXW2","name":"ci","url":"https://ci.example.com/hook"}}`,
+			call: func(ctx context.Context, c WebhookClient) (Webhook, error) {
+				return c.Create(ctx, WebhookOptions{Organization: "org", Name: "ci", Url: "https://ci.example.com/hook"})
+			},
+			want: want{
+				method: "POST",
+				path:   "/api/webhooks/create",
+				params: url.Values{"organization": {"org"}, "name": {"ci"}, "url": {"https://ci.example.com/hook"}},
+				result: Webhook{Key: "AXW2", Name: "ci", Url: "https://ci.example.com/hook"},
+			},
+		},
+		"Update": {
+			reason: "Update should target the webhook by its key, and send an empty secret to remove the secret when none is set.",
+			call: func(ctx context.Context, c WebhookClient) (Webhook, error) {
+				return Webhook{}, c.Update(ctx, "AXW1", WebhookOptions{Name: "ci", Url: "https://ci.example.com/new"})
+			},
+			want: want{
+				method: "POST",
+				path:   "/api/webhooks/update",
+				params: url.Values{"webhook": {"AXW1"}, "name": {"ci"}, "url": {"https://ci.example.com/new"}, "secret": {""}},
+			},
+		},
+		"Delete": {
+			reason: "Delete should target the webhook by its key.",
+			call: func(ctx context.Context, c WebhookClient) (Webhook, error) {
+				return Webhook{}, c.Delete(ctx, "AXW1")
+			},
+			want: want{
+				method: "POST",
+				path:   "/api/webhooks/delete",
+				params: url.Values{"webhook": {"AXW1"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var method, path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				method = r.Method
+				path = r.URL.Path
+				params = r.Form
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			c := NewWebhookClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+			got, err := tc.call(context.Background(), c)
+			if err != nil {
+				t.Fatalf("\n%s\n%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want.method, method); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want method, +got method:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want path, +got path:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want params, +got params:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\n%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestWebhookList(t *testing.T) {
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(`{"webhooks":[{"key":"AXW1","name":"ci","url":"https://ci.example.com/hook","hasSecret":false}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewWebhookClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.List(context.Background(), "org", "key")
+	if err != nil {
+		t.Fatalf("List(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(url.Values{"organization": {"org"}, "project": {"key"}}, params); diff != "" {
+		t.Errorf("List(...): -want params, +got params:\n%s\n", diff)
+	}
+	want := []Webhook{{Key: "AXW1", Name: "ci", Url: "https://ci.example.com/hook"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("List(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestWebhookListDeliveries(t *testing.T) {
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":10,"total":2},"deliveries":[` +
+			`{"id":"d2","componentKey":"key","ceTaskId":"t2","name":"ci","url":"https://ci.example.com/hook","at":"2022-11-10T19:33:53+0100","success":false,"httpStatus":502,"durationMs":31},` +
+			`{"id":"d1","componentKey":"key","ceTaskId":"t1","name":"ci","url":"https://ci.example.com/hook","at":"2022-11-09T10:00:00+0100","success":true,"httpStatus":200,"durationMs":12}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewWebhookClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.ListDeliveries(context.Background(), "AXW1")
+	if err != nil {
+		t.Fatalf("ListDeliveries(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(url.Values{"webhook": {"AXW1"}}, params); diff != "" {
+		t.Errorf("ListDeliveries(...): -want params, +got params:\n%s\n", diff)
+	}
+
+	cet := time.FixedZone("", 3600)
+	want := []WebhookDelivery{
+		{Id: "d2", ComponentKey: "key", CeTaskId: "t2", Name: "ci", Url: "https://ci.example.com/hook", At: SonarTime{time.Date(2022, 11, 10, 19, 33, 53, 0, cet)}, HttpStatus: 502, DurationMs: 31},
+		{Id: "d1", ComponentKey: "key", CeTaskId: "t1", Name: "ci", Url: "https://ci.example.com/hook", At: SonarTime{time.Date(2022, 11, 9, 10, 0, 0, 0, cet)}, Success: true, HttpStatus: 200, DurationMs: 12},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b SonarTime) bool { return a.Equal(b.Time) })); diff != "" {
+		t.Errorf("ListDeliveries(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestWebhookGetDelivery(t *testing.T) {
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		params = r.Form
+		_, _ = w.Write([]byte(`{"delivery":{"id":"d2","httpStatus":502,"success":false,"payload":"{\"status\":\"SUCCESS\"}"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewWebhookClient(SonarApiOptions{Key: "token", BaseUrl: srv.URL})
+	got, err := c.GetDelivery(context.Background(), "d2")
+	if err != nil {
+		t.Fatalf("GetDelivery(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(url.Values{"deliveryId": {"d2"}}, params); diff != "" {
+		t.Errorf("GetDelivery(...): -want params, +got params:\n%s\n", diff)
+	}
+	want := WebhookDelivery{Id: "d2", HttpStatus: 502, Payload: `{"status":"SUCCESS"}`}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetDelivery(...): -want, +got:\n%s\n", diff)
+	}
+}
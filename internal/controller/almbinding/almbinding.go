@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package almbinding
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/almbinding/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotALMBinding = "managed resource is not a ALMBinding custom resource"
+	errTrackPCUsage  = "cannot track ProviderConfig usage"
+	errGetPC         = "cannot get ProviderConfig"
+
+	errGetBinding    = "cannot get ALM binding"
+	errSetBinding    = "cannot set ALM binding"
+	errDeleteBinding = "cannot delete ALM binding"
+)
+
+// Setup adds a controller that reconciles ALMBinding managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.ALMBindingGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ALMBindingGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewAlmBindingClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ALMBinding{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.AlmBindingClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.ALMBinding)
+	if !ok {
+		return nil, errors.New(errNotALMBinding)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{almBindingClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	almBindingClient sonar.AlmBindingClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ALMBinding)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotALMBinding)
+	}
+
+	binding, err := c.almBindingClient.Get(ctx, cr.Spec.ForProvider.ProjectKey)
+	if err != nil {
+		if errors.Is(err, sonar.ErrAlmBindingNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetBinding)
+	}
+
+	cr.Status.AtProvider.URL = binding.Url
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, binding),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ALMBinding)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotALMBinding)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	err := c.almBindingClient.Set(ctx, cr.Spec.ForProvider.ProjectKey, generateBinding(cr.Spec.ForProvider))
+	return managed.ExternalCreation{}, errors.Wrap(err, errSetBinding)
+}
+
+// Update sets the binding again, since setting a binding replaces the
+// existing one.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ALMBinding)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotALMBinding)
+	}
+
+	err := c.almBindingClient.Set(ctx, cr.Spec.ForProvider.ProjectKey, generateBinding(cr.Spec.ForProvider))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errSetBinding)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ALMBinding)
+	if !ok {
+		return errors.New(errNotALMBinding)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.almBindingClient.Delete(ctx, cr.Spec.ForProvider.ProjectKey)
+	return errors.Wrap(err, errDeleteBinding)
+}
+
+// generateBinding produces the binding to set from the supplied parameters.
+func generateBinding(p v1alpha1.ALMBindingParameters) sonar.AlmBinding {
+	return sonar.AlmBinding{Key: p.ALMSetting, Alm: p.ALM, Repository: p.Repository, Slug: p.Slug, Monorepo: p.Monorepo}
+}
+
+// isUpToDate returns true if the observed binding matches the supplied
+// parameters. The slug is only compared when set.
+func isUpToDate(p v1alpha1.ALMBindingParameters, binding sonar.AlmBinding) bool {
+	if p.ALM != binding.Alm || p.ALMSetting != binding.Key || p.Repository != binding.Repository || p.Monorepo != binding.Monorepo {
+		return false
+	}
+	return p.Slug == "" || p.Slug == binding.Slug
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package almbinding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/almbinding/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied binding, if any, and records every other
+// request it receives.
+type fakeSonar struct {
+	binding  *sonar.AlmBinding
+	requests []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.URL.Path != "/api/alm_settings/get_binding" {
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		return
+	}
+	if f.binding == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Project 'key' is not bound to any DevOps Platform"}]}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(f.binding)
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		want   managed.ExternalObservation
+	}{
+		"NotFound": {
+			reason: "A project that is not bound should be reported as not existing.",
+			sonar:  &fakeSonar{},
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			reason: "A binding matching the parameters should be reported as up to date.",
+			sonar:  &fakeSonar{binding: &sonar.AlmBinding{Key: "github", Alm: "github", Repository: "acme/payments", Url: "https://api.github.com"}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"RepositoryDrift": {
+			reason: "A binding to another repository should be reported as not up to date.",
+			sonar:  &fakeSonar{binding: &sonar.AlmBinding{Key: "github", Alm: "github", Repository: "acme/legacy", Url: "https://api.github.com"}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"MonorepoDrift": {
+			reason: "A binding whose monorepo flag drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{binding: &sonar.AlmBinding{Key: "github", Alm: "github", Repository: "acme/payments", Monorepo: true}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{almBindingClient: sonar.NewAlmBindingClient(sonar.SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1})}
+			got, err := e.Observe(context.Background(), binding())
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := binding()
+	e := external{almBindingClient: sonar.NewAlmBindingClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	cr.Spec.ForProvider.Repository = "acme/payments-api"
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %v", err)
+	}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/alm_settings/set_github_binding", Params: url.Values{"almSetting": {"github"}, "project": {"key"}, "repository": {"acme/payments"}, "monorepo": {"false"}}},
+		{Path: "/api/alm_settings/set_github_binding", Params: url.Values{"almSetting": {"github"}, "project": {"key"}, "repository": {"acme/payments-api"}, "monorepo": {"false"}}},
+		{Path: "/api/alm_settings/delete_binding", Params: url.Values{"project": {"key"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("-want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func binding() *v1alpha1.ALMBinding {
+	cr := &v1alpha1.ALMBinding{}
+	cr.Spec.ForProvider = v1alpha1.ALMBindingParameters{
+		ProjectKey: "key",
+		ALM:        "github",
+		ALMSetting: "github",
+		Repository: "acme/payments",
+	}
+	return cr
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package almsetting
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/almsetting/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotALMSetting = "managed resource is not an ALMSetting custom resource"
+	errTrackPCUsage  = "cannot track ProviderConfig usage"
+	errGetPC         = "cannot get ProviderConfig"
+
+	errGetSecret        = "cannot get ALM setting secret"
+	errGetALMSetting    = "cannot get ALM setting"
+	errCreateALMSetting = "cannot create ALM setting"
+	errUpdateALMSetting = "cannot update ALM setting"
+	errDeleteALMSetting = "cannot delete ALM setting"
+)
+
+// Setup adds a controller that reconciles ALMSetting managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.ALMSettingGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ALMSettingGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewAlmSettingClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ALMSetting{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.AlmSettingClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.ALMSetting)
+	if !ok {
+		return nil, errors.New(errNotALMSetting)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{kube: c.kube, almSettingClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube             client.Client
+	almSettingClient sonar.AlmSettingClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ALMSetting)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotALMSetting)
+	}
+
+	setting, err := c.almSettingClient.GetGitHub(ctx, cr.Spec.ForProvider.Key)
+	if errors.Is(err, sonar.ErrAlmSettingNotFound) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetALMSetting)
+	}
+
+	cr.Status.AtProvider.ALM = sonar.AlmGitHub
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, setting),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ALMSetting)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotALMSetting)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	setting, err := c.gitHubAlmSetting(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	err = c.almSettingClient.CreateGitHub(ctx, setting)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateALMSetting)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ALMSetting)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotALMSetting)
+	}
+
+	// The secrets are never returned by the API, so they are sent again with
+	// every update.
+	setting, err := c.gitHubAlmSetting(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	err = c.almSettingClient.UpdateGitHub(ctx, setting)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateALMSetting)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.ALMSetting)
+	if !ok {
+		return errors.New(errNotALMSetting)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.almSettingClient.Delete(ctx, cr.Spec.ForProvider.Key)
+	return errors.Wrap(err, errDeleteALMSetting)
+}
+
+// gitHubAlmSetting produces the GitHub ALM setting to create or update,
+// reading its secrets from the referenced Kubernetes secrets.
+func (c *external) gitHubAlmSetting(ctx context.Context, p v1alpha1.ALMSettingParameters) (sonar.GitHubAlmSetting, error) {
+	setting := sonar.GitHubAlmSetting{
+		Key:      p.Key,
+		Url:      p.URL,
+		AppId:    p.AppID,
+		ClientId: p.ClientID,
+	}
+
+	var err error
+	if setting.ClientSecret, err = c.secret(ctx, p.ClientSecretSecretRef); err != nil {
+		return sonar.GitHubAlmSetting{}, err
+	}
+	if setting.PrivateKey, err = c.secret(ctx, p.PrivateKeySecretRef); err != nil {
+		return sonar.GitHubAlmSetting{}, err
+	}
+	if ref := p.WebhookSecretSecretRef; ref != nil {
+		if setting.WebhookSecret, err = c.secret(ctx, *ref); err != nil {
+			return sonar.GitHubAlmSetting{}, err
+		}
+	}
+	return setting, nil
+}
+
+// secret returns the value of the referenced secret key.
+func (c *external) secret(ctx context.Context, ref xpv1.SecretKeySelector) (string, error) {
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return "", errors.Wrap(err, errGetSecret)
+	}
+	return string(s.Data[ref.Key]), nil
+}
+
+// isUpToDate returns true if the observed ALM setting matches the desired one.
+// The API never returns the secrets, so they are not compared.
+func isUpToDate(p v1alpha1.ALMSettingParameters, setting sonar.GitHubAlmSetting) bool {
+	return p.URL == setting.Url &&
+		p.AppID == setting.AppId &&
+		p.ClientID == setting.ClientId
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package almsetting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/almsetting/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied GitHub ALM settings and records every other
+// request it receives.
+type fakeSonar struct {
+	github   []sonar.GitHubAlmSetting
+	requests []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.URL.Path != "/api/alm_settings/list_definitions" {
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"github": f.github})
+}
+
+// kube serves the secrets of the GitHub App.
+var kube = &test.MockClient{
+	MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if s, ok := obj.(*corev1.Secret); ok && key.Namespace == "ns" && key.Name == "github-app" {
+			s.Data = map[string][]byte{"clientSecret": []byte("client-secret"), "privateKey": []byte("private-key")}
+		}
+		return nil
+	},
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		want   managed.ExternalObservation
+	}{
+		"NotFound": {
+			reason: "A missing ALM setting should be reported as not existing.",
+			sonar:  &fakeSonar{github: []sonar.GitHubAlmSetting{{Key: "other", Url: "https://api.github.com", AppId: "123456", ClientId: "Iv1.abc"}}},
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			reason: "An ALM setting matching the parameters should be reported as up to date.",
+			sonar:  &fakeSonar{github: []sonar.GitHubAlmSetting{{Key: "github", Url: "https://api.github.com", AppId: "123456", ClientId: "Iv1.abc"}}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"URLDrift": {
+			reason: "An ALM setting whose URL drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{github: []sonar.GitHubAlmSetting{{Key: "github", Url: "https://github.example.org/api/v3", AppId: "123456", ClientId: "Iv1.abc"}}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"AppIDDrift": {
+			reason: "An ALM setting whose app id drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{github: []sonar.GitHubAlmSetting{{Key: "github", Url: "https://api.github.com", AppId: "654321", ClientId: "Iv1.abc"}}},
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{kube: kube, almSettingClient: sonar.NewAlmSettingClient(sonar.SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1})}
+			got, err := e.Observe(context.Background(), setting())
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := setting()
+	e := external{kube: kube, almSettingClient: sonar.NewAlmSettingClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	cr.Spec.ForProvider.AppID = "654321"
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %v", err)
+	}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/alm_settings/create_github", Params: url.Values{"key": {"github"}, "url": {"https://api.github.com"}, "appId": {"123456"}, "clientId": {"Iv1.abc"}, "clientSecret": {"client-secret"}, "privateKey": {"private-key"}}},
+		{Path: "/api/alm_settings/update_github", Params: url.Values{"key": {"github"}, "url": {"https://api.github.com"}, "appId": {"654321"}, "clientId": {"Iv1.abc"}, "clientSecret": {"client-secret"}, "privateKey": {"private-key"}}},
+		{Path: "/api/alm_settings/delete", Params: url.Values{"key": {"github"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("-want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func setting() *v1alpha1.ALMSetting {
+	cr := &v1alpha1.ALMSetting{}
+	cr.Spec.ForProvider = v1alpha1.ALMSettingParameters{
+		Key:                   "github",
+		URL:                   "https://api.github.com",
+		AppID:                 "123456",
+		ClientID:              "Iv1.abc",
+		ClientSecretSecretRef: xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "ns", Name: "github-app"}, Key: "clientSecret"},
+		PrivateKeySecretRef:   xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "ns", Name: "github-app"}, Key: "privateKey"},
+	}
+	return cr
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/application/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied application, if any, and records every other
+// request it receives.
+type fakeSonar struct {
+	application *sonar.Application
+	requests    []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.URL.Path != "/api/applications/show" {
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		return
+	}
+	if f.application == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Application key 'payments' not found"}]}`))
+		return
+	}
+	projects := make([]map[string]string, 0, len(f.application.Projects))
+	for _, p := range f.application.Projects {
+		projects = append(projects, map[string]string{"key": p})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"application": map[string]any{
+		"key":         f.application.Key,
+		"name":        f.application.Name,
+		"description": f.application.Description,
+		"projects":    projects,
+	}})
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.Application
+		want   managed.ExternalObservation
+	}{
+		"NotFound": {
+			reason: "An application that does not exist should be reported as such.",
+			sonar:  &fakeSonar{},
+			mg:     application(),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			reason: "An application with the desired projects, in any order, should be reported as up to date.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Payments", Projects: []string{"b", "a"}}},
+			mg:     application(withProjects("a", "b")),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"UnmanagedProjects": {
+			reason: "Projects should be left unmanaged when none are desired.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Payments", Projects: []string{"a"}}},
+			mg:     application(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"ProjectDrift": {
+			reason: "An application whose projects drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Payments", Projects: []string{"a", "c"}}},
+			mg:     application(withProjects("a", "b")),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"NameDrift": {
+			reason: "An application renamed outside of Crossplane should be reported as not up to date.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Legacy"}},
+			mg:     application(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{applicationClient: sonar.NewApplicationClient(sonar.SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := application(withProjects("a"))
+	e := external{applicationClient: sonar.NewApplicationClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/applications/create", Params: url.Values{"key": {"payments"}, "name": {"Payments"}, "visibility": {"private"}}},
+		{Path: "/api/applications/add_project", Params: url.Values{"application": {"payments"}, "project": {"a"}}},
+		{Path: "/api/applications/delete", Params: url.Values{"application": {"payments"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("e.Create(...), e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.Application
+		want   []request
+	}{
+		"Rename": {
+			reason: "An application renamed outside of Crossplane should be renamed back.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Legacy"}},
+			mg:     application(),
+			want: []request{
+				{Path: "/api/applications/update", Params: url.Values{"application": {"payments"}, "name": {"Payments"}, "description": {""}}},
+			},
+		},
+		"ProjectDrift": {
+			reason: "Missing projects should be added and undesired ones removed.",
+			sonar:  &fakeSonar{application: &sonar.Application{Key: "payments", Name: "Payments", Projects: []string{"a", "c"}}},
+			mg:     application(withProjects("a", "b")),
+			want: []request{
+				{Path: "/api/applications/remove_project", Params: url.Values{"application": {"payments"}, "project": {"c"}}},
+				{Path: "/api/applications/add_project", Params: url.Values{"application": {"payments"}, "project": {"b"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{applicationClient: sonar.NewApplicationClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.sonar.requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type applicationModifier func(*v1alpha1.Application)
+
+func withProjects(projects ...string) applicationModifier {
+	return func(cr *v1alpha1.Application) { cr.Spec.ForProvider.Projects = projects }
+}
+
+func application(m ...applicationModifier) *v1alpha1.Application {
+	cr := &v1alpha1.Application{}
+	cr.Spec.ForProvider = v1alpha1.ApplicationParameters{
+		Key:        "payments",
+		Name:       "Payments",
+		Visibility: "private",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
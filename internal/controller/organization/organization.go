@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/organization/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotOrganization = "managed resource is not an Organization custom resource"
+	errTrackPCUsage    = "cannot track ProviderConfig usage"
+	errGetPC           = "cannot get ProviderConfig"
+
+	errGetOrganization    = "cannot get organization"
+	errCreateOrganization = "organizations cannot be created, the organization must already exist"
+	errUpdateOrganization = "cannot update organization"
+)
+
+// Setup adds a controller that reconciles Organization managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.OrganizationGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.OrganizationGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewOrganizationClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Organization{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.OrganizationClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Organization)
+	if !ok {
+		return nil, errors.New(errNotOrganization)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{organizationClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	organizationClient sonar.OrganizationClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Organization)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotOrganization)
+	}
+
+	// The organization is never deleted, deleting an Organization only
+	// stops managing it.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	organization, err := c.organizationClient.Get(ctx, cr.Spec.ForProvider.Key)
+	if errors.Is(err, sonar.ErrOrganizationNotFound) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetOrganization)
+	}
+
+	cr.Status.AtProvider.Name = organization.Name
+	cr.Status.AtProvider.ProjectVisibility = organization.ProjectVisibility
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, organization),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if _, ok := mg.(*v1alpha1.Organization); !ok {
+		return managed.ExternalCreation{}, errors.New(errNotOrganization)
+	}
+
+	return managed.ExternalCreation{}, errors.New(errCreateOrganization)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Organization)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotOrganization)
+	}
+
+	if v := cr.Spec.ForProvider.ProjectVisibility; v != "" {
+		if err := c.organizationClient.SetDefaultVisibility(ctx, cr.Spec.ForProvider.Key, v); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateOrganization)
+		}
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Organization)
+	if !ok {
+		return errors.New(errNotOrganization)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	return nil
+}
+
+// isUpToDate returns true if the observed organization matches the supplied
+// parameters. Empty parameters are unmanaged and always match.
+func isUpToDate(p v1alpha1.OrganizationParameters, organization sonar.Organization) bool {
+	return p.ProjectVisibility == "" || p.ProjectVisibility == organization.ProjectVisibility
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/organization/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		handler http.HandlerFunc
+		mg      resource.Managed
+	}
+
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	observed := organizationResponse("public")
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "A missing organization should be reported as not existing.",
+			args: args{
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				},
+				mg: organization(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"Unmanaged": {
+			reason: "An organization without a desired visibility should be reported as up to date.",
+			args: args{
+				handler: observed,
+				mg:      organization(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"VisibilityUpToDate": {
+			reason: "An organization with the desired default visibility should be reported as up to date.",
+			args: args{
+				handler: observed,
+				mg:      organization(withProjectVisibility("public")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"VisibilityDrift": {
+			reason: "An organization with another default visibility should be reported as not up to date.",
+			args: args{
+				handler: observed,
+				mg:      organization(withProjectVisibility("private")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"Deleted": {
+			reason: "A deleted Organization should be reported as not existing, since the organization is never deleted.",
+			args: args{
+				handler: observed,
+				mg:      organization(withDeletionTimestamp()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.args.handler)
+			defer srv.Close()
+
+			e := external{organizationClient: sonar.NewOrganizationClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Organization
+		want   url.Values
+	}{
+		"SetDefaultVisibility": {
+			reason: "Update should set the desired default project visibility.",
+			mg:     organization(withProjectVisibility("private")),
+			want:   url.Values{"organization": {"gbsandbox"}, "projectVisibility": {"private"}},
+		},
+		"Unmanaged": {
+			reason: "Update should not change an unmanaged default project visibility.",
+			mg:     organization(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				got = r.Form
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			e := &external{organizationClient: sonar.NewOrganizationClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want params, +got params:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type organizationModifier func(*v1alpha1.Organization)
+
+func withProjectVisibility(visibility string) organizationModifier {
+	return func(cr *v1alpha1.Organization) { cr.Spec.ForProvider.ProjectVisibility = visibility }
+}
+
+func withDeletionTimestamp() organizationModifier {
+	return func(cr *v1alpha1.Organization) {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+	}
+}
+
+func organization(m ...organizationModifier) *v1alpha1.Organization {
+	cr := &v1alpha1.Organization{}
+	cr.Spec.ForProvider = v1alpha1.OrganizationParameters{Key: "gbsandbox"}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// organizationResponse returns a handler that answers every request with an
+// organization whose projects default to the supplied visibility.
+func organizationResponse(visibility string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"organization":{"name":"GB Sandbox","projectVisibility":"` + visibility + `"}}`))
+	}
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissiontemplate
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/permissiontemplate/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotPermissionTemplate = "managed resource is not a PermissionTemplate custom resource"
+	errTrackPCUsage          = "cannot track ProviderConfig usage"
+	errGetPC                 = "cannot get ProviderConfig"
+
+	errGetPermissionTemplate    = "cannot get permission template"
+	errCreatePermissionTemplate = "cannot create permission template"
+	errUpdatePermissionTemplate = "cannot update permission template"
+	errDeletePermissionTemplate = "cannot delete permission template"
+	errAddGroup                 = "cannot grant permission to group"
+	errRemoveGroup              = "cannot revoke permission from group"
+)
+
+// Setup adds a controller that reconciles PermissionTemplate managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.PermissionTemplateGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.PermissionTemplateGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewPermissionTemplateClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.PermissionTemplate{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.PermissionTemplateClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.PermissionTemplate)
+	if !ok {
+		return nil, errors.New(errNotPermissionTemplate)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{permissionTemplateClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	permissionTemplateClient sonar.PermissionTemplateClient
+}
+
+// A grant is a single permission granted to a group.
+type grant struct {
+	group      string
+	permission string
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.PermissionTemplate)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPermissionTemplate)
+	}
+
+	p := cr.Spec.ForProvider
+
+	template, err := c.permissionTemplateClient.GetByName(ctx, p.Organization, p.Name)
+	if err != nil {
+		if errors.Is(err, sonar.ErrPermissionTemplateNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPermissionTemplate)
+	}
+
+	cr.Status.AtProvider.ID = template.Id
+	cr.SetConditions(xpv1.Available())
+
+	if !isUpToDate(p, template) {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+	}
+
+	groups, err := c.permissionTemplateClient.Groups(ctx, p.Organization, template.Id)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPermissionTemplate)
+	}
+	toAdd, toRemove := diffGrants(p.GroupPermissions, groups)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(toAdd) == 0 && len(toRemove) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.PermissionTemplate)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPermissionTemplate)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	p := cr.Spec.ForProvider
+	template, err := c.permissionTemplateClient.Create(ctx, p.Organization, generateTemplate(p))
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePermissionTemplate)
+	}
+	cr.Status.AtProvider.ID = template.Id
+
+	// Anything that fails here is retried by the next Update.
+	toAdd, _ := diffGrants(p.GroupPermissions, nil)
+	for _, g := range toAdd {
+		if err := c.permissionTemplateClient.AddGroup(ctx, p.Organization, template.Id, g.group, g.permission); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAddGroup)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.PermissionTemplate)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotPermissionTemplate)
+	}
+
+	p := cr.Spec.ForProvider
+
+	template, err := c.permissionTemplateClient.GetByName(ctx, p.Organization, p.Name)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePermissionTemplate)
+	}
+
+	if !isUpToDate(p, template) {
+		desired := generateTemplate(p)
+		desired.Id = template.Id
+		if err := c.permissionTemplateClient.Update(ctx, p.Organization, desired); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePermissionTemplate)
+		}
+	}
+
+	groups, err := c.permissionTemplateClient.Groups(ctx, p.Organization, template.Id)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePermissionTemplate)
+	}
+
+	toAdd, toRemove := diffGrants(p.GroupPermissions, groups)
+	for _, g := range toRemove {
+		if err := c.permissionTemplateClient.RemoveGroup(ctx, p.Organization, template.Id, g.group, g.permission); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRemoveGroup)
+		}
+	}
+	for _, g := range toAdd {
+		if err := c.permissionTemplateClient.AddGroup(ctx, p.Organization, template.Id, g.group, g.permission); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAddGroup)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.PermissionTemplate)
+	if !ok {
+		return errors.New(errNotPermissionTemplate)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	p := cr.Spec.ForProvider
+
+	template, err := c.permissionTemplateClient.GetByName(ctx, p.Organization, p.Name)
+	if errors.Is(err, sonar.ErrPermissionTemplateNotFound) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errDeletePermissionTemplate)
+	}
+
+	err = c.permissionTemplateClient.Delete(ctx, p.Organization, template.Id)
+	return errors.Wrap(err, errDeletePermissionTemplate)
+}
+
+// generateTemplate produces the permission template to create or update from
+// the supplied parameters.
+func generateTemplate(p v1alpha1.PermissionTemplateParameters) sonar.PermissionTemplate {
+	return sonar.PermissionTemplate{Name: p.Name, Description: p.Description, ProjectKeyPattern: p.ProjectKeyPattern}
+}
+
+// isUpToDate returns true if the observed permission template matches the
+// supplied parameters. Optional fields are only compared when set.
+func isUpToDate(p v1alpha1.PermissionTemplateParameters, template sonar.PermissionTemplate) bool {
+	if p.Description != "" && p.Description != template.Description {
+		return false
+	}
+	return p.ProjectKeyPattern == "" || p.ProjectKeyPattern == template.ProjectKeyPattern
+}
+
+// diffGrants compares the desired group permissions of a permission template
+// with the observed ones. Grants are left unmanaged when none are desired.
+func diffGrants(desired []v1alpha1.GroupPermission, observed []sonar.TemplateGroup) (toAdd, toRemove []grant) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	existing := map[grant]bool{}
+	for _, g := range observed {
+		for _, permission := range g.Permissions {
+			existing[grant{group: g.Name, permission: permission}] = true
+		}
+	}
+
+	wanted := map[grant]bool{}
+	for _, g := range desired {
+		for _, permission := range g.Permissions {
+			d := grant{group: g.Group, permission: permission}
+			if wanted[d] {
+				continue
+			}
+			wanted[d] = true
+			if !existing[d] {
+				toAdd = append(toAdd, d)
+			}
+		}
+	}
+
+	for _, g := range observed {
+		for _, permission := range g.Permissions {
+			if o := (grant{group: g.Name, permission: permission}); !wanted[o] {
+				toRemove = append(toRemove, o)
+			}
+		}
+	}
+
+	return toAdd, toRemove
+}
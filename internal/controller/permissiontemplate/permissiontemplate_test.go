@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissiontemplate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/permissiontemplate/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied permission templates and group grants, and
+// records every other request it receives.
+type fakeSonar struct {
+	templates []sonar.PermissionTemplate
+	groups    []sonar.TemplateGroup
+	requests  []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	switch r.URL.Path {
+	case "/api/permissions/search_templates":
+		_ = json.NewEncoder(w).Encode(map[string][]sonar.PermissionTemplate{"permissionTemplates": f.templates})
+	case "/api/permissions/template_groups":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"paging": sonar.SonarPaging{PageIndex: 1, PageSize: 100, Total: len(f.groups)},
+			"groups": f.groups,
+		})
+	case "/api/permissions/create_template":
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		_ = json.NewEncoder(w).Encode(map[string]sonar.PermissionTemplate{"permissionTemplate": {Id: "AXT1", Name: r.Form.Get("name")}})
+	default:
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o  managed.ExternalObservation
+		id string
+	}
+
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.PermissionTemplate
+		want   want
+	}{
+		"NotFound": {
+			reason: "A permission template whose name only matches partially should be reported as not existing.",
+			sonar:  &fakeSonar{templates: []sonar.PermissionTemplate{{Id: "AXT2", Name: "payments-legacy"}}},
+			mg:     template(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A permission template granting the desired permissions should be reported as up to date and have its id recorded.",
+			sonar: &fakeSonar{
+				templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: "payments-.*"}},
+				groups:    []sonar.TemplateGroup{{Name: "developers", Permissions: []string{"user", "codeviewer"}}},
+			},
+			mg: template(withGrant("developers", "codeviewer", "user")),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				id: "AXT1",
+			},
+		},
+		"UnmanagedGrants": {
+			reason: "Grants should be left unmanaged when none are desired.",
+			sonar: &fakeSonar{
+				templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: "payments-.*"}},
+				groups:    []sonar.TemplateGroup{{Name: "developers", Permissions: []string{"user"}}},
+			},
+			mg: template(),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				id: "AXT1",
+			},
+		},
+		"PatternDrift": {
+			reason: "A permission template whose project key pattern drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: ".*"}}},
+			mg:     template(),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				id: "AXT1",
+			},
+		},
+		"GrantDrift": {
+			reason: "A permission template whose grants drifted should be reported as not up to date.",
+			sonar: &fakeSonar{
+				templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: "payments-.*"}},
+				groups:    []sonar.TemplateGroup{{Name: "developers", Permissions: []string{"user", "admin"}}},
+			},
+			mg: template(withGrant("developers", "user")),
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				id: "AXT1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{permissionTemplateClient: sonar.NewPermissionTemplateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, tc.mg.Status.AtProvider.ID); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want id, +got id:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := template(withGrant("developers", "user"))
+	e := external{permissionTemplateClient: sonar.NewPermissionTemplateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	f.templates = []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments"}}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/permissions/create_template", Params: url.Values{"organization": {"org"}, "name": {"payments"}, "projectKeyPattern": {"payments-.*"}}},
+		{Path: "/api/permissions/add_group_to_template", Params: url.Values{"organization": {"org"}, "templateId": {"AXT1"}, "groupName": {"developers"}, "permission": {"user"}}},
+		{Path: "/api/permissions/delete_template", Params: url.Values{"organization": {"org"}, "templateId": {"AXT1"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("e.Create(...), e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("AXT1", cr.Status.AtProvider.ID); diff != "" {
+		t.Errorf("e.Create(...): -want id, +got id:\n%s\n", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.PermissionTemplate
+		want   []request
+	}{
+		"Pattern": {
+			reason: "A project key pattern that drifted should be updated by id.",
+			sonar:  &fakeSonar{templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: ".*"}}},
+			mg:     template(),
+			want: []request{
+				{Path: "/api/permissions/update_template", Params: url.Values{"id": {"AXT1"}, "name": {"payments"}, "projectKeyPattern": {"payments-.*"}}},
+			},
+		},
+		"AddGroupPermission": {
+			reason: "A missing group permission should be granted.",
+			sonar: &fakeSonar{
+				templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: "payments-.*"}},
+				groups:    []sonar.TemplateGroup{{Name: "developers", Permissions: []string{"user"}}},
+			},
+			mg: template(withGrant("developers", "user"), withGrant("leads", "admin")),
+			want: []request{
+				{Path: "/api/permissions/add_group_to_template", Params: url.Values{"organization": {"org"}, "templateId": {"AXT1"}, "groupName": {"leads"}, "permission": {"admin"}}},
+			},
+		},
+		"RemoveGroupPermission": {
+			reason: "An undesired group permission should be revoked.",
+			sonar: &fakeSonar{
+				templates: []sonar.PermissionTemplate{{Id: "AXT1", Name: "payments", ProjectKeyPattern: "payments-.*"}},
+				groups:    []sonar.TemplateGroup{{Name: "developers", Permissions: []string{"user", "admin"}}},
+			},
+			mg: template(withGrant("developers", "user")),
+			want: []request{
+				{Path: "/api/permissions/remove_group_from_template", Params: url.Values{"organization": {"org"}, "templateId": {"AXT1"}, "groupName": {"developers"}, "permission": {"admin"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{permissionTemplateClient: sonar.NewPermissionTemplateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.sonar.requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type templateModifier func(*v1alpha1.PermissionTemplate)
+
+func withGrant(group string, permissions ...string) templateModifier {
+	return func(cr *v1alpha1.PermissionTemplate) {
+		cr.Spec.ForProvider.GroupPermissions = append(cr.Spec.ForProvider.GroupPermissions, v1alpha1.GroupPermission{Group: group, Permissions: permissions})
+	}
+}
+
+func template(m ...templateModifier) *v1alpha1.PermissionTemplate {
+	cr := &v1alpha1.PermissionTemplate{}
+	cr.Spec.ForProvider = v1alpha1.PermissionTemplateParameters{
+		Organization:      "org",
+		Name:              "payments",
+		ProjectKeyPattern: "payments-.*",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
@@ -0,0 +1,66 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package poll spreads the polls of managed resources over time.
+package poll
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Jitter is the largest fraction of the poll interval by which a poll is moved
+// earlier or later. Resources created together, for example at startup, would
+// otherwise keep observing their external resources in the same burst.
+const Jitter = 0.1
+
+// A Reconciler requeues the resources its wrapped reconciler polls after a
+// jittered poll interval.
+type Reconciler struct {
+	inner    reconcile.Reconciler
+	interval time.Duration
+	jitter   func(spread time.Duration) time.Duration
+}
+
+// NewReconciler wraps a managed resource reconciler polling every interval.
+func NewReconciler(r reconcile.Reconciler, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		inner:    r,
+		interval: interval,
+		jitter: func(spread time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(2*spread)+1)) - spread //nolint:gosec // Jitter needs no cryptographic randomness.
+		},
+	}
+}
+
+// Reconcile the request with the wrapped reconciler. A request requeued after
+// exactly the poll interval is requeued after a jittered interval instead, and
+// any other result is returned as is.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	res, err := r.inner.Reconcile(ctx, req)
+	if r.interval <= 0 || res.RequeueAfter != r.interval {
+		return res, err
+	}
+
+	if spread := time.Duration(float64(r.interval) * Jitter); spread > 0 {
+		res.RequeueAfter += r.jitter(spread)
+	}
+	return res, err
+}
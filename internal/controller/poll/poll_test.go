@@ -0,0 +1,96 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package poll
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestReconcile(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		interval time.Duration
+		result   ctrl.Result
+		want     ctrl.Result
+	}{
+		"Poll": {
+			reason:   "A resource polled after the configured interval should be requeued after a jittered interval.",
+			interval: time.Minute,
+			result:   ctrl.Result{RequeueAfter: time.Minute},
+			want:     ctrl.Result{RequeueAfter: time.Minute + 6*time.Second},
+		},
+		"ShortWait": {
+			reason:   "A resource requeued after anything but the poll interval should be left alone.",
+			interval: time.Minute,
+			result:   ctrl.Result{RequeueAfter: 30 * time.Second},
+			want:     ctrl.Result{RequeueAfter: 30 * time.Second},
+		},
+		"Requeue": {
+			reason:   "A resource requeued with backoff should be left alone.",
+			interval: time.Minute,
+			result:   ctrl.Result{Requeue: true},
+			want:     ctrl.Result{Requeue: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return tc.result, nil
+			})
+			r := NewReconciler(inner, tc.interval)
+			// Always move the poll as late as allowed.
+			r.jitter = func(spread time.Duration) time.Duration { return spread }
+
+			got, err := r.Reconcile(context.Background(), ctrl.Request{})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	})
+	r := NewReconciler(inner, time.Minute)
+
+	for i := 0; i < 100; i++ {
+		got, _ := r.Reconcile(context.Background(), ctrl.Request{})
+		if got.RequeueAfter < 54*time.Second || got.RequeueAfter > 66*time.Second {
+			t.Fatalf("r.Reconcile(...): want requeue within 10%% of the poll interval, got %s", got.RequeueAfter)
+		}
+	}
+}
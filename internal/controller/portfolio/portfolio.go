@@ -0,0 +1,264 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/portfolio/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotPortfolio = "managed resource is not a Portfolio custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+
+	errGetPortfolio    = "cannot get portfolio"
+	errCreatePortfolio = "cannot create portfolio"
+	errUpdatePortfolio = "cannot update portfolio"
+	errDeletePortfolio = "cannot delete portfolio"
+	errAddProject      = "cannot add project to portfolio"
+	errRemoveProject   = "cannot remove project from portfolio"
+)
+
+// Setup adds a controller that reconciles Portfolio managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.PortfolioGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.PortfolioGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewPortfolioClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Portfolio{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.PortfolioClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Portfolio)
+	if !ok {
+		return nil, errors.New(errNotPortfolio)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{portfolioClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	portfolioClient sonar.PortfolioClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Portfolio)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotPortfolio)
+	}
+
+	p := cr.Spec.ForProvider
+
+	portfolio, err := c.portfolioClient.Get(ctx, p.Key)
+	if err != nil {
+		if errors.Is(err, sonar.ErrPortfolioNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPortfolio)
+	}
+
+	cr.Status.AtProvider.Visibility = portfolio.Visibility
+	cr.SetConditions(xpv1.Available())
+
+	toAdd, toRemove := diffProjects(p.Projects, portfolio.Projects)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(p, portfolio) && len(toAdd) == 0 && len(toRemove) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Portfolio)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotPortfolio)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	p := cr.Spec.ForProvider
+	portfolio := sonar.Portfolio{Key: p.Key, Name: p.Name, Description: p.Description, Visibility: p.Visibility}
+	if err := c.portfolioClient.Create(ctx, portfolio); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePortfolio)
+	}
+
+	// Anything that fails here is retried by the next Update.
+	for _, project := range p.Projects {
+		if err := c.portfolioClient.AddProject(ctx, p.Key, project); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAddProject)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Portfolio)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotPortfolio)
+	}
+
+	p := cr.Spec.ForProvider
+
+	portfolio, err := c.portfolioClient.Get(ctx, p.Key)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePortfolio)
+	}
+
+	if !isUpToDate(p, portfolio) {
+		if err := c.portfolioClient.Update(ctx, sonar.Portfolio{Key: p.Key, Name: p.Name, Description: p.Description}); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePortfolio)
+		}
+	}
+
+	toAdd, toRemove := diffProjects(p.Projects, portfolio.Projects)
+	for _, project := range toRemove {
+		if err := c.portfolioClient.RemoveProject(ctx, p.Key, project); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRemoveProject)
+		}
+	}
+	for _, project := range toAdd {
+		if err := c.portfolioClient.AddProject(ctx, p.Key, project); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAddProject)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Portfolio)
+	if !ok {
+		return errors.New(errNotPortfolio)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.portfolioClient.Delete(ctx, cr.Spec.ForProvider.Key)
+	return errors.Wrap(err, errDeletePortfolio)
+}
+
+// isUpToDate returns true if the name and description of the observed
+// portfolio match the supplied parameters. The description is only compared
+// when set.
+func isUpToDate(p v1alpha1.PortfolioParameters, portfolio sonar.Portfolio) bool {
+	return p.Name == portfolio.Name && (p.Description == "" || p.Description == portfolio.Description)
+}
+
+// diffProjects compares the desired projects of a portfolio with the observed
+// ones. Projects are left unmanaged when none are desired.
+func diffProjects(desired, observed []string) (toAdd, toRemove []string) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	existing := make(map[string]bool, len(observed))
+	for _, o := range observed {
+		existing[o] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		if wanted[d] {
+			continue
+		}
+		wanted[d] = true
+		if !existing[d] {
+			toAdd = append(toAdd, d)
+		}
+	}
+
+	for _, o := range observed {
+		if !wanted[o] {
+			toRemove = append(toRemove, o)
+		}
+	}
+
+	return toAdd, toRemove
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portfolio
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/portfolio/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied portfolio, if any, and records every other
+// request it receives.
+type fakeSonar struct {
+	portfolio *sonar.Portfolio
+	requests  []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.URL.Path != "/api/views/show" {
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		return
+	}
+	if f.portfolio == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"Portfolio 'payments' not found"}]}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(f.portfolio)
+}
+
+func TestObserve(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.Portfolio
+		want   managed.ExternalObservation
+	}{
+		"NotFound": {
+			reason: "A portfolio that does not exist should be reported as such.",
+			sonar:  &fakeSonar{},
+			mg:     portfolio(),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"UpToDate": {
+			reason: "A portfolio with the desired projects, in any order, should be reported as up to date.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Payments", Projects: []string{"b", "a"}}},
+			mg:     portfolio(withProjects("a", "b")),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"UnmanagedProjects": {
+			reason: "Projects should be left unmanaged when none are desired.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Payments", Projects: []string{"a"}}},
+			mg:     portfolio(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"ProjectDrift": {
+			reason: "A portfolio whose projects drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Payments", Projects: []string{"a", "c"}}},
+			mg:     portfolio(withProjects("a", "b")),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"NameDrift": {
+			reason: "A portfolio renamed outside of Crossplane should be reported as not up to date.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Legacy"}},
+			mg:     portfolio(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{portfolioClient: sonar.NewPortfolioClient(sonar.SonarApiOptions{BaseUrl: srv.URL, MaxAttempts: 1})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := portfolio(withProjects("a"))
+	e := external{portfolioClient: sonar.NewPortfolioClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/views/create", Params: url.Values{"key": {"payments"}, "name": {"Payments"}, "visibility": {"private"}}},
+		{Path: "/api/views/add_project", Params: url.Values{"key": {"payments"}, "project": {"a"}}},
+		{Path: "/api/views/delete", Params: url.Values{"key": {"payments"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("e.Create(...), e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.Portfolio
+		want   []request
+	}{
+		"Rename": {
+			reason: "A portfolio renamed outside of Crossplane should be renamed back.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Legacy"}},
+			mg:     portfolio(),
+			want: []request{
+				{Path: "/api/views/update", Params: url.Values{"key": {"payments"}, "name": {"Payments"}, "description": {""}}},
+			},
+		},
+		"ProjectDrift": {
+			reason: "Missing projects should be added and undesired ones removed.",
+			sonar:  &fakeSonar{portfolio: &sonar.Portfolio{Key: "payments", Name: "Payments", Projects: []string{"a", "c"}}},
+			mg:     portfolio(withProjects("a", "b")),
+			want: []request{
+				{Path: "/api/views/remove_project", Params: url.Values{"key": {"payments"}, "project": {"c"}}},
+				{Path: "/api/views/add_project", Params: url.Values{"key": {"payments"}, "project": {"b"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{portfolioClient: sonar.NewPortfolioClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.sonar.requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type portfolioModifier func(*v1alpha1.Portfolio)
+
+func withProjects(projects ...string) portfolioModifier {
+	return func(cr *v1alpha1.Portfolio) { cr.Spec.ForProvider.Projects = projects }
+}
+
+func portfolio(m ...portfolioModifier) *v1alpha1.Portfolio {
+	cr := &v1alpha1.Portfolio{}
+	cr.Spec.ForProvider = v1alpha1.PortfolioParameters{
+		Key:        "payments",
+		Name:       "Payments",
+		Visibility: "private",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
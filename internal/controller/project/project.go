@@ -18,17 +18,25 @@ package project
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,22 +45,100 @@ import (
 	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
 	"github.com/crossplane/provider-sonar/internal/clients/sonar"
 	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
 )
 
 const (
 	errNotProject   = "managed resource is not a Project custom resource"
 	errTrackPCUsage = "cannot track ProviderConfig usage"
 	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
 
-	errNewClient = "cannot create new Service"
+	errEmptyKey          = "project key must not be empty: set it, or the crossplane.io/external-name annotation"
+	errMoveProject       = "cannot move project from organization %q to %q: the organization of a project cannot be changed"
+	errGetProject        = "cannot get project"
+	errCreateProject     = "cannot create project"
+	errProjectLimit      = "cannot create project: the plan of the organization allows no more private projects; make the project public, delete another private project, or upgrade the plan"
+	errUpdateProject     = "cannot update project"
+	errDeleteProject     = "cannot delete project"
+	errProtectionDays    = "invalid " + v1alpha1.AnnotationKeyDeletionProtectionDays + " annotation: want a positive number of days"
+	errDeletionProtected = "project was analyzed %s, within the %d days of its " + v1alpha1.AnnotationKeyDeletionProtectionDays + " annotation: remove the annotation to delete it"
+	errDeletedExternally = "project was deleted outside of Crossplane and is not recreated, since the ProviderConfig disables recreation: restore it, or delete this resource"
+	errNotYetVisible     = "project was created recently and is not visible yet: waiting for it rather than creating it again"
+	errProjectURL        = "cannot build project URL"
+
+	errGetNewCodePeriod = "cannot get new code period of project"
+	errSetNewCodePeriod = "cannot set new code period of project"
+
+	errGetMeasures    = "cannot get measures of project"
+	errSearchAnalyses = "cannot search analyses of project"
+
+	errCountIssues   = "cannot count issues of project"
+	errCountHotspots = "cannot count security hotspots of project"
+
+	errGetMainBranch    = "cannot get main branch of project"
+	errRenameMainBranch = "cannot rename main branch of project"
+
+	errListBranches    = "cannot list branches of project"
+	errProtectBranch   = "cannot protect branch of project from automatic deletion"
+	errUnprotectBranch = "cannot lift automatic deletion protection of branch of project"
+
+	errGetBadgeToken   = "cannot get badge token of project"
+	errRenewBadgeToken = "cannot renew badge token of project"
+
+	errSearchAnalysisTokens  = "cannot search analysis tokens"
+	errGenerateAnalysisToken = "cannot generate analysis token of project"
+	errRevokeAnalysisToken   = "cannot revoke analysis token of project"
+
+	errSearchLinks = "cannot search links of project"
+	errCreateLink  = "cannot create link of project"
+	errDeleteLink  = "cannot delete link of project"
+
+	errGetTemplateProject     = "cannot get template project"
+	errGetTemplateQualityGate = "cannot get quality gate of template project"
+	errSearchPermTemplates    = "cannot search permission templates"
+	errRollbackProject        = "cannot delete project after its template project could not be applied"
+	errApplyPermTemplate      = "cannot apply permission template of template project"
+
+	errGetQualityGate    = "cannot get quality gate of project"
+	errSelectQualityGate = "cannot select quality gate of project"
+
+	errSearchQualityProfiles = "cannot search quality profiles of project"
+	errAddQualityProfile     = "cannot associate quality profile with project"
+	errRemoveQualityProfile  = "cannot remove quality profile association of project"
+
+	errGetExclusions   = "cannot get exclusions of project"
+	errSetExclusions   = "cannot set exclusions of project"
+	errResetExclusions = "cannot reset exclusions of project"
+)
+
+// Keys of the settings that hold the exclusions of a Project.
+const (
+	settingExclusions         = "sonar.exclusions"
+	settingCoverageExclusions = "sonar.coverage.exclusions"
 )
 
+// Connection detail keys published for a Project.
+const (
+	keyProjectKey   = "projectKey"
+	keyProjectURL   = "projectUrl"
+	keyOrganization = "organization"
+	keyBadgeToken   = "badgeToken"
+
+	keyAnalysisToken = "analysisToken"
+)
+
+// creationGracePeriod is how long after its creation a project that is not
+// found is waited for rather than created again, since SonarCloud is
+// eventually consistent.
+const creationGracePeriod = 30 * time.Second
+
 // Setup adds a controller that reconciles Project managed resources.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := managed.ControllerName(v1alpha1.ProjectGroupKind)
+	logger := o.Logger.WithValues("controller", name)
 
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
 	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
@@ -62,8 +148,14 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		managed.WithExternalConnecter(&connector{
 			kube:        mgr.GetClient(),
 			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newClientFn: sonar.NewProjectClient}),
-		managed.WithLogger(o.Logger.WithValues("controller", name)),
+			logger:      logger,
+			newClientFn: sonar.NewProjectService}),
+		// The external name is the project key, not the name of the resource
+		// that the default initializer would set.
+		managed.WithInitializers(),
+		managed.WithCreationGracePeriod(creationGracePeriod),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		managed.WithConnectionPublishers(cps...))
 
@@ -71,7 +163,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha1.Project{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -79,7 +171,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube        client.Client
 	usage       resource.Tracker
-	newClientFn func(options sonar.SonarApiOptions) sonar.ProjectClient
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.ProjectService
 }
 
 // Connect typically produces an ExternalClient by:
@@ -102,27 +195,75 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+		return nil, err
 	}
-	fmt.Println(string(data))
 
-	svc := c.newClientFn(sonar.SonarApiOptions{Key: string(data)})
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
-	}
-
-	return &external{projectClient: svc}, nil
+	return &external{
+		kube:                     c.kube,
+		projectClient:            c.newClientFn(options),
+		qualityGateClient:        sonar.NewQualityGateClient(options),
+		measuresClient:           sonar.NewMeasuresClient(options),
+		analysesClient:           sonar.NewProjectAnalysesClient(options),
+		issuesClient:             sonar.NewIssuesClient(options),
+		branchClient:             sonar.NewProjectBranchClient(options),
+		userTokenClient:          sonar.NewUserTokenClient(options),
+		linkClient:               sonar.NewProjectLinkClient(options),
+		profileClient:            sonar.NewQualityProfileClient(options),
+		settingsClient:           sonar.NewSettingsClient(options),
+		permissionTemplateClient: sonar.NewPermissionTemplateClient(options),
+		organization:             pc.Spec.Organization,
+		disableRecreation:        pc.Spec.DisableRecreation,
+		defaultTags:              pc.Spec.DefaultTags,
+		logger:                   c.logger,
+	}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
+	// kube reads the connection secret of a Project.
+	kube client.Client
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
-	projectClient sonar.ProjectClient
+	projectClient            sonar.ProjectService
+	qualityGateClient        sonar.QualityGateClient
+	measuresClient           sonar.MeasuresClient
+	analysesClient           sonar.ProjectAnalysesClient
+	issuesClient             sonar.IssuesClient
+	branchClient             sonar.ProjectBranchClient
+	userTokenClient          sonar.UserTokenClient
+	linkClient               sonar.ProjectLinkClient
+	profileClient            sonar.QualityProfileClient
+	settingsClient           sonar.SettingsClient
+	permissionTemplateClient sonar.PermissionTemplateClient
+	// organization is the default of the ProviderConfig, used when a
+	// Project does not set one.
+	organization string
+	// disableRecreation reports a project that was deleted outside of
+	// Crossplane as unavailable rather than creating it again.
+	disableRecreation bool
+	// defaultTags of the ProviderConfig, carried by every project.
+	defaultTags []string
+	logger      logging.Logger
+}
+
+// organizationOf returns the organization of a Project, or the default
+// organization of its ProviderConfig when it sets none.
+func (c *external) organizationOf(cr *v1alpha1.Project) string {
+	if cr.Spec.ForProvider.Organization != "" {
+		return cr.Spec.ForProvider.Organization
+	}
+	return c.organization
+}
+
+// recentlyCreated reports whether the project was created within the creation
+// grace period, during which the API may not return it yet. The reconciler
+// waits for such a project rather than creating it when it is not observed,
+// but Update must not create it again either.
+func recentlyCreated(cr *v1alpha1.Project) bool {
+	return meta.ExternalCreateSucceededDuring(cr, creationGracePeriod)
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -131,34 +272,103 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotProject)
 	}
 
-	// These fmt statements should be removed in the real implementation.
-	fmt.Printf("Observing: %+v", cr)
+	keyLateInitialized := lateInitializeKey(cr)
+
+	// An empty key would match arbitrary projects, which must never be
+	// reported as this one.
+	if cr.Spec.ForProvider.Key == "" {
+		return managed.ExternalObservation{}, errors.New(errEmptyKey)
+	}
+
+	// A project cannot move to another organization. Looking it up in the new
+	// organization would not find it, and create a second project there. A
+	// project being deleted is still deleted, since its key identifies it.
+	if o := cr.Status.AtProvider.Organization; o != "" && o != c.organizationOf(cr) && !meta.WasDeleted(cr) {
+		return managed.ExternalObservation{}, errors.Errorf(errMoveProject, o, c.organizationOf(cr))
+	}
+
+	c.logger.Debug("Observing project", "organization", cr.Spec.ForProvider.Organization, "key", cr.Spec.ForProvider.Key)
 
 	project, err := c.projectClient.GetByProjectKey(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Key)
 
 	if err != nil {
+		// Only a project that is known to be absent is reported as such. A
+		// permission error must not trigger a create.
 		if errors.Is(err, sonar.ErrProjectNotFound) {
+			// A project observed before was deleted outside of Crossplane.
+			// It is reported as existing so that it is not created again.
+			// Once its managed resource is deleted, it is reported as gone,
+			// so that the finalizer is removed.
+			if c.disableRecreation && cr.Status.AtProvider.ProjectURL != "" && !meta.WasDeleted(cr) {
+				cr.SetConditions(xpv1.Unavailable().WithMessage(errDeletedExternally))
+				return managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				}, nil
+			}
 			return managed.ExternalObservation{
 				ResourceExists:          false,
 				ResourceLateInitialized: false,
 			}, nil
 		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetProject)
+	}
+
+	u, err := c.projectClient.GetProjectUrl(project.Key)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errProjectURL)
+	}
+	cr.SetConditions(xpv1.Available())
+	cr.Status.AtProvider.LastAnalysisDate = nil
+	if !project.LastAnalysisDate.IsZero() {
+		cr.Status.AtProvider.LastAnalysisDate = &metav1.Time{Time: project.LastAnalysisDate.Time}
+	}
+	cr.Status.AtProvider.Organization = c.organizationOf(cr)
+	cr.Status.AtProvider.Qualifier = project.Qualifier
+	cr.Status.AtProvider.Revision = project.Revision
+	cr.Status.AtProvider.ProjectURL = u
+
+	cr.Status.AtProvider.Measures = nil
+	if len(cr.Spec.ForProvider.Measures) > 0 {
+		measures, err := c.measuresClient.GetMeasures(ctx, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Measures)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetMeasures)
+		}
+		cr.Status.AtProvider.Measures = measures
+	}
+
+	if err := c.observeAnalyses(ctx, cr); err != nil {
 		return managed.ExternalObservation{}, err
 	}
 
-	fmt.Println("\n\nproject.Visibility:" + project.Visibility)
-	fmt.Println("cr.Spec.ForProvider.Visibility:" + cr.Spec.ForProvider.Visibility + "\n\n")
+	if err := c.observeIssueCounts(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	lateInitialized := lateInitialize(&cr.Spec.ForProvider, project) || keyLateInitialized
 
-	if project.Visibility != cr.Spec.ForProvider.Visibility {
-		return managed.ExternalObservation{
-			ResourceExists:   true,
-			ResourceUpToDate: false,
-		}, nil
+	observed, err := c.observeManaged(ctx, cr, project)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.Status.AtProvider.MainBranch = observed.mainBranch
+
+	diff := diffProject(cr, observed, c.defaultTags)
+	if !diff.upToDate() {
+		c.logger.Debug("Project is not up to date", "key", cr.Spec.ForProvider.Key, "fields", diff.fields())
+	}
+	recordManaged(cr, diff)
+
+	cd, err := c.connectionDetails(ctx, cr, c.missingBadgeToken(ctx, cr))
+	if err != nil {
+		return managed.ExternalObservation{}, err
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: true,
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.upToDate(),
+		ResourceLateInitialized: lateInitialized,
+		ConnectionDetails:       cd,
 	}, nil
 
 	// return managed.ExternalObservation{
@@ -184,19 +394,84 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotProject)
 	}
 
-	fmt.Printf("Creating: %+v", cr)
+	c.logger.Debug("Creating project", "organization", cr.Spec.ForProvider.Organization, "key", cr.Spec.ForProvider.Key)
 
-	_, err := c.projectClient.Create(ctx, cr.Spec.ForProvider.Organization, cr.GetObjectMeta().GetName(), cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Visibility)
+	name := cr.Spec.ForProvider.Name
+	if name == "" {
+		name = cr.GetObjectMeta().GetName()
+	}
+
+	// The template project is looked up first, so that a missing one does
+	// not leave a project behind that the template was never applied to.
+	if t := cr.Spec.ForProvider.TemplateProject; t != "" {
+		if _, err := c.projectClient.GetByProjectKey(ctx, c.organizationOf(cr), t); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errGetTemplateProject)
+		}
+	}
 
+	_, created, err := c.projectClient.Create(ctx, cr.Spec.ForProvider.Organization, name, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Visibility, cr.Spec.ForProvider.MainBranch)
+	if errors.Is(err, sonar.ErrProjectLimitReached) {
+		return managed.ExternalCreation{}, errors.Wrap(err, errProjectLimit)
+	}
 	if err != nil {
-		log.Fatal(err)
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateProject)
 	}
+	cr.Status.AtProvider.Organization = c.organizationOf(cr)
+	meta.SetExternalName(cr, cr.Spec.ForProvider.Key)
 
-	return managed.ExternalCreation{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	// A project that already existed is taken over as it is. Neither is the
+	// template applied to it, nor is it deleted when that fails.
+	if cr.Spec.ForProvider.TemplateProject != "" && created {
+		if err := c.applyTemplateProject(ctx, cr); err != nil {
+			// The template is only applied to a new project, so the project
+			// is deleted for the next reconcile to create it again.
+			if derr := c.projectClient.Delete(ctx, cr.Spec.ForProvider.Key); derr != nil {
+				return managed.ExternalCreation{}, kerrors.NewAggregate([]error{err, errors.Wrap(derr, errRollbackProject)})
+			}
+			return managed.ExternalCreation{}, err
+		}
+	}
+
+	// Anything that fails here is retried by the next Update.
+	if cr.Spec.ForProvider.QualityGate != "" {
+		if err := c.qualityGateClient.SelectForProject(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.QualityGate, cr.Spec.ForProvider.Key); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errSelectQualityGate)
+		}
+	}
+
+	if p := cr.Spec.ForProvider.NewCodePeriod; p != nil {
+		if err := c.projectClient.SetNewCodePeriod(ctx, cr.Spec.ForProvider.Key, sonar.NewCodePeriod{Type: p.Type, Value: p.Value}); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errSetNewCodePeriod)
+		}
+	}
+
+	for _, l := range cr.Spec.ForProvider.Links {
+		if _, err := c.linkClient.Create(ctx, cr.Spec.ForProvider.Key, l.Name, l.URL); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errCreateLink)
+		}
+	}
+
+	for language, name := range cr.Spec.ForProvider.ProfileAssociations {
+		if err := c.profileClient.AddProject(ctx, cr.Spec.ForProvider.Organization, language, name, cr.Spec.ForProvider.Key); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAddQualityProfile)
+		}
+	}
+
+	for key, patterns := range exclusions(cr) {
+		if err := c.settingsClient.Set(ctx, cr.Spec.ForProvider.Key, sonar.Setting{Key: key, Values: patterns}); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errSetExclusions)
+		}
+	}
+
+	// The status is not persisted after a create, so the associations and
+	// exclusions are recorded by the next Observe. The badge token rotation
+	// and the analysis token are left to the next Update, which records them.
+	cd, err := c.connectionDetails(ctx, cr, true)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	return managed.ExternalCreation{ConnectionDetails: cd}, nil
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -205,18 +480,152 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotProject)
 	}
 
-	fmt.Printf("Updating: %+v", cr)
+	project, err := c.projectClient.GetByProjectKey(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Key)
+	if errors.Is(err, sonar.ErrProjectNotFound) && !c.disableRecreation {
+		return c.recreate(ctx, cr)
+	}
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateProject)
+	}
+	observed, err := c.observeManaged(ctx, cr, project)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// The key identifies the project and is never changed, only the fields
+	// that drifted are updated.
+	diff := diffProject(cr, observed, c.defaultTags)
+	c.logger.Debug("Updating project", "key", cr.Spec.ForProvider.Key, "fields", diff.fields())
 
-	err := c.projectClient.UpdateVisibility(ctx, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Visibility)
+	// The steps are independent, so a failing one does not stop the others
+	// and every failure is returned. The next reconcile retries only the
+	// fields that still differ. The failures are aggregated with
+	// kerrors.NewAggregate since errors.Join needs Go 1.20, newer than the Go
+	// version of the module.
+	var errs []error
+	if diff.name {
+		if err := c.projectClient.UpdateName(ctx, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Name); err != nil {
+			errs = append(errs, errors.Wrap(err, errUpdateProject))
+		}
+	}
+	if diff.visibility {
+		err := c.projectClient.UpdateVisibility(ctx, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.Visibility)
+		if isNotFound(err) && !c.disableRecreation {
+			u, err := c.recreate(ctx, cr)
+			return u, kerrors.NewAggregate(append(errs, err))
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrap(err, errUpdateProject))
+		}
+	}
+	if diff.tags {
+		if err := c.projectClient.SetTags(ctx, cr.Spec.ForProvider.Key, desiredTags(cr.Spec.ForProvider.Tags, c.defaultTags, project.Tags)); err != nil {
+			errs = append(errs, errors.Wrap(err, errUpdateProject))
+		}
+	}
+	if diff.qualityGate {
+		if err := c.qualityGateClient.SelectForProject(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.QualityGate, cr.Spec.ForProvider.Key); err != nil {
+			errs = append(errs, errors.Wrap(err, errSelectQualityGate))
+		}
+	}
+	if p := cr.Spec.ForProvider.NewCodePeriod; diff.newCodePeriod {
+		if err := c.projectClient.SetNewCodePeriod(ctx, cr.Spec.ForProvider.Key, sonar.NewCodePeriod{Type: p.Type, Value: p.Value}); err != nil {
+			errs = append(errs, errors.Wrap(err, errSetNewCodePeriod))
+		}
+	}
+	if diff.mainBranch {
+		if err := c.branchClient.RenameMainBranch(ctx, cr.Spec.ForProvider.Key, cr.Spec.ForProvider.MainBranch); err != nil {
+			errs = append(errs, errors.Wrap(err, errRenameMainBranch))
+		}
+	}
+	if diff.links {
+		if err := c.updateLinks(ctx, cr, observed.links); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if diff.profileAssociations {
+		if err := c.updateProfileAssociations(ctx, cr, observed.profiles); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if diff.protectedBranches {
+		if err := c.updateProtectedBranches(ctx, cr, observed.branches); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if diff.exclusions {
+		if err := c.updateExclusions(ctx, cr, observed.exclusions); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if diff.badgeToken {
+		if err := c.projectClient.RenewBadgeToken(ctx, cr.Spec.ForProvider.Key); err != nil {
+			errs = append(errs, errors.Wrap(err, errRenewBadgeToken))
+		} else {
+			cr.Status.AtProvider.BadgeTokenRotation = cr.Spec.ForProvider.BadgeTokenRotation
+		}
+	}
+
+	cd, err := c.connectionDetails(ctx, cr, true)
 	if err != nil {
-		log.Fatal(err)
+		return managed.ExternalUpdate{}, kerrors.NewAggregate(append(errs, err))
 	}
 
-	return managed.ExternalUpdate{
-		// Optionally return any details that may be required to connect to the
-		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
-	}, nil
+	if diff.analysisToken {
+		token, err := c.regenerateAnalysisToken(ctx, cr)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			cr.Status.AtProvider.AnalysisTokenName = token.Name
+			cd[keyAnalysisToken] = []byte(token.Token)
+		}
+	}
+
+	return managed.ExternalUpdate{ConnectionDetails: cd}, kerrors.NewAggregate(errs)
+}
+
+// applyTemplateProject applies the permission template and the quality gate of
+// the template project of a Project to its new project. The quality gate is
+// left to the qualityGate parameter when it is set.
+func (c *external) applyTemplateProject(ctx context.Context, cr *v1alpha1.Project) error {
+	org, template := c.organizationOf(cr), cr.Spec.ForProvider.TemplateProject
+
+	templates, err := c.permissionTemplateClient.Search(ctx, org, "")
+	if err != nil {
+		return errors.Wrap(err, errSearchPermTemplates)
+	}
+	// A template project matched by no template has the default permission
+	// template, which the new project was given too.
+	if t, ok := sonar.MatchProjectKey(templates, template); ok {
+		if err := c.permissionTemplateClient.ApplyToProject(ctx, org, t.Id, cr.Spec.ForProvider.Key); err != nil {
+			return errors.Wrap(err, errApplyPermTemplate)
+		}
+	}
+
+	if cr.Spec.ForProvider.QualityGate != "" {
+		return nil
+	}
+	gate, err := c.qualityGateClient.GetGateForProject(ctx, org, template)
+	if err != nil {
+		return errors.Wrap(err, errGetTemplateQualityGate)
+	}
+	if err := c.qualityGateClient.SelectForProject(ctx, org, gate.Name, cr.Spec.ForProvider.Key); err != nil {
+		return errors.Wrap(err, errSelectQualityGate)
+	}
+	return nil
+}
+
+// recreate creates a Project that was deleted after it was observed, e.g. by
+// another actor in the middle of a reconcile, rather than failing its update.
+func (c *external) recreate(ctx context.Context, cr *v1alpha1.Project) (managed.ExternalUpdate, error) {
+	if recentlyCreated(cr) {
+		return managed.ExternalUpdate{}, errors.New(errNotYetVisible)
+	}
+
+	c.logger.Debug("Project deleted before it was updated, creating it again", "key", cr.Spec.ForProvider.Key)
+
+	creation, err := c.Create(ctx, cr)
+	return managed.ExternalUpdate{ConnectionDetails: creation.ConnectionDetails}, err
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -225,12 +634,706 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotProject)
 	}
 
-	fmt.Printf("Deleting: %+v", cr)
+	c.logger.Debug("Deleting project", "key", cr.Spec.ForProvider.Key)
+
+	if err := c.checkDeletionProtection(ctx, cr); err != nil {
+		return err
+	}
+
+	name := cr.Status.AtProvider.AnalysisTokenName
+	if name == "" && cr.Spec.ForProvider.GenerateAnalysisToken {
+		name = analysisToken(cr).Name
+	}
+	if name != "" {
+		if err := c.userTokenClient.Revoke(ctx, name); err != nil && !isNotFound(err) {
+			return errors.Wrap(err, errRevokeAnalysisToken)
+		}
+	}
+
+	// A project already deleted out of band must not block the removal of
+	// the managed resource.
+	if err := c.projectClient.Delete(ctx, cr.Spec.ForProvider.Key); err != nil && !isNotFound(err) {
+		return errors.Wrap(err, errDeleteProject)
+	}
+	return nil
+}
 
-	err := c.projectClient.Delete(ctx, cr.Spec.ForProvider.Key)
+// checkDeletionProtection returns an error if a Project has the deletion
+// protection annotation and its project was analyzed within the days it holds.
+// The error surfaces as the condition of the Project until the annotation is
+// removed or the period passes.
+func (c *external) checkDeletionProtection(ctx context.Context, cr *v1alpha1.Project) error {
+	v, ok := cr.GetAnnotations()[v1alpha1.AnnotationKeyDeletionProtectionDays]
+	if !ok {
+		return nil
+	}
+	days, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || days <= 0 {
+		return errors.New(errProtectionDays)
+	}
+
+	project, err := c.projectClient.GetByProjectKey(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Key)
+	if errors.Is(err, sonar.ErrProjectNotFound) {
+		return nil
+	}
 	if err != nil {
-		log.Fatal(err)
+		return errors.Wrap(err, errGetProject)
 	}
 
+	analyzed := project.LastAnalysisDate.Time
+	if !analyzed.IsZero() && time.Since(analyzed) < time.Duration(days)*24*time.Hour {
+		return errors.Errorf(errDeletionProtected, analyzed.Format(time.RFC3339), days)
+	}
 	return nil
 }
+
+// connectionDetails returns the details published to the connection secret
+// of a Project, so that composed resources such as CI configuration can refer
+// to it. The badge token lets badge URLs be composed. It takes a request, so
+// it is only included when badgeToken is true, and otherwise left as
+// published before, since the publisher never removes a detail.
+func (c *external) connectionDetails(ctx context.Context, cr *v1alpha1.Project, badgeToken bool) (managed.ConnectionDetails, error) {
+	u, err := c.projectClient.GetProjectUrl(cr.Spec.ForProvider.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, errProjectURL)
+	}
+
+	cd := managed.ConnectionDetails{
+		keyProjectKey:   []byte(cr.Spec.ForProvider.Key),
+		keyProjectURL:   []byte(u),
+		keyOrganization: []byte(c.organizationOf(cr)),
+	}
+	if badgeToken {
+		token, err := c.projectClient.GetBadgeToken(ctx, cr.Spec.ForProvider.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetBadgeToken)
+		}
+		cd[keyBadgeToken] = []byte(token)
+	}
+	return cd, nil
+}
+
+// missingBadgeToken returns true if the connection secret of a Project does
+// not hold its badge token, which Observe then fetches. Create and Update
+// always fetch it. Details published to an external secret store are not
+// read, so the badge token is always fetched for them.
+func (c *external) missingBadgeToken(ctx context.Context, cr *v1alpha1.Project) bool {
+	if cr.GetPublishConnectionDetailsTo() != nil {
+		return true
+	}
+	ref := cr.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return false
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return true
+	}
+	return len(s.Data[keyBadgeToken]) == 0
+}
+
+// observeAnalyses reports the date of the latest analysis of a Project, and
+// the latest change of its quality gate status, from its recent analyses.
+func (c *external) observeAnalyses(ctx context.Context, cr *v1alpha1.Project) error {
+	cr.Status.AtProvider.LastQualityGateStatus = ""
+	cr.Status.AtProvider.LastQualityGateChangeDate = nil
+	if !cr.Spec.ForProvider.ReportAnalyses {
+		return nil
+	}
+
+	analyses, err := c.analysesClient.Search(ctx, cr.Spec.ForProvider.Key)
+	if err != nil {
+		return errors.Wrap(err, errSearchAnalyses)
+	}
+	if len(analyses) > 0 && !analyses[0].Date.IsZero() {
+		cr.Status.AtProvider.LastAnalysisDate = &metav1.Time{Time: analyses[0].Date.Time}
+	}
+
+	// Analyses are returned newest first.
+	for _, a := range analyses {
+		for _, e := range a.Events {
+			if e.Category != sonar.ProjectAnalysisEventQualityGate || e.QualityGate == nil {
+				continue
+			}
+			cr.Status.AtProvider.LastQualityGateStatus = e.QualityGate.Status
+			cr.Status.AtProvider.LastQualityGateChangeDate = &metav1.Time{Time: a.Date.Time}
+			return nil
+		}
+	}
+	return nil
+}
+
+// observeIssueCounts reports the number of open blocker and critical issues,
+// and of security hotspots to review, in the status of a Project that asks
+// for them.
+func (c *external) observeIssueCounts(ctx context.Context, cr *v1alpha1.Project) error {
+	cr.Status.AtProvider.OpenBlockerCount = nil
+	cr.Status.AtProvider.OpenCriticalCount = nil
+	cr.Status.AtProvider.OpenHotspotCount = nil
+	if !cr.Spec.ForProvider.ReportIssueCounts {
+		return nil
+	}
+
+	blockers, err := c.countOpenIssues(ctx, cr, sonar.SeverityBlocker)
+	if err != nil {
+		return err
+	}
+	critical, err := c.countOpenIssues(ctx, cr, sonar.SeverityCritical)
+	if err != nil {
+		return err
+	}
+	hotspots, err := c.issuesClient.CountHotspots(ctx, cr.Spec.ForProvider.Key, sonar.HotspotStatusToReview)
+	if err != nil {
+		return errors.Wrap(err, errCountHotspots)
+	}
+
+	cr.Status.AtProvider.OpenBlockerCount = &blockers
+	cr.Status.AtProvider.OpenCriticalCount = &critical
+	cr.Status.AtProvider.OpenHotspotCount = &hotspots
+	return nil
+}
+
+// countOpenIssues counts the open issues of a Project with the supplied
+// severity.
+func (c *external) countOpenIssues(ctx context.Context, cr *v1alpha1.Project, severity string) (int, error) {
+	resolved := false
+	n, err := c.issuesClient.Count(ctx, cr.Spec.ForProvider.Key, sonar.IssueFilters{
+		Organization: cr.Spec.ForProvider.Organization,
+		Severities:   []string{severity},
+		Resolved:     &resolved,
+	})
+	return n, errors.Wrap(err, errCountIssues)
+}
+
+// analysisToken returns the project analysis token generated for a Project.
+func analysisToken(cr *v1alpha1.Project) sonar.UserToken {
+	return sonar.UserToken{
+		Name:       "crossplane-" + cr.Spec.ForProvider.Key,
+		Type:       sonar.UserTokenTypeProjectAnalysis,
+		ProjectKey: cr.Spec.ForProvider.Key,
+	}
+}
+
+// regenerateAnalysisToken generates the analysis token of a Project that has
+// none recorded in its status. A token left with the same name, e.g. by a
+// status update that was lost, is revoked first since its value is unknown.
+func (c *external) regenerateAnalysisToken(ctx context.Context, cr *v1alpha1.Project) (sonar.UserToken, error) {
+	want := analysisToken(cr)
+
+	tokens, err := c.userTokenClient.Search(ctx)
+	if err != nil {
+		return sonar.UserToken{}, errors.Wrap(err, errSearchAnalysisTokens)
+	}
+	for _, t := range tokens {
+		if t.Name == want.Name {
+			if err := c.userTokenClient.Revoke(ctx, t.Name); err != nil {
+				return sonar.UserToken{}, errors.Wrap(err, errRevokeAnalysisToken)
+			}
+		}
+	}
+
+	token, err := c.userTokenClient.Generate(ctx, want)
+	return token, errors.Wrap(err, errGenerateAnalysisToken)
+}
+
+// updateLinks makes the links of a Project match its links parameter. The
+// API cannot change the URL of a link, so a link whose URL drifted is deleted
+// and created again.
+func (c *external) updateLinks(ctx context.Context, cr *v1alpha1.Project, links []sonar.ProjectLink) error {
+	create, remove := diffLinks(cr.Spec.ForProvider.Links, links)
+	for _, l := range remove {
+		if err := c.linkClient.Delete(ctx, l.Id); err != nil {
+			return errors.Wrap(err, errDeleteLink)
+		}
+	}
+	for _, l := range create {
+		if _, err := c.linkClient.Create(ctx, cr.Spec.ForProvider.Key, l.Name, l.URL); err != nil {
+			return errors.Wrap(err, errCreateLink)
+		}
+	}
+	return nil
+}
+
+// diffLinks returns the links to create and the observed links to remove so
+// that the custom links of a project match the desired ones, keyed by name.
+// Links provided by the scanner are never removed.
+func diffLinks(want []v1alpha1.ProjectLink, have []sonar.ProjectLink) ([]v1alpha1.ProjectLink, []sonar.ProjectLink) {
+	desired := make(map[string]string, len(want))
+	for _, l := range want {
+		desired[l.Name] = l.URL
+	}
+
+	kept := make(map[string]bool, len(have))
+	var remove []sonar.ProjectLink
+	for _, l := range have {
+		if l.Type != sonar.ProjectLinkTypeCustom {
+			continue
+		}
+		u, ok := desired[l.Name]
+		if !ok || u != l.Url || kept[l.Name] {
+			remove = append(remove, l)
+			continue
+		}
+		kept[l.Name] = true
+	}
+
+	var create []v1alpha1.ProjectLink
+	for _, l := range want {
+		if !kept[l.Name] {
+			create = append(create, l)
+		}
+	}
+	return create, remove
+}
+
+// updateProfileAssociations makes the quality profiles of a Project match its
+// profileAssociations parameter, and records them in its status so that a
+// language later removed from the parameter reverts to the default profile.
+func (c *external) updateProfileAssociations(ctx context.Context, cr *v1alpha1.Project, profiles []sonar.QualityProfile) error {
+	add, remove := diffProfileAssociations(cr.Spec.ForProvider.ProfileAssociations, cr.Status.AtProvider.ProfileAssociations, profiles)
+	for language, name := range remove {
+		if err := c.profileClient.RemoveProject(ctx, cr.Spec.ForProvider.Organization, language, name, cr.Spec.ForProvider.Key); err != nil {
+			return errors.Wrap(err, errRemoveQualityProfile)
+		}
+	}
+	for language, name := range add {
+		if err := c.profileClient.AddProject(ctx, cr.Spec.ForProvider.Organization, language, name, cr.Spec.ForProvider.Key); err != nil {
+			return errors.Wrap(err, errAddQualityProfile)
+		}
+	}
+	cr.Status.AtProvider.ProfileAssociations = copyProfileAssociations(cr.Spec.ForProvider.ProfileAssociations)
+	return nil
+}
+
+// diffProfileAssociations returns the profiles to associate with a project and
+// the associations to remove, both keyed by language. A profile is associated
+// when the project uses another one for its language. An association made
+// earlier, but no longer desired, is removed unless the project already uses
+// another profile for that language.
+func diffProfileAssociations(want, associated map[string]string, have []sonar.QualityProfile) (map[string]string, map[string]string) {
+	used := make(map[string]sonar.QualityProfile, len(have))
+	for _, p := range have {
+		used[p.Language] = p
+	}
+
+	add := map[string]string{}
+	for language, name := range want {
+		if used[language].Name != name {
+			add[language] = name
+		}
+	}
+
+	remove := map[string]string{}
+	for language, name := range associated {
+		if _, ok := want[language]; ok {
+			continue
+		}
+		if p := used[language]; p.Name == name && !p.IsDefault {
+			remove[language] = name
+		}
+	}
+	return add, remove
+}
+
+// copyProfileAssociations returns a copy of the supplied associations, or nil
+// if there are none.
+func copyProfileAssociations(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for language, name := range in {
+		out[language] = name
+	}
+	return out
+}
+
+// updateProtectedBranches protects the branches of a Project listed in its
+// protectedBranches parameter from automatic deletion, and records them in its
+// status so that a branch later removed from the parameter is unprotected.
+func (c *external) updateProtectedBranches(ctx context.Context, cr *v1alpha1.Project, branches []sonar.Branch) error {
+	protect, unprotect := diffProtectedBranches(cr.Spec.ForProvider.ProtectedBranches, cr.Status.AtProvider.ProtectedBranches, branches)
+	for _, b := range unprotect {
+		if err := c.branchClient.SetAutomaticDeletionProtection(ctx, cr.Spec.ForProvider.Key, b, false); err != nil {
+			return errors.Wrap(err, errUnprotectBranch)
+		}
+	}
+	for _, b := range protect {
+		if err := c.branchClient.SetAutomaticDeletionProtection(ctx, cr.Spec.ForProvider.Key, b, true); err != nil {
+			return errors.Wrap(err, errProtectBranch)
+		}
+	}
+	cr.Status.AtProvider.ProtectedBranches = append([]string(nil), cr.Spec.ForProvider.ProtectedBranches...)
+	return nil
+}
+
+// diffProtectedBranches returns the branches to protect from automatic
+// deletion and the branches to unprotect. A desired branch is protected once
+// it exists. A branch protected earlier, but no longer desired, is unprotected
+// unless it is the main branch, which is always kept.
+func diffProtectedBranches(want, protected []string, have []sonar.Branch) ([]string, []string) {
+	existing := make(map[string]sonar.Branch, len(have))
+	for _, b := range have {
+		existing[b.Name] = b
+	}
+
+	desired := make(map[string]bool, len(want))
+	var protect []string
+	for _, name := range want {
+		desired[name] = true
+		if b, ok := existing[name]; ok && !b.ExcludedFromPurge {
+			protect = append(protect, name)
+		}
+	}
+
+	var unprotect []string
+	for _, name := range protected {
+		if desired[name] {
+			continue
+		}
+		if b, ok := existing[name]; ok && !b.IsMain && b.ExcludedFromPurge {
+			unprotect = append(unprotect, name)
+		}
+	}
+	return protect, unprotect
+}
+
+// exclusions returns the desired patterns of the exclusion settings of a
+// Project, keyed by setting. A setting is included while it is set by the
+// spec, or was set earlier, in which case its patterns are empty and it is to
+// be reset.
+func exclusions(cr *v1alpha1.Project) map[string][]string {
+	out := map[string][]string{}
+	if len(cr.Spec.ForProvider.Exclusions) > 0 || len(cr.Status.AtProvider.Exclusions) > 0 {
+		out[settingExclusions] = cr.Spec.ForProvider.Exclusions
+	}
+	if len(cr.Spec.ForProvider.CoverageExclusions) > 0 || len(cr.Status.AtProvider.CoverageExclusions) > 0 {
+		out[settingCoverageExclusions] = cr.Spec.ForProvider.CoverageExclusions
+	}
+	return out
+}
+
+// updateExclusions makes the exclusion settings of a Project match its
+// exclusions and coverageExclusions parameters, and records them in its status
+// so that a list later emptied is reset.
+func (c *external) updateExclusions(ctx context.Context, cr *v1alpha1.Project, settings []sonar.Setting) error {
+	set, reset := diffExclusions(exclusions(cr), settings)
+	if len(reset) > 0 {
+		if err := c.settingsClient.Reset(ctx, cr.Spec.ForProvider.Key, reset...); err != nil {
+			return errors.Wrap(err, errResetExclusions)
+		}
+	}
+	for _, key := range []string{settingExclusions, settingCoverageExclusions} {
+		patterns, ok := set[key]
+		if !ok {
+			continue
+		}
+		if err := c.settingsClient.Set(ctx, cr.Spec.ForProvider.Key, sonar.Setting{Key: key, Values: patterns}); err != nil {
+			return errors.Wrap(err, errSetExclusions)
+		}
+	}
+	cr.Status.AtProvider.Exclusions = append([]string(nil), cr.Spec.ForProvider.Exclusions...)
+	cr.Status.AtProvider.CoverageExclusions = append([]string(nil), cr.Spec.ForProvider.CoverageExclusions...)
+	return nil
+}
+
+// diffExclusions returns the settings to set, with their patterns, and the
+// keys of the settings to reset. A setting is set when the project does not
+// hold exactly the desired patterns, in order. A setting without desired
+// patterns is reset when the project still sets it.
+func diffExclusions(want map[string][]string, have []sonar.Setting) (map[string][]string, []string) {
+	observed := make(map[string]sonar.Setting, len(have))
+	for _, s := range have {
+		if !s.Inherited {
+			observed[s.Key] = s
+		}
+	}
+
+	set := map[string][]string{}
+	var reset []string
+	for _, key := range []string{settingExclusions, settingCoverageExclusions} {
+		patterns, ok := want[key]
+		if !ok {
+			continue
+		}
+		s, found := observed[key]
+		switch {
+		case len(patterns) == 0 && found:
+			reset = append(reset, key)
+		case len(patterns) > 0 && !samePatterns(patterns, s.Values):
+			set[key] = patterns
+		}
+	}
+	return set, reset
+}
+
+// samePatterns returns true if a and b hold the same patterns in the same
+// order.
+func samePatterns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isNotFound returns true if err is a 404 answered by the Sonar API.
+func isNotFound(err error) bool {
+	var apiErr *sonar.SonarAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// An observedProject is the state of a project, and of the settings of it
+// that its parameters manage. The settings that are not managed are left
+// unobserved and empty.
+type observedProject struct {
+	project       sonar.Project
+	qualityGate   string
+	newCodePeriod sonar.NewCodePeriod
+	mainBranch    string
+	links         []sonar.ProjectLink
+	profiles      []sonar.QualityProfile
+	branches      []sonar.Branch
+	exclusions    []sonar.Setting
+}
+
+// observeManaged observes the settings of a project that its parameters
+// manage.
+func (c *external) observeManaged(ctx context.Context, cr *v1alpha1.Project, project sonar.Project) (observedProject, error) {
+	in := cr.Spec.ForProvider
+	observed := observedProject{project: project}
+
+	if in.QualityGate != "" {
+		gate, err := c.qualityGateClient.GetGateForProject(ctx, in.Organization, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errGetQualityGate)
+		}
+		observed.qualityGate = gate.Name
+	}
+	if in.NewCodePeriod != nil {
+		period, err := c.projectClient.GetNewCodePeriod(ctx, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errGetNewCodePeriod)
+		}
+		observed.newCodePeriod = period
+	}
+	if in.MainBranch != "" {
+		branch, err := c.branchClient.GetMainBranch(ctx, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errGetMainBranch)
+		}
+		observed.mainBranch = branch.Name
+	}
+	if len(in.Links) > 0 {
+		links, err := c.linkClient.Search(ctx, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errSearchLinks)
+		}
+		observed.links = links
+	}
+	if len(in.ProfileAssociations) > 0 || len(cr.Status.AtProvider.ProfileAssociations) > 0 {
+		profiles, err := c.profileClient.SearchForProject(ctx, in.Organization, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errSearchQualityProfiles)
+		}
+		observed.profiles = profiles
+	}
+	if len(in.ProtectedBranches) > 0 || len(cr.Status.AtProvider.ProtectedBranches) > 0 {
+		branches, err := c.branchClient.List(ctx, in.Key)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errListBranches)
+		}
+		observed.branches = branches
+	}
+	if len(exclusions(cr)) > 0 {
+		settings, err := c.settingsClient.Values(ctx, in.Key, settingExclusions, settingCoverageExclusions)
+		if err != nil {
+			return observedProject{}, errors.Wrap(err, errGetExclusions)
+		}
+		observed.exclusions = settings
+	}
+	return observed, nil
+}
+
+// A projectDiff holds the managed fields of a project that differ from its
+// parameters. Update applies each of them on its own.
+type projectDiff struct {
+	name                bool
+	visibility          bool
+	tags                bool
+	qualityGate         bool
+	newCodePeriod       bool
+	mainBranch          bool
+	links               bool
+	profileAssociations bool
+	protectedBranches   bool
+	exclusions          bool
+	badgeToken          bool
+	analysisToken       bool
+}
+
+// upToDate returns true if no managed field differs.
+func (d projectDiff) upToDate() bool {
+	return d == projectDiff{}
+}
+
+// fields returns the names of the parameters that differ, for logging.
+func (d projectDiff) fields() []string {
+	var fields []string
+	for _, f := range []struct {
+		name    string
+		differs bool
+	}{
+		{"name", d.name},
+		{"visibility", d.visibility},
+		{"tags", d.tags},
+		{"qualityGate", d.qualityGate},
+		{"newCodePeriod", d.newCodePeriod},
+		{"mainBranch", d.mainBranch},
+		{"links", d.links},
+		{"profileAssociations", d.profileAssociations},
+		{"protectedBranches", d.protectedBranches},
+		{"exclusions", d.exclusions},
+		{"badgeTokenRotation", d.badgeToken},
+		{"generateAnalysisToken", d.analysisToken},
+	} {
+		if f.differs {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// diffProject compares every managed field of a project with its parameters.
+// Empty parameters are late-initialized or unmanaged and always match.
+func diffProject(cr *v1alpha1.Project, observed observedProject, defaultTags []string) projectDiff {
+	in := cr.Spec.ForProvider
+	project := observed.project
+
+	d := projectDiff{
+		name:          in.Name != "" && in.Name != project.Name,
+		visibility:    in.Visibility != "" && in.Visibility != project.Visibility,
+		tags:          !sameTags(desiredTags(in.Tags, defaultTags, project.Tags), project.Tags),
+		qualityGate:   in.QualityGate != "" && in.QualityGate != observed.qualityGate,
+		newCodePeriod: in.NewCodePeriod != nil && !isNewCodePeriodUpToDate(*in.NewCodePeriod, observed.newCodePeriod),
+		mainBranch:    in.MainBranch != "" && in.MainBranch != observed.mainBranch,
+		badgeToken:    in.BadgeTokenRotation != cr.Status.AtProvider.BadgeTokenRotation,
+		analysisToken: in.GenerateAnalysisToken && cr.Status.AtProvider.AnalysisTokenName == "",
+	}
+	if len(in.Links) > 0 {
+		create, remove := diffLinks(in.Links, observed.links)
+		d.links = len(create) > 0 || len(remove) > 0
+	}
+	if len(in.ProfileAssociations) > 0 || len(cr.Status.AtProvider.ProfileAssociations) > 0 {
+		add, remove := diffProfileAssociations(in.ProfileAssociations, cr.Status.AtProvider.ProfileAssociations, observed.profiles)
+		d.profileAssociations = len(add) > 0 || len(remove) > 0
+	}
+	if len(in.ProtectedBranches) > 0 || len(cr.Status.AtProvider.ProtectedBranches) > 0 {
+		protect, unprotect := diffProtectedBranches(in.ProtectedBranches, cr.Status.AtProvider.ProtectedBranches, observed.branches)
+		d.protectedBranches = len(protect) > 0 || len(unprotect) > 0
+	}
+	if want := exclusions(cr); len(want) > 0 {
+		set, reset := diffExclusions(want, observed.exclusions)
+		d.exclusions = len(set) > 0 || len(reset) > 0
+	}
+	return d
+}
+
+// recordManaged records the profile associations, protected branches and
+// exclusions of a Project in its status once the project matches them. These
+// are recorded by Update too, but not when they were set by Create, whose
+// status is not persisted.
+func recordManaged(cr *v1alpha1.Project, d projectDiff) {
+	if !d.profileAssociations {
+		cr.Status.AtProvider.ProfileAssociations = copyProfileAssociations(cr.Spec.ForProvider.ProfileAssociations)
+	}
+	if !d.protectedBranches {
+		cr.Status.AtProvider.ProtectedBranches = append([]string(nil), cr.Spec.ForProvider.ProtectedBranches...)
+	}
+	if !d.exclusions {
+		cr.Status.AtProvider.Exclusions = append([]string(nil), cr.Spec.ForProvider.Exclusions...)
+		cr.Status.AtProvider.CoverageExclusions = append([]string(nil), cr.Spec.ForProvider.CoverageExclusions...)
+	}
+}
+
+// desiredTags returns the tags a project should carry: the supplied tags, or
+// the observed ones when tags are unmanaged, merged with the default tags of
+// the ProviderConfig.
+func desiredTags(tags, defaults, observed []string) []string {
+	if len(tags) == 0 {
+		tags = observed
+	}
+	if len(defaults) == 0 {
+		return tags
+	}
+
+	merged := make([]string, 0, len(tags)+len(defaults))
+	seen := make(map[string]bool, len(tags)+len(defaults))
+	for _, t := range append(append([]string{}, tags...), defaults...) {
+		if !seen[t] {
+			seen[t] = true
+			merged = append(merged, t)
+		}
+	}
+	return merged
+}
+
+// isNewCodePeriodUpToDate returns true if the observed new code period is
+// the desired one and set on the project itself rather than inherited.
+func isNewCodePeriodUpToDate(in v1alpha1.NewCodePeriod, period sonar.NewCodePeriod) bool {
+	return !period.Inherited && in.Type == period.Type && in.Value == period.Value
+}
+
+// sameTags returns true if a and b hold the same tags in any order.
+func sameTags(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, t := range a {
+		set[t] = true
+	}
+	other := make(map[string]bool, len(b))
+	for _, t := range b {
+		if !set[t] {
+			return false
+		}
+		other[t] = true
+	}
+	return len(set) == len(other)
+}
+
+// lateInitializeKey reconciles the key of a project with its external name. An
+// unset key is taken from the external name, which lets an existing project be
+// imported by its key. Otherwise the key wins, and a differing external name,
+// such as the resource name set by earlier versions, is replaced. It returns
+// true if either changed.
+func lateInitializeKey(cr *v1alpha1.Project) bool {
+	en := meta.GetExternalName(cr)
+	switch {
+	case en == "" || en == cr.Spec.ForProvider.Key:
+		return false
+	case cr.Spec.ForProvider.Key == "":
+		cr.Spec.ForProvider.Key = en
+	default:
+		meta.SetExternalName(cr, cr.Spec.ForProvider.Key)
+	}
+	return true
+}
+
+// lateInitialize fills the unset fields of the supplied parameters from the
+// observed project. Fields set by the user are never overwritten. It returns
+// true if any field was late-initialized.
+func lateInitialize(in *v1alpha1.ProjectParameters, project sonar.Project) bool {
+	li := false
+	if in.Name == "" && project.Name != "" {
+		in.Name = project.Name
+		li = true
+	}
+	if in.Visibility == "" && project.Visibility != "" {
+		in.Visibility = project.Visibility
+		li = true
+	}
+	return li
+}
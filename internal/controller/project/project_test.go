@@ -18,14 +18,34 @@ package project
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/project/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
 	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar/fake"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -38,7 +58,7 @@ import (
 
 func TestObserve(t *testing.T) {
 	type fields struct {
-		service interface{}
+		handler http.HandlerFunc
 	}
 
 	type args struct {
@@ -48,28 +68,2621 @@ func TestObserve(t *testing.T) {
 
 	type want struct {
 		o   managed.ExternalObservation
+		cr  *v1alpha1.Project
 		err error
 	}
 
+	observed := searchResponse(sonar.Project{
+		Organization: "org",
+		Key:          "key",
+		Name:         "Server Name",
+		Visibility:   "private",
+	})
+
 	cases := map[string]struct {
 		reason string
 		fields fields
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"NotFound": {
+			reason: "A project that does not exist should be reported as such.",
+			fields: fields{handler: searchResponse()},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key")),
+			},
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: false},
+				cr: project(withKey("key")),
+			},
+		},
+		"EmptyKey": {
+			reason: "A project without a key should be rejected rather than matched against arbitrary projects.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("e.Observe(...): unexpected request %s for a project without a key", r.URL.Path)
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withName("Server Name"), withVisibility("private")),
+			},
+			want: want{
+				err: errors.New(errEmptyKey),
+				cr:  project(withName("Server Name"), withVisibility("private")),
+			},
+		},
+		"OrganizationChanged": {
+			reason: "A project whose organization changed should be rejected rather than created again in the new organization.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("e.Observe(...): unexpected request %s for a project that moved organization", r.URL.Path)
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withOrganization("other"), withObservedOrganization("org"), withKey("key")),
+			},
+			want: want{
+				err: errors.Errorf(errMoveProject, "org", "other"),
+				cr:  project(withOrganization("other"), withObservedOrganization("org"), withKey("key")),
+			},
+		},
+		"LateInitialize": {
+			reason: "Fields the user left empty should be late-initialized from the observed project.",
+			fields: fields{handler: observed},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private")),
+			},
+		},
+		"ImportByExternalName": {
+			reason: "A project without a key should be imported by the key in its external name annotation.",
+			fields: fields{handler: observed},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName("key"), withName("Server Name"), withVisibility("private")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withExternalName("key"), withKey("key"), withName("Server Name"), withVisibility("private")),
+			},
+		},
+		"ExternalNameFollowsKey": {
+			reason: "An external name differing from the key, such as the resource name, should be replaced by the key.",
+			fields: fields{handler: observed},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withExternalName("test-project-name"), withKey("key"), withName("Server Name"), withVisibility("private")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withExternalName("key"), withKey("key"), withName("Server Name"), withVisibility("private")),
+			},
+		},
+		"DoNotOverwrite": {
+			reason: "Fields the user set explicitly should not be overwritten by the observed project.",
+			fields: fields{handler: observed},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("User Name"), withVisibility("public")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("User Name"), withVisibility("public")),
+			},
+		},
+		"InsufficientPermissions": {
+			reason: "A project the token is not allowed to see should not be reported as absent.",
+			fields: fields{handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"errors":[{"msg":"Insufficient privileges"}]}`))
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key")),
+			},
+			want: want{
+				err: errors.Wrap(fmt.Errorf("%w: %s", sonar.ErrInsufficientPermissions, &sonar.SonarAPIError{
+					StatusCode: http.StatusForbidden,
+					Status:     "403 Forbidden",
+					Message:    "Insufficient privileges",
+				}), errGetProject),
+				cr: project(withKey("key")),
+			},
+		},
+		"TagsAdded": {
+			reason: "A desired tag missing from the observed project should be reported as not up to date.",
+			fields: fields{handler: searchResponse(sonar.Project{Key: "key", Name: "Server Name", Visibility: "private", Tags: []string{"team:payments"}})},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withTags("team:payments", "tier:1")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        false,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withTags("team:payments", "tier:1")),
+			},
+		},
+		"TagsRemoved": {
+			reason: "An observed tag that is no longer desired should be reported as not up to date.",
+			fields: fields{handler: searchResponse(sonar.Project{Key: "key", Name: "Server Name", Visibility: "private", Tags: []string{"team:payments", "tier:1"}})},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withTags("team:payments")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        false,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withTags("team:payments")),
+			},
+		},
+		"TagsReordered": {
+			reason: "Tags are a set, so a different order should be reported as up to date.",
+			fields: fields{handler: searchResponse(sonar.Project{Key: "key", Name: "Server Name", Visibility: "private", Tags: []string{"tier:1", "team:payments"}})},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withTags("team:payments", "tier:1")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withTags("team:payments", "tier:1")),
+			},
+		},
+		"QualityGateDrift": {
+			reason: "A project whose quality gate was changed outside of Crossplane should be reported as not up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/qualitygates/get_by_project" {
+					_, _ = w.Write([]byte(`{"qualityGate":{"id":"1","name":"Sonar way"}}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withQualityGate("strict")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withQualityGate("strict")),
+			},
+		},
+		"NewCodePeriodDrift": {
+			reason: "A project whose new code period was changed outside of Crossplane should be reported as not up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/new_code_periods/show" {
+					_, _ = w.Write([]byte(`{"projectKey":"key","type":"NUMBER_OF_DAYS","value":"15"}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("NUMBER_OF_DAYS", "30")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("NUMBER_OF_DAYS", "30")),
+			},
+		},
+		"NewCodePeriodUpToDate": {
+			reason: "A project whose new code period is the desired one should be reported as up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/new_code_periods/show" {
+					_, _ = w.Write([]byte(`{"projectKey":"key","type":"NUMBER_OF_DAYS","value":"30"}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("NUMBER_OF_DAYS", "30")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("NUMBER_OF_DAYS", "30")),
+			},
+		},
+		"Measures": {
+			reason: "The values of the listed metrics should be reported in the status, leaving out metrics without a value.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/measures/component" {
+					_, _ = w.Write([]byte(`{"component":{"key":"key","measures":[{"metric":"coverage","value":"82.5"},{"metric":"bugs","value":"3"}]}}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withMeasures("coverage", "bugs", "code_smells")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withMeasures("coverage", "bugs", "code_smells"),
+					withObservedMeasures(map[string]string{"coverage": "82.5", "bugs": "3"})),
+			},
+		},
+		"MainBranchUpToDate": {
+			reason: "A project whose main branch has the desired name should be reported as up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/project_branches/list" {
+					_, _ = w.Write([]byte(`{"branches":[{"name":"develop"},{"name":"main","isMain":true}]}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("main")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("main"), withObservedMainBranch("main")),
+			},
+		},
+		"MainBranchDrift": {
+			reason: "A project whose main branch has another name should be reported as not up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/project_branches/list" {
+					_, _ = w.Write([]byte(`{"branches":[{"name":"master","isMain":true}]}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("main")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("main"), withObservedMainBranch("master")),
+			},
+		},
+		"LinkDrift": {
+			reason: "A project whose link points to another URL should be reported as not up to date.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/project_links/search" {
+					_, _ = w.Write([]byte(`{"links":[{"id":"2","name":"CI","type":"custom","url":"https://ci.example.com/old"}]}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments")),
+			},
+		},
+		"LinksUpToDate": {
+			reason: "A project whose custom links match should be reported as up to date, whatever the links provided by the scanner.",
+			fields: fields{handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/project_links/search" {
+					_, _ = w.Write([]byte(`{"links":[{"id":"1","type":"scm","url":"https://git.example.com"},{"id":"2","name":"CI","type":"custom","url":"https://ci.example.com/payments"}]}`))
+					return
+				}
+				observed(w, r)
+			}},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments")),
+			},
+		},
+		"NameDrift": {
+			reason: "A project renamed outside of Crossplane should be reported as not up to date.",
+			fields: fields{handler: observed},
+			args: args{
+				ctx: context.Background(),
+				mg:  project(withKey("key"), withName("Desired Name"), withVisibility("private")),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+				},
+				cr: project(withObservedOrganization("org"), withConditions(xpv1.Available()), withKey("key"), withName("Desired Name"), withVisibility("private")),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{projectClient: sonar.ProjectClient{}}
+			srv := httptest.NewServer(tc.fields.handler)
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), qualityGateClient: sonar.NewQualityGateClient(options), measuresClient: sonar.NewMeasuresClient(options), branchClient: sonar.NewProjectBranchClient(options), linkClient: sonar.NewProjectLinkClient(options), logger: logging.NewNopLogger()}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+			// Connection details are covered by TestConnectionDetails.
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreFields(managed.ExternalObservation{}, "ConnectionDetails")); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			// The project URL depends on the test server and is covered by
+			// TestObserveStatus.
+			if diff := cmp.Diff(tc.want.cr, tc.args.mg, test.EquateConditions(), cmpopts.IgnoreFields(v1alpha1.ProjectObservation{}, "ProjectURL")); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveOrganization(t *testing.T) {
+	type want struct {
+		organization string
+		err          error
+	}
+
+	deleted := func(cr *v1alpha1.Project) { cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()}) }
+
+	cases := map[string]struct {
+		reason       string
+		organization string
+		cr           *v1alpha1.Project
+		want         want
+	}{
+		"DefaultOrganization": {
+			reason:       "The default organization of the ProviderConfig should be recorded for a project that sets none.",
+			organization: "org",
+			cr:           project(withOrganization(""), withKey("key")),
+			want:         want{organization: "org"},
+		},
+		"DefaultOrganizationUnchanged": {
+			reason:       "A project in the default organization of the ProviderConfig should not be reported as moved.",
+			organization: "org",
+			cr:           project(withOrganization(""), withObservedOrganization("org"), withKey("key")),
+			want:         want{organization: "org"},
+		},
+		"DefaultOrganizationChanged": {
+			reason:       "A project should be reported as moved when the default organization of its ProviderConfig changed.",
+			organization: "other",
+			cr:           project(withOrganization(""), withObservedOrganization("org"), withKey("key")),
+			want:         want{organization: "org", err: errors.Errorf(errMoveProject, "org", "other")},
+		},
+		"Deleted": {
+			reason: "A project that moved organization should still be observed while it is deleted, so that its deletion is not blocked.",
+			cr:     project(withOrganization("other"), withObservedOrganization("org"), withKey("key"), deleted),
+			want:   want{organization: "other"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(searchResponse(sonar.Project{Organization: "org", Key: "key"}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), organization: tc.organization, logger: logging.NewNopLogger()}
+			_, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.organization, tc.cr.Status.AtProvider.Organization); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want organization, +got organization:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveStatus(t *testing.T) {
+	analyzed := time.Date(2022, time.November, 10, 18, 33, 53, 0, time.UTC)
+	srv := httptest.NewServer(searchResponse(sonar.Project{
+		Organization:     "org",
+		Key:              "key",
+		Name:             "Name",
+		Qualifier:        "TRK",
+		Visibility:       "private",
+		LastAnalysisDate: sonar.SonarTime{Time: analyzed},
+		Revision:         "c3d2f1a",
+	}))
+	defer srv.Close()
+
+	e := external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+	cr := project(withKey("key"), withName("Name"), withVisibility("private"))
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+
+	want := v1alpha1.ProjectObservation{
+		Organization:     "org",
+		LastAnalysisDate: &metav1.Time{Time: analyzed},
+		Qualifier:        "TRK",
+		Revision:         "c3d2f1a",
+		ProjectURL:       srv.URL + "/dashboard?id=key",
+	}
+	if diff := cmp.Diff(want, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Observe(...): -want status, +got status:\n%s\n", diff)
+	}
+}
+
+func TestObserveIssueCounts(t *testing.T) {
+	count := func(n int) *int { return &n }
+
+	type want struct {
+		blockers *int
+		critical *int
+		hotspots *int
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Project
+		want   want
+	}{
+		"Reported": {
+			reason: "The open blocker and critical issues and the hotspots to review should be counted when asked for.",
+			mg:     project(withKey("key"), withName("Name"), withVisibility("private"), withReportIssueCounts()),
+			want:   want{blockers: count(2), critical: count(0), hotspots: count(5)},
+		},
+		"NotReported": {
+			reason: "No count should be reported when not asked for.",
+			mg:     project(withKey("key"), withName("Name"), withVisibility("private")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/issues/search":
+					if r.FormValue("resolved") != "false" {
+						t.Errorf("e.Observe(...): want only open issues counted, got %q", r.URL.RawQuery)
+					}
+					total := map[string]string{"BLOCKER": "2", "CRITICAL": "0"}[r.FormValue("severities")]
+					_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":1,"total":` + total + `},"issues":[]}`))
+				case "/api/hotspots/search":
+					_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":1,"total":5},"hotspots":[]}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), issuesClient: sonar.NewIssuesClient(options), logger: logging.NewNopLogger()}
+			if _, err := e.Observe(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			got := want{
+				blockers: tc.mg.Status.AtProvider.OpenBlockerCount,
+				critical: tc.mg.Status.AtProvider.OpenCriticalCount,
+				hotspots: tc.mg.Status.AtProvider.OpenHotspotCount,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want counts, +got counts:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveAnalyses(t *testing.T) {
+	at := func(s string) *metav1.Time {
+		tm, _ := time.Parse(sonar.SonarTimeLayout, s)
+		return &metav1.Time{Time: tm}
+	}
+
+	type want struct {
+		lastAnalysis *metav1.Time
+		status       string
+		changed      *metav1.Time
+	}
+
+	cases := map[string]struct {
+		reason   string
+		analyses string
+		mg       *v1alpha1.Project
+		want     want
+	}{
+		"Reported": {
+			reason: "The latest analysis and the latest quality gate change should be reported when asked for.",
+			analyses: `[
+				{"key":"a3","date":"2023-05-04T14:08:19+0200","events":[{"key":"e2","category":"VERSION","name":"1.3"}]},
+				{"key":"a2","date":"2023-05-03T10:00:00+0200","events":[{"key":"e1","category":"QUALITY_GATE","name":"Red (was Green)","qualityGate":{"status":"ERROR","stillFailing":false,"failing":[]}}]},
+				{"key":"a1","date":"2023-05-02T09:00:00+0200","events":[{"key":"e0","category":"QUALITY_GATE","name":"Green (was Red)","qualityGate":{"status":"OK","stillFailing":false,"failing":[]}}]}
+			]`,
+			mg: project(withKey("key"), withName("Name"), withVisibility("private"), withReportAnalyses()),
+			want: want{
+				lastAnalysis: at("2023-05-04T14:08:19+0200"),
+				status:       "ERROR",
+				changed:      at("2023-05-03T10:00:00+0200"),
+			},
+		},
+		"NoQualityGateChange": {
+			reason:   "No quality gate status should be reported when none of the recent analyses changed it.",
+			analyses: `[{"key":"a1","date":"2023-05-02T09:00:00+0200","events":[]}]`,
+			mg:       project(withKey("key"), withName("Name"), withVisibility("private"), withReportAnalyses()),
+			want:     want{lastAnalysis: at("2023-05-02T09:00:00+0200")},
+		},
+		"NotReported": {
+			reason:   "The analysis history should not be read when not asked for.",
+			analyses: `[{"key":"a1","date":"2023-05-02T09:00:00+0200","events":[{"key":"e0","category":"QUALITY_GATE","name":"Green","qualityGate":{"status":"OK"}}]}]`,
+			mg:       project(withKey("key"), withName("Name"), withVisibility("private")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/project_analyses/search":
+					_, _ = w.Write([]byte(`{"paging":{"pageIndex":1,"pageSize":100,"total":3},"analyses":` + tc.analyses + `}`))
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), analysesClient: sonar.NewProjectAnalysesClient(options), logger: logging.NewNopLogger()}
+			if _, err := e.Observe(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			got := want{
+				lastAnalysis: tc.mg.Status.AtProvider.LastAnalysisDate,
+				status:       tc.mg.Status.AtProvider.LastQualityGateStatus,
+				changed:      tc.mg.Status.AtProvider.LastQualityGateChangeDate,
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), cmp.Comparer(func(a, b metav1.Time) bool { return a.Equal(&b) })); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want analyses, +got analyses:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type request struct {
+		Path   string
+		Params url.Values
+	}
+
+	observed := sonar.Project{Organization: "org", Key: "key", Name: "Server Name", Visibility: "private", Tags: []string{"team:payments", "tier:1"}}
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Project
+		want   []request
+	}{
+		"NameOnly": {
+			reason: "Only the name should be updated when only the name drifted, and the key should be left unchanged.",
+			mg:     project(withKey("key"), withName("Desired Name"), withVisibility("private")),
+			want: []request{
+				{Path: "/api/projects/update_name", Params: url.Values{"project": {"key"}, "name": {"Desired Name"}}},
+			},
+		},
+		"VisibilityOnly": {
+			reason: "Only the visibility should be updated when only the visibility drifted.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("public")),
+			want: []request{
+				{Path: "/api/projects/update_visibility", Params: url.Values{"project": {"key"}, "visibility": {"public"}}},
+			},
+		},
+		"TagsOnly": {
+			reason: "Only the tags should be set when only the tags drifted.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withTags("team:payments")),
+			want: []request{
+				{Path: "/api/project_tags/set", Params: url.Values{"project": {"key"}, "tags": {"team:payments"}}},
+			},
+		},
+		"QualityGate": {
+			reason: "The quality gate should be selected again when the selection drifted.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withQualityGate("strict")),
+			want: []request{
+				{Path: "/api/qualitygates/select", Params: url.Values{"organization": {"org"}, "gateName": {"strict"}, "projectKey": {"key"}}},
+			},
+		},
+		"QualityGateUpToDate": {
+			reason: "The quality gate should not be selected again when it is already selected.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withQualityGate("Sonar way")),
+		},
+		"TagsReordered": {
+			reason: "Nothing should be updated when the tags only differ in order.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withTags("tier:1", "team:payments")),
+		},
+		"NewCodePeriodPreviousVersion": {
+			reason: "An inherited new code period should be set on the project even when its type matches.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("PREVIOUS_VERSION", "")),
+			want: []request{
+				{Path: "/api/new_code_periods/set", Params: url.Values{"project": {"key"}, "type": {"PREVIOUS_VERSION"}}},
+			},
+		},
+		"NewCodePeriodNumberOfDays": {
+			reason: "A new code period whose type drifted should be set again.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("NUMBER_OF_DAYS", "30")),
+			want: []request{
+				{Path: "/api/new_code_periods/set", Params: url.Values{"project": {"key"}, "type": {"NUMBER_OF_DAYS"}, "value": {"30"}}},
+			},
+		},
+		"NewCodePeriodReferenceBranch": {
+			reason: "A new code period whose value drifted should be set again.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withNewCodePeriod("REFERENCE_BRANCH", "main")),
+			want: []request{
+				{Path: "/api/new_code_periods/set", Params: url.Values{"project": {"key"}, "type": {"REFERENCE_BRANCH"}, "value": {"main"}}},
+			},
+		},
+		"MainBranch": {
+			reason: "The main branch should be renamed when its name drifted.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("main")),
+			want: []request{
+				{Path: "/api/project_branches/rename", Params: url.Values{"project": {"key"}, "name": {"main"}}},
+			},
+		},
+		"MainBranchUpToDate": {
+			reason: "The main branch should not be renamed when it already has the desired name.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withMainBranch("master")),
+		},
+		"LinkCreated": {
+			reason: "A missing link should be created.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments", "Issues", "https://issues.example.com")),
+			want: []request{
+				{Path: "/api/project_links/create", Params: url.Values{"projectKey": {"key"}, "name": {"Issues"}, "url": {"https://issues.example.com"}}},
+			},
+		},
+		"LinkDeleted": {
+			reason: "A custom link that is not listed should be deleted, and a link provided by the scanner kept.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("Issues", "https://issues.example.com")),
+			want: []request{
+				{Path: "/api/project_links/delete", Params: url.Values{"id": {"2"}}},
+				{Path: "/api/project_links/create", Params: url.Values{"projectKey": {"key"}, "name": {"Issues"}, "url": {"https://issues.example.com"}}},
+			},
+		},
+		"LinkUrlDrift": {
+			reason: "A link whose URL drifted should be deleted and created again.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/new")),
+			want: []request{
+				{Path: "/api/project_links/delete", Params: url.Values{"id": {"2"}}},
+				{Path: "/api/project_links/create", Params: url.Values{"projectKey": {"key"}, "name": {"CI"}, "url": {"https://ci.example.com/new"}}},
+			},
+		},
+		"LinksUpToDate": {
+			reason: "Nothing should be changed when the links match.",
+			mg:     project(withKey("key"), withName("Server Name"), withVisibility("private"), withLinks("CI", "https://ci.example.com/payments")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []request
+			search := searchResponse(observed)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/projects/search":
+					search(w, r)
+				case "/api/qualitygates/get_by_project":
+					_, _ = w.Write([]byte(`{"qualityGate":{"id":"1","name":"Sonar way"}}`))
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				case "/api/new_code_periods/show":
+					_, _ = w.Write([]byte(`{"projectKey":"key","type":"PREVIOUS_VERSION","inherited":true}`))
+				case "/api/project_branches/list":
+					_, _ = w.Write([]byte(`{"branches":[{"name":"master","isMain":true}]}`))
+				case "/api/project_links/search":
+					_, _ = w.Write([]byte(`{"links":[{"id":"1","type":"homepage","url":"https://example.com"},{"id":"2","name":"CI","type":"custom","url":"https://ci.example.com/payments"}]}`))
+				case "/api/project_links/create":
+					got = append(got, request{Path: r.URL.Path, Params: r.Form})
+					_, _ = w.Write([]byte(`{"link":{"id":"3"}}`))
+				default:
+					got = append(got, request{Path: r.URL.Path, Params: r.Form})
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), qualityGateClient: sonar.NewQualityGateClient(options), branchClient: sonar.NewProjectBranchClient(options), linkClient: sonar.NewProjectLinkClient(options), logger: logging.NewNopLogger()}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateProjectDeleted(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		disable bool
+		name    string
+		missing string
+		failing string
+		want    []string
+		wantErr bool
+	}{
+		"DeletedBeforeUpdate": {
+			reason:  "A project deleted between Observe and Update should be created again.",
+			missing: "/api/projects/search",
+			want:    []string{"/api/projects/create name=Name&organization=org&project=key&visibility=public"},
+		},
+		"DeletedDuringUpdate": {
+			reason:  "A project deleted while its visibility is updated should be created again.",
+			missing: "/api/projects/update_visibility",
+			want: []string{
+				"/api/projects/update_visibility project=key&visibility=public",
+				"/api/projects/create name=Name&organization=org&project=key&visibility=public",
+			},
+		},
+		"DeletedDuringUpdateAfterFailure": {
+			reason:  "A failure of an update before the project is created again should still be returned.",
+			name:    "Renamed",
+			missing: "/api/projects/update_visibility",
+			failing: "/api/projects/update_name",
+			want: []string{
+				"/api/projects/update_name name=Renamed&project=key",
+				"/api/projects/update_visibility project=key&visibility=public",
+				"/api/projects/create name=Renamed&organization=org&project=key&visibility=public",
+			},
+			wantErr: true,
+		},
+		"RecreationDisabled": {
+			reason:  "A project deleted between Observe and Update should not be created again when recreation is disabled.",
+			disable: true,
+			missing: "/api/projects/search",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/projects/search":
+					if r.URL.Path == tc.missing {
+						searchResponse()(w, r)
+						return
+					}
+					searchResponse(sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"})(w, r)
+					return
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+					return
+				}
+				got = append(got, r.URL.Path+" "+r.Form.Encode())
+				switch r.URL.Path {
+				case tc.missing:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"errors":[{"msg":"Project 'key' not found"}]}`))
+				case tc.failing:
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"errors":[{"msg":"Invalid name"}]}`))
+				case "/api/projects/create":
+					_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+				}
+			}))
+			defer srv.Close()
+
+			name := "Name"
+			if tc.name != "" {
+				name = tc.name
+			}
+			e := external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), disableRecreation: tc.disable, logger: logging.NewNopLogger()}
+			u, err := e.Update(context.Background(), project(withKey("key"), withName(name), withVisibility("public")))
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Fatalf("\n%s\ne.Update(...): -want error, +got error:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if !tc.wantErr && u.ConnectionDetails[keyProjectKey] == nil {
+				t.Errorf("\n%s\ne.Update(...): want connection details of the created project", tc.reason)
+			}
+		})
+	}
+}
+
+func TestCreationGracePeriod(t *testing.T) {
+	type want struct {
+		exists   bool
+		requests []string
+		err      bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.Project
+		want   want
+	}{
+		"RecentlyCreated": {
+			reason: "A project created within the grace period that is not found yet should be reported as not existing, which the reconciler waits for, and not be created again by Update.",
+			cr:     project(withKey("key"), withName("Name"), withVisibility("public"), withCreated(time.Now().Add(-10*time.Second))),
+			want: want{
+				err: true,
+			},
+		},
+		"GracePeriodElapsed": {
+			reason: "A project created before the grace period that is not found should be created again.",
+			cr:     project(withKey("key"), withName("Name"), withVisibility("public"), withCreated(time.Now().Add(-time.Hour))),
+			want: want{
+				requests: []string{"/api/projects/create name=Name&organization=org&project=key&visibility=public"},
+			},
+		},
+		"NeverCreated": {
+			reason: "A project that was never created should be created.",
+			cr:     project(withKey("key"), withName("Name"), withVisibility("public")),
+			want: want{
+				requests: []string{"/api/projects/create name=Name&organization=org&project=key&visibility=public"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/projects/search":
+					// The project was not indexed yet.
+					searchResponse()(w, r)
+					return
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+					return
+				}
+				got = append(got, r.URL.Path+" "+r.Form.Encode())
+				if r.URL.Path == "/api/projects/create" {
+					_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+				}
+			}))
+			defer srv.Close()
+
+			e := external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.exists, o.ResourceExists); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want exists, +got exists:\n%s\n", tc.reason, diff)
+			}
+
+			// A project that is missing by the time it is updated is created
+			// again by Update, unless it was created recently.
+			_, err = e.Update(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("\n%s\ne.Update(...): -want error, +got error:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeletionProtection(t *testing.T) {
+	recently := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+
+	type want struct {
+		err      string
+		requests []string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		days     string
+		analyzed time.Time
+		want     want
+	}{
+		"Unguarded": {
+			reason:   "A project without the annotation should be deleted, however recently it was analyzed.",
+			analyzed: recently,
+			want:     want{requests: []string{"/api/projects/delete project=key"}},
+		},
+		"AnalyzedRecently": {
+			reason:   "A guarded project analyzed within the protected days should not be deleted.",
+			days:     "30",
+			analyzed: recently,
+			want: want{err: fmt.Sprintf("project was analyzed %s, within the 30 days of its %s annotation: remove the annotation to delete it",
+				recently.UTC().Format(time.RFC3339), v1alpha1.AnnotationKeyDeletionProtectionDays)},
+		},
+		"AnalyzedLongAgo": {
+			reason:   "A guarded project analyzed before the protected days should be deleted.",
+			days:     "30",
+			analyzed: longAgo,
+			want:     want{requests: []string{"/api/projects/delete project=key"}},
+		},
+		"NeverAnalyzed": {
+			reason: "A guarded project that was never analyzed has no history to lose and should be deleted.",
+			days:   "30",
+			want:   want{requests: []string{"/api/projects/delete project=key"}},
+		},
+		"InvalidDays": {
+			reason:   "An annotation that is not a positive number of days should not be ignored.",
+			days:     "a month",
+			analyzed: recently,
+			want:     want{err: errProtectionDays},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				if r.URL.Path == "/api/projects/search" {
+					searchResponse(sonar.Project{Organization: "org", Key: "key", LastAnalysisDate: sonar.SonarTime{Time: tc.analyzed}})(w, r)
+					return
+				}
+				got = append(got, r.URL.Path+" "+r.Form.Encode())
+			}))
+			defer srv.Close()
+
+			cr := project(withKey("key"))
+			if tc.days != "" {
+				meta.AddAnnotations(cr, map[string]string{v1alpha1.AnnotationKeyDeletionProtectionDays: tc.days})
+			}
+
+			e := external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+			err := e.Delete(context.Background(), cr)
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if diff := cmp.Diff(tc.want.err, gotErr); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffProject(t *testing.T) {
+	// all sets every field that diffProject compares, and observed
+	// returns a project matching it.
+	all := []projectModifier{
+		withName("Name"), withVisibility("private"), withTags("team"), withQualityGate("strict"),
+		withNewCodePeriod("NUMBER_OF_DAYS", "30"), withMainBranch("main"), withLinks("docs", "https://docs.example.org"),
+		withProfileAssociation("go", "strict"), withProtectedBranches("release"), withExclusions("vendor/**"),
+	}
+	observed := func() observedProject {
+		return observedProject{
+			project:       sonar.Project{Name: "Name", Visibility: "private", Tags: []string{"team"}},
+			qualityGate:   "strict",
+			newCodePeriod: sonar.NewCodePeriod{Type: "NUMBER_OF_DAYS", Value: "30"},
+			mainBranch:    "main",
+			links:         []sonar.ProjectLink{{Id: "1", Name: "docs", Type: sonar.ProjectLinkTypeCustom, Url: "https://docs.example.org"}},
+			profiles:      []sonar.QualityProfile{{Language: "go", Name: "strict"}},
+			branches:      []sonar.Branch{{Name: "main", IsMain: true}, {Name: "release", ExcludedFromPurge: true}},
+			exclusions:    []sonar.Setting{{Key: settingExclusions, Values: []string{"vendor/**"}}},
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		cr       []projectModifier
+		observed func(o *observedProject)
+		want     []string
+	}{
+		"UpToDate": {
+			reason: "A project matching every managed field should be up to date.",
+		},
+		"Unmanaged": {
+			reason: "Fields left unset should never differ.",
+			cr: []projectModifier{func(cr *v1alpha1.Project) {
+				cr.Spec.ForProvider = v1alpha1.ProjectParameters{Organization: "org", Key: "key"}
+			}},
+			observed: func(o *observedProject) {
+				*o = observedProject{project: sonar.Project{Name: "Other", Visibility: "public"}}
+			},
+		},
+		"Name": {
+			reason:   "A renamed project should differ in its name only.",
+			observed: func(o *observedProject) { o.project.Name = "Other" },
+			want:     []string{"name"},
+		},
+		"Visibility": {
+			reason:   "A project made public should differ in its visibility only.",
+			observed: func(o *observedProject) { o.project.Visibility = "public" },
+			want:     []string{"visibility"},
+		},
+		"Tags": {
+			reason:   "A project with other tags should differ in its tags only.",
+			observed: func(o *observedProject) { o.project.Tags = []string{"other"} },
+			want:     []string{"tags"},
+		},
+		"QualityGate": {
+			reason:   "A project using another quality gate should differ in its quality gate only.",
+			observed: func(o *observedProject) { o.qualityGate = "Sonar way" },
+			want:     []string{"qualityGate"},
+		},
+		"NewCodePeriod": {
+			reason:   "A project inheriting its new code period should differ in its new code period only.",
+			observed: func(o *observedProject) { o.newCodePeriod.Inherited = true },
+			want:     []string{"newCodePeriod"},
+		},
+		"MainBranch": {
+			reason:   "A project whose main branch was renamed should differ in its main branch only.",
+			observed: func(o *observedProject) { o.mainBranch = "master" },
+			want:     []string{"mainBranch"},
+		},
+		"Links": {
+			reason:   "A project whose link points elsewhere should differ in its links only.",
+			observed: func(o *observedProject) { o.links[0].Url = "https://wiki.example.org" },
+			want:     []string{"links"},
+		},
+		"ProfileAssociations": {
+			reason:   "A project using another quality profile should differ in its profile associations only.",
+			observed: func(o *observedProject) { o.profiles[0].Name = "Sonar way" },
+			want:     []string{"profileAssociations"},
+		},
+		"ProtectedBranches": {
+			reason:   "A project whose listed branch is no longer protected should differ in its protected branches only.",
+			observed: func(o *observedProject) { o.branches[1].ExcludedFromPurge = false },
+			want:     []string{"protectedBranches"},
+		},
+		"Exclusions": {
+			reason:   "A project with other exclusions should differ in its exclusions only.",
+			observed: func(o *observedProject) { o.exclusions[0].Values = []string{"build/**"} },
+			want:     []string{"exclusions"},
+		},
+		"BadgeTokenRotation": {
+			reason: "A project whose badge token rotation changed should differ in its badge token only.",
+			cr:     []projectModifier{withBadgeTokenRotation("2024-01")},
+			want:   []string{"badgeTokenRotation"},
+		},
+		"GenerateAnalysisToken": {
+			reason: "A project whose analysis token is yet to be generated should differ in its analysis token only.",
+			cr:     []projectModifier{withGenerateAnalysisToken()},
+			want:   []string{"generateAnalysisToken"},
+		},
+		"Several": {
+			reason: "Every field that differs should be reported.",
+			observed: func(o *observedProject) {
+				o.project.Name = "Other"
+				o.qualityGate = "Sonar way"
+			},
+			want: []string{"name", "qualityGate"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := project(append(append([]projectModifier{}, all...), tc.cr...)...)
+			o := observed()
+			if tc.observed != nil {
+				tc.observed(&o)
+			}
+
+			got := diffProject(cr, o, nil)
+			if diff := cmp.Diff(tc.want, got.fields()); diff != "" {
+				t.Errorf("\n%s\ndiffProject(...): -want fields, +got fields:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(len(tc.want) == 0, got.upToDate()); diff != "" {
+				t.Errorf("\n%s\ndiffProject(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateOnlyChangedFields(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/api/projects/search":
+			searchResponse(sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private", Tags: []string{"team"}})(w, r)
+			return
+		case "/api/qualitygates/get_by_project":
+			_, _ = w.Write([]byte(`{"qualityGate":{"name":"Sonar way"}}`))
+			return
+		case "/api/project_badges/token":
+			_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+			return
+		}
+		got = append(got, r.URL.Path+" "+r.Form.Encode())
+	}))
+	defer srv.Close()
+
+	options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+	e := external{projectClient: sonar.NewProjectClient(options), qualityGateClient: sonar.NewQualityGateClient(options), logger: logging.NewNopLogger()}
+	cr := project(withKey("key"), withName("Name"), withVisibility("private"), withTags("team"), withQualityGate("strict"))
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %v", err)
+	}
+
+	want := []string{"/api/qualitygates/select gateName=strict&organization=org&projectKey=key"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Update(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestCreateTemplateProject(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		template     string
+		organization string
+		failing      string
+		existing     bool
+		cr           *v1alpha1.Project
+		want         []string
+		wantErr      bool
+	}{
+		"Applied": {
+			reason:   "The permission template and quality gate of the template project should be applied to the new project.",
+			template: "payments-template",
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-template")),
+			want: []string{
+				"/api/projects/create name=Checkout&organization=org&project=checkout",
+				"/api/permissions/apply_template organization=org&projectKey=checkout&templateId=AXT2",
+				"/api/qualitygates/select gateName=strict&organization=org&projectKey=checkout",
+			},
+		},
+		"QualityGateSet": {
+			reason:   "The quality gate of the project should take precedence over the one of the template project.",
+			template: "payments-template",
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-template"), withQualityGate("relaxed")),
+			want: []string{
+				"/api/projects/create name=Checkout&organization=org&project=checkout",
+				"/api/permissions/apply_template organization=org&projectKey=checkout&templateId=AXT2",
+				"/api/qualitygates/select gateName=relaxed&organization=org&projectKey=checkout",
+			},
+		},
+		"DefaultPermissionTemplate": {
+			reason:   "A template project matched by no permission template has the default one, which the new project already has.",
+			template: "legacy-template",
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("legacy-template")),
+			want: []string{
+				"/api/projects/create name=Checkout&organization=org&project=checkout",
+				"/api/qualitygates/select gateName=strict&organization=org&projectKey=checkout",
+			},
+		},
+		"DefaultOrganization": {
+			reason:       "The template project should be applied in the default organization of the ProviderConfig when the project sets none.",
+			template:     "payments-template",
+			organization: "org",
+			cr:           project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-template"), withOrganization("")),
+			want: []string{
+				"/api/projects/create name=Checkout&project=checkout",
+				"/api/permissions/apply_template organization=org&projectKey=checkout&templateId=AXT2",
+				"/api/qualitygates/select gateName=strict&organization=org&projectKey=checkout",
+			},
+		},
+		"RolledBack": {
+			reason:   "A project that the template project could not be applied to should be deleted, so that it is created and the template applied again.",
+			template: "payments-template",
+			failing:  "/api/permissions/apply_template",
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-template")),
+			want: []string{
+				"/api/projects/create name=Checkout&organization=org&project=checkout",
+				"/api/permissions/apply_template organization=org&projectKey=checkout&templateId=AXT2",
+				"/api/projects/delete project=checkout",
+			},
+			wantErr: true,
+		},
+		"TakenOver": {
+			reason:   "A project that already existed should be taken over without applying the template project to it, and not be deleted when that would fail.",
+			template: "payments-template",
+			failing:  "/api/permissions/apply_template",
+			existing: true,
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-template")),
+			want: []string{
+				"/api/projects/create name=Checkout&organization=org&project=checkout",
+			},
+		},
+		"MissingTemplateProject": {
+			reason:   "A project should not be created from a template project that does not exist.",
+			template: "payments-template",
+			cr:       project(withKey("checkout"), withName("Checkout"), withTemplateProject("payments-templat")),
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/projects/search":
+					switch p := r.Form.Get("projects"); {
+					case p == tc.template:
+						searchResponse(sonar.Project{Organization: "org", Key: tc.template})(w, r)
+					case p == "checkout" && tc.existing:
+						searchResponse(sonar.Project{Organization: "org", Key: "checkout", Name: "Checkout"})(w, r)
+					default:
+						searchResponse()(w, r)
+					}
+					return
+				case "/api/permissions/search_templates":
+					_, _ = w.Write([]byte(`{"permissionTemplates":[{"id":"AXT1","name":"default"},{"id":"AXT2","name":"payments","projectKeyPattern":"payments-.*"}]}`))
+					return
+				case "/api/qualitygates/get_by_project":
+					_, _ = w.Write([]byte(`{"qualityGate":{"id":"2","name":"strict"}}`))
+					return
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+					return
+				}
+				got = append(got, r.URL.Path+" "+r.Form.Encode())
+				if r.URL.Path == tc.failing {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				if r.URL.Path == "/api/projects/create" {
+					if tc.existing {
+						w.WriteHeader(http.StatusBadRequest)
+						_, _ = w.Write([]byte(`{"errors":[{"msg":"Could not create Project, key already exists: checkout"}]}`))
+						return
+					}
+					_, _ = w.Write([]byte(`{"project":{"key":"checkout","name":"Checkout"}}`))
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{
+				projectClient:            sonar.NewProjectClient(options),
+				qualityGateClient:        sonar.NewQualityGateClient(options),
+				permissionTemplateClient: sonar.NewPermissionTemplateClient(options),
+				organization:             tc.organization,
+				logger:                   logging.NewNopLogger(),
+			}
+			_, err := e.Create(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Fatalf("\n%s\ne.Create(...): -want error, +got error:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdatePartialFailure(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		failing []string
+		want    []string
+		wantErr []string
+	}{
+		"OneFails": {
+			reason:  "A failing rename should not stop the tags and quality gate from being updated.",
+			failing: []string{"/api/projects/update_name"},
+			want: []string{
+				"/api/projects/update_name name=Name&project=key",
+				"/api/project_tags/set project=key&tags=team",
+				"/api/qualitygates/select gateName=strict&organization=org&projectKey=key",
+			},
+			wantErr: []string{errUpdateProject},
+		},
+		"SeveralFail": {
+			reason:  "Every failing step should be returned, while the others are still updated.",
+			failing: []string{"/api/projects/update_name", "/api/qualitygates/select"},
+			want: []string{
+				"/api/projects/update_name name=Name&project=key",
+				"/api/project_tags/set project=key&tags=team",
+				"/api/qualitygates/select gateName=strict&organization=org&projectKey=key",
+			},
+			wantErr: []string{errUpdateProject, errSelectQualityGate},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/projects/search":
+					searchResponse(sonar.Project{Organization: "org", Key: "key", Name: "Old", Visibility: "private"})(w, r)
+					return
+				case "/api/qualitygates/get_by_project":
+					_, _ = w.Write([]byte(`{"qualityGate":{"name":"Sonar way"}}`))
+					return
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+					return
+				}
+				got = append(got, r.URL.Path+" "+r.Form.Encode())
+				for _, path := range tc.failing {
+					if r.URL.Path == path {
+						w.WriteHeader(http.StatusBadRequest)
+						_, _ = w.Write([]byte(`{"errors":[{"msg":"Request rejected"}]}`))
+					}
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := external{projectClient: sonar.NewProjectClient(options), qualityGateClient: sonar.NewQualityGateClient(options), logger: logging.NewNopLogger()}
+			cr := project(withKey("key"), withName("Name"), withVisibility("private"), withTags("team"), withQualityGate("strict"))
+			_, err := e.Update(context.Background(), cr)
+			if err == nil {
+				t.Fatalf("\n%s\ne.Update(...): want an error", tc.reason)
+			}
+			for _, msg := range tc.wantErr {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("\n%s\ne.Update(...): want error to contain %q, got %q", tc.reason, msg, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateMainBranch(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		got = append(got, r.URL.Path+" "+r.Form.Encode())
+		switch r.URL.Path {
+		case "/api/projects/create":
+			_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+		case "/api/project_badges/token":
+			_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+		}
+	}))
+	defer srv.Close()
+
+	options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+	e := external{projectClient: sonar.NewProjectClient(options), branchClient: sonar.NewProjectBranchClient(options), logger: logging.NewNopLogger()}
+	cr := project(withKey("key"), withName("Name"), withMainBranch("main"))
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	// The project is created with its main branch, which needs no rename.
+	want := []string{
+		"/api/projects/create mainBranch=main&name=Name&organization=org&project=key",
+		"/api/project_badges/token project=key",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Create(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestCreateSetsExternalName(t *testing.T) {
+	var created string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/create":
+			created = r.FormValue("project")
+			_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+		case "/api/project_badges/token":
+			_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+		}
+	}))
+	defer srv.Close()
+
+	e := external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+	cr := project(withKey("key"), withName("Name"))
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("key", created); diff != "" {
+		t.Errorf("e.Create(...): -want created key, +got created key:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("key", meta.GetExternalName(cr)); diff != "" {
+		t.Errorf("e.Create(...): -want external name, +got external name:\n%s\n", diff)
+	}
+}
+
+func TestConnectionDetails(t *testing.T) {
+	observe := func(ctx context.Context, e *external, cr *v1alpha1.Project) (managed.ConnectionDetails, error) {
+		o, err := e.Observe(ctx, cr)
+		return o.ConnectionDetails, err
+	}
+	published := map[string][]byte{"projectKey": []byte("key"), "badgeToken": []byte("badge-token")}
+
+	cases := map[string]struct {
+		reason string
+		secret map[string][]byte
+		call   func(ctx context.Context, e *external, cr *v1alpha1.Project) (managed.ConnectionDetails, error)
+		badge  bool
+	}{
+		"Observe": {
+			reason: "Observe should publish the connection details of an existing project so the secret stays accurate.",
+			secret: map[string][]byte{"projectKey": []byte("key")},
+			call:   observe,
+			badge:  true,
+		},
+		"ObserveNoSecret": {
+			reason: "Observe should publish the badge token when the connection secret does not exist yet.",
+			call:   observe,
+			badge:  true,
+		},
+		"ObserveBadgeTokenPublished": {
+			reason: "Observe should not fetch the badge token again once the connection secret holds it.",
+			secret: published,
+			call:   observe,
+		},
+		"Create": {
+			reason: "Create should publish the connection details of the created project.",
+			secret: published,
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Project) (managed.ConnectionDetails, error) {
+				c, err := e.Create(ctx, cr)
+				return c.ConnectionDetails, err
+			},
+			badge: true,
+		},
+		"Update": {
+			reason: "Update should publish the connection details of the updated project, including a renewed badge token.",
+			secret: published,
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Project) (managed.ConnectionDetails, error) {
+				u, err := e.Update(ctx, cr)
+				return u.ConnectionDetails, err
+			},
+			badge: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			observed := sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"}
+			search := searchResponse(observed)
+			fetched := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/projects/create":
+					_ = json.NewEncoder(w).Encode(map[string]sonar.Project{"project": observed})
+				case "/api/project_badges/token":
+					fetched = true
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.secret == nil {
+						return errors.New("secret not found")
+					}
+					obj.(*corev1.Secret).Data = tc.secret
+					return nil
+				},
+			}
+			e := &external{kube: kube, projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+			cr := project(withKey("key"), withName("Name"), withVisibility("private"), func(cr *v1alpha1.Project) {
+				cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Namespace: "crossplane-system", Name: "payments-sonar"})
+			})
+			got, err := tc.call(context.Background(), e, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+
+			want := managed.ConnectionDetails{
+				"projectKey":   []byte("key"),
+				"projectUrl":   []byte(srv.URL + "/dashboard?id=key"),
+				"organization": []byte("org"),
+			}
+			if tc.badge {
+				want["badgeToken"] = []byte("badge-token")
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want, +got:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.badge, fetched); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want badge token fetched, +got badge token fetched:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultOrganization(t *testing.T) {
+	type want struct {
+		organization string
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Project
+		want   want
+	}{
+		"Inherited": {
+			reason: "A project without an organization should use the default of the ProviderConfig.",
+			mg:     project(withOrganization(""), withKey("key"), withName("Name"), withVisibility("private")),
+			want:   want{organization: "default"},
+		},
+		"Overridden": {
+			reason: "The organization of a project should take precedence over the default of the ProviderConfig.",
+			mg:     project(withOrganization("org"), withKey("key"), withName("Name"), withVisibility("private")),
+			want:   want{organization: "org"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var searched string
+			search := searchResponse(sonar.Project{Organization: tc.want.organization, Key: "key", Name: "Name", Visibility: "private"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					searched = r.FormValue("organization")
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL, Organization: "default"}
+			e := &external{projectClient: sonar.NewProjectClient(options), organization: "default", logger: logging.NewNopLogger()}
+			o, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.organization, searched); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want searched organization, +got searched organization:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.organization, string(o.ConnectionDetails[keyOrganization])); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want published organization, +got published organization:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateProjectLimitReached(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors":[{"msg":"This organization cannot use project private"}]}`))
+	}))
+	defer srv.Close()
+
+	e := &external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+	_, err := e.Create(context.Background(), project(withKey("org_key"), withVisibility("private")))
+
+	if !errors.Is(err, sonar.ErrProjectLimitReached) {
+		t.Errorf("e.Create(...): want ErrProjectLimitReached, got %v", err)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), errProjectLimit) {
+		t.Errorf("e.Create(...): want an error starting with %q, got %v", errProjectLimit, err)
+	}
+}
+
+func TestBadgeTokenRotation(t *testing.T) {
+	observed := sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"}
+	token := "old-token"
+	renewed := 0
+	search := searchResponse(observed)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/project_badges/token":
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": token})
+		case "/api/project_badges/renew_token":
+			renewed++
+			token = "new-token"
+		default:
+			search(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	e := &external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+	cr := project(withKey("key"), withName("Name"), withVisibility("private"), withBadgeTokenRotation("2022-11-10"))
+
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a changed badgeTokenRotation should be reported as not up to date")
+	}
+
+	u, err := e.Update(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(1, renewed); diff != "" {
+		t.Errorf("e.Update(...): -want renewals, +got renewals:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("new-token", string(u.ConnectionDetails["badgeToken"])); diff != "" {
+		t.Errorf("e.Update(...): -want badge token, +got badge token:\n%s\n", diff)
+	}
+
+	o, err = e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if !o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a renewed badge token should be reported as up to date")
+	}
+}
+
+func TestAnalysisToken(t *testing.T) {
+	observed := sonar.Project{Organization: "org", Key: "key", Name: "Name", Visibility: "private"}
+	var got []string
+	search := searchResponse(observed)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/api/projects/create":
+			_, _ = w.Write([]byte(`{"project":{"key":"key","name":"Name"}}`))
+		case "/api/project_badges/token":
+			_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+		case "/api/user_tokens/search":
+			_, _ = w.Write([]byte(`{"login":"ci","userTokens":[{"name":"crossplane-key","type":"PROJECT_ANALYSIS_TOKEN"}]}`))
+		case "/api/user_tokens/generate":
+			got = append(got, r.URL.Path+" "+r.Form.Encode())
+			_, _ = w.Write([]byte(`{"name":"crossplane-key","token":"squ_analysis","type":"PROJECT_ANALYSIS_TOKEN","projectKey":"key"}`))
+		case "/api/user_tokens/revoke", "/api/projects/delete":
+			got = append(got, r.URL.Path+" "+r.Form.Encode())
+		default:
+			search(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+	e := &external{projectClient: sonar.NewProjectClient(options), userTokenClient: sonar.NewUserTokenClient(options), logger: logging.NewNopLogger()}
+
+	t.Run("Create", func(t *testing.T) {
+		got = nil
+		cr := project(withKey("key"), withName("Name"), withVisibility("private"), withGenerateAnalysisToken())
+
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %v", err)
+		}
+		// The token is generated by the next Update, whose status is
+		// persisted, rather than by Create, whose status is not.
+		if diff := cmp.Diff([]string(nil), got); diff != "" {
+			t.Errorf("e.Create(...): -want requests, +got requests:\n%s\n", diff)
+		}
+
+		o, err := e.Observe(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("e.Observe(...): unexpected error: %v", err)
+		}
+		if o.ResourceUpToDate {
+			t.Errorf("e.Observe(...): a created project without a generated analysis token should be reported as not up to date")
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		got = nil
+		cr := project(withKey("key"), withName("Name"), withVisibility("private"), withGenerateAnalysisToken())
+
+		o, err := e.Observe(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("e.Observe(...): unexpected error: %v", err)
+		}
+		if o.ResourceUpToDate {
+			t.Errorf("e.Observe(...): a project without a generated analysis token should be reported as not up to date")
+		}
+
+		u, err := e.Update(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("e.Update(...): unexpected error: %v", err)
+		}
+		if diff := cmp.Diff("squ_analysis", string(u.ConnectionDetails[keyAnalysisToken])); diff != "" {
+			t.Errorf("e.Update(...): -want analysis token, +got analysis token:\n%s\n", diff)
+		}
+		want := []string{
+			"/api/user_tokens/revoke name=crossplane-key",
+			"/api/user_tokens/generate name=crossplane-key&projectKey=key&type=PROJECT_ANALYSIS_TOKEN",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("e.Update(...): -want requests, +got requests:\n%s\n", diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		got = nil
+		cr := project(withKey("key"), withGenerateAnalysisToken(), withAnalysisTokenName("crossplane-key"))
+
+		if err := e.Delete(context.Background(), cr); err != nil {
+			t.Fatalf("e.Delete(...): unexpected error: %v", err)
+		}
+		want := []string{
+			"/api/user_tokens/revoke name=crossplane-key",
+			"/api/projects/delete project=key",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+		}
+	})
+
+	t.Run("DeleteUnrecorded", func(t *testing.T) {
+		got = nil
+		cr := project(withKey("key"), withGenerateAnalysisToken())
+
+		if err := e.Delete(context.Background(), cr); err != nil {
+			t.Fatalf("e.Delete(...): unexpected error: %v", err)
+		}
+		want := []string{
+			"/api/user_tokens/revoke name=crossplane-key",
+			"/api/projects/delete project=key",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+		}
+	})
+}
+
+func TestDefaultTags(t *testing.T) {
+	type want struct {
+		upToDate bool
+		tags     []string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		defaults []string
+		observed []string
+		cr       *v1alpha1.Project
+		want     want
+	}{
+		"Merged": {
+			reason:   "The default tags should be set along with the tags of the project.",
+			defaults: []string{"org:acme"},
+			observed: []string{"team:payments"},
+			cr:       project(withKey("key"), withTags("team:payments")),
+			want:     want{tags: []string{"team:payments", "org:acme"}},
+		},
+		"AlreadyListed": {
+			reason:   "A default tag the project lists too should be set once.",
+			defaults: []string{"org:acme"},
+			cr:       project(withKey("key"), withTags("org:acme", "team:payments")),
+			want:     want{tags: []string{"org:acme", "team:payments"}},
+		},
+		"UnmanagedTags": {
+			reason:   "The default tags should be added to a project that leaves its tags unmanaged, keeping its other tags.",
+			defaults: []string{"org:acme"},
+			observed: []string{"legacy"},
+			cr:       project(withKey("key")),
+			want:     want{tags: []string{"legacy", "org:acme"}},
+		},
+		"DefaultRemoved": {
+			reason:   "A default tag removed outside of Crossplane should be reported as drift and set again.",
+			defaults: []string{"org:acme", "cost:platform"},
+			observed: []string{"team:payments", "org:acme"},
+			cr:       project(withKey("key"), withTags("team:payments")),
+			want:     want{tags: []string{"team:payments", "org:acme", "cost:platform"}},
+		},
+		"UpToDate": {
+			reason:   "A project that carries its tags and the default tags, in any order, should be up to date.",
+			defaults: []string{"org:acme"},
+			observed: []string{"org:acme", "team:payments"},
+			cr:       project(withKey("key"), withTags("team:payments")),
+			want:     want{upToDate: true},
+		},
+		"NoDefaults": {
+			reason:   "A project that leaves its tags unmanaged should be up to date when there are no default tags.",
+			observed: []string{"legacy"},
+			cr:       project(withKey("key")),
+			want:     want{upToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key", Tags: tc.observed})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/project_tags/set":
+					got = strings.Split(r.FormValue("tags"), ",")
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			e := &external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), defaultTags: tc.defaults, logger: logging.NewNopLogger()}
+
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, o.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+			if o.ResourceUpToDate {
+				return
+			}
+
+			if _, err := e.Update(context.Background(), tc.cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.tags, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want tags, +got tags:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProfileAssociations(t *testing.T) {
+	type want struct {
+		upToDate   bool
+		requests   []string
+		associated map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		used   map[string]string
+		cr     *v1alpha1.Project
+		want   want
+	}{
+		"Add": {
+			reason: "A profile should be associated with a project that uses the default profile for its language.",
+			cr:     project(withKey("key"), withProfileAssociation("java", "strict")),
+			want: want{
+				requests:   []string{"/api/qualityprofiles/add_project language=java&organization=org&project=key&qualityProfile=strict"},
+				associated: map[string]string{"java": "strict"},
+			},
+		},
+		"Change": {
+			reason: "A profile should be associated with a project that uses another profile for its language.",
+			used:   map[string]string{"java": "strict"},
+			cr:     project(withKey("key"), withProfileAssociation("java", "stricter"), withObservedProfileAssociation("java", "strict")),
+			want: want{
+				requests:   []string{"/api/qualityprofiles/add_project language=java&organization=org&project=key&qualityProfile=stricter"},
+				associated: map[string]string{"java": "stricter"},
+			},
+		},
+		"Remove": {
+			reason: "An association that is no longer desired should be removed, reverting the language to the default profile.",
+			used:   map[string]string{"java": "strict", "js": "lenient"},
+			cr:     project(withKey("key"), withProfileAssociation("js", "lenient"), withObservedProfileAssociation("java", "strict"), withObservedProfileAssociation("js", "lenient")),
+			want: want{
+				requests:   []string{"/api/qualityprofiles/remove_project language=java&organization=org&project=key&qualityProfile=strict"},
+				associated: map[string]string{"js": "lenient"},
+			},
+		},
+		"RemoveChangedOutOfBand": {
+			reason: "An association that is no longer desired should be left alone, and forgotten, when the project already uses another profile.",
+			used:   map[string]string{"java": "other"},
+			cr:     project(withKey("key"), withObservedProfileAssociation("java", "strict")),
+			want: want{
+				upToDate: true,
+			},
+		},
+		"UpToDate": {
+			reason: "A project that uses the desired profiles should be reported as up to date.",
+			used:   map[string]string{"java": "strict"},
+			cr:     project(withKey("key"), withProfileAssociation("java", "strict"), withObservedProfileAssociation("java", "strict")),
+			want: want{
+				upToDate:   true,
+				associated: map[string]string{"java": "strict"},
+			},
+		},
+		"RecordedAfterCreate": {
+			reason: "Associations made by Create, whose status is lost, should be recorded once the project uses them.",
+			used:   map[string]string{"java": "strict"},
+			cr:     project(withKey("key"), withProfileAssociation("java", "strict")),
+			want: want{
+				upToDate:   true,
+				associated: map[string]string{"java": "strict"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/qualityprofiles/search":
+					profiles := []sonar.QualityProfile{}
+					for _, language := range []string{"java", "js"} {
+						p := sonar.QualityProfile{Name: "Sonar way", Language: language, IsDefault: true}
+						if name, ok := tc.used[language]; ok {
+							p = sonar.QualityProfile{Name: name, Language: language}
+						}
+						profiles = append(profiles, p)
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"profiles": profiles})
+				case "/api/qualityprofiles/add_project", "/api/qualityprofiles/remove_project":
+					got = append(got, r.URL.Path+" "+r.Form.Encode())
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := &external{projectClient: sonar.NewProjectClient(options), profileClient: sonar.NewQualityProfileClient(options), logger: logging.NewNopLogger()}
+
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, o.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+			if o.ResourceUpToDate {
+				if diff := cmp.Diff(tc.want.associated, tc.cr.Status.AtProvider.ProfileAssociations); diff != "" {
+					t.Errorf("\n%s\ne.Observe(...): -want associations, +got associations:\n%s\n", tc.reason, diff)
+				}
+				return
+			}
+
+			if _, err := e.Update(context.Background(), tc.cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.associated, tc.cr.Status.AtProvider.ProfileAssociations); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want associations, +got associations:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProtectedBranches(t *testing.T) {
+	type want struct {
+		upToDate  bool
+		requests  []string
+		protected []string
+	}
+
+	branches := `{"branches":[` +
+		`{"name":"main","isMain":true,"excludedFromPurge":true},` +
+		`{"name":"release/1.x","isMain":false,"excludedFromPurge":true},` +
+		`{"name":"release/2.x","isMain":false,"excludedFromPurge":false}]}`
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.Project
+		want   want
+	}{
+		"Protect": {
+			reason: "A branch that is not protected should be protected from automatic deletion.",
+			cr:     project(withKey("key"), withProtectedBranches("release/1.x", "release/2.x")),
+			want: want{
+				requests:  []string{"/api/project_branches/set_automatic_deletion_protection branch=release%2F2.x&project=key&value=true"},
+				protected: []string{"release/1.x", "release/2.x"},
+			},
+		},
+		"Unprotect": {
+			reason: "A branch protected earlier, but no longer desired, should be unprotected.",
+			cr:     project(withKey("key"), withObservedProtectedBranches("release/1.x")),
+			want: want{
+				requests: []string{"/api/project_branches/set_automatic_deletion_protection branch=release%2F1.x&project=key&value=false"},
+			},
+		},
+		"MainBranchKept": {
+			reason: "The main branch should not be unprotected, since it is always kept.",
+			cr:     project(withKey("key"), withObservedProtectedBranches("main")),
+			want:   want{upToDate: true},
+		},
+		"NotAnalyzed": {
+			reason: "A branch that does not exist yet should not be protected until it was analyzed.",
+			cr:     project(withKey("key"), withProtectedBranches("release/1.x", "release/3.x")),
+			want:   want{upToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch r.URL.Path {
+				case "/api/project_branches/list":
+					_, _ = w.Write([]byte(branches))
+				case "/api/project_branches/set_automatic_deletion_protection":
+					got = append(got, r.URL.Path+" "+r.Form.Encode())
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := &external{projectClient: sonar.NewProjectClient(options), branchClient: sonar.NewProjectBranchClient(options), logger: logging.NewNopLogger()}
+
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, o.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+			if o.ResourceUpToDate {
+				return
+			}
+
+			if _, err := e.Update(context.Background(), tc.cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.protected, tc.cr.Status.AtProvider.ProtectedBranches); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want protected branches, +got protected branches:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExclusions(t *testing.T) {
+	type want struct {
+		upToDate bool
+		requests []string
+		observed []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		set    map[string][]string
+		cr     *v1alpha1.Project
+		want   want
+	}{
+		"Set": {
+			reason: "Exclusions that are not set on a project should be set.",
+			cr:     project(withKey("key"), withExclusions("**/vendor/**", "**/*.pb.go")),
+			want: want{
+				requests: []string{"/api/settings/set component=key&key=sonar.exclusions&values=%2A%2A%2Fvendor%2F%2A%2A&values=%2A%2A%2F%2A.pb.go"},
+				observed: []string{"**/vendor/**", "**/*.pb.go"},
+			},
+		},
+		"Clear": {
+			reason: "Exclusions that were set earlier, but are no longer desired, should be reset.",
+			set:    map[string][]string{"sonar.exclusions": {"**/vendor/**"}},
+			cr: project(withKey("key"), func(cr *v1alpha1.Project) {
+				cr.Status.AtProvider.Exclusions = []string{"**/vendor/**"}
+			}),
+			want: want{
+				requests: []string{"/api/settings/reset component=key&keys=sonar.exclusions"},
+			},
+		},
+		"Drift": {
+			reason: "Coverage exclusions changed outside of Crossplane should be set again.",
+			set:    map[string][]string{"sonar.coverage.exclusions": {"**/*_test.go", "**/testdata/**"}},
+			cr: project(withKey("key"), withCoverageExclusions("**/*_test.go"), func(cr *v1alpha1.Project) {
+				cr.Status.AtProvider.CoverageExclusions = []string{"**/*_test.go"}
+			}),
+			want: want{
+				requests: []string{"/api/settings/set component=key&key=sonar.coverage.exclusions&values=%2A%2A%2F%2A_test.go"},
+			},
+		},
+		"UpToDate": {
+			reason: "A project that sets the desired exclusions should be reported as up to date.",
+			set:    map[string][]string{"sonar.exclusions": {"**/vendor/**"}},
+			cr:     project(withKey("key"), withExclusions("**/vendor/**")),
+			want:   want{upToDate: true},
+		},
+		"ClearedOutOfBand": {
+			reason: "Exclusions that are no longer desired should be left alone when the project no longer sets them.",
+			cr: project(withKey("key"), func(cr *v1alpha1.Project) {
+				cr.Status.AtProvider.Exclusions = []string{"**/vendor/**"}
+			}),
+			want: want{upToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			search := searchResponse(sonar.Project{Organization: "org", Key: "key"})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/settings/values":
+					settings := []sonar.Setting{}
+					for key, values := range tc.set {
+						settings = append(settings, sonar.Setting{Key: key, Values: values})
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"settings": settings})
+				case "/api/settings/set", "/api/settings/reset":
+					_ = r.ParseForm()
+					got = append(got, r.URL.Path+" "+r.Form.Encode())
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			options := sonar.SonarApiOptions{BaseUrl: srv.URL}
+			e := &external{projectClient: sonar.NewProjectClient(options), settingsClient: sonar.NewSettingsClient(options), logger: logging.NewNopLogger()}
+
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.upToDate, o.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+			if o.ResourceUpToDate {
+				return
+			}
+
+			if _, err := e.Update(context.Background(), tc.cr); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.requests, got); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.observed, tc.cr.Status.AtProvider.Exclusions); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want exclusions, +got exclusions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDisableRecreation(t *testing.T) {
+	type want struct {
+		exists bool
+		ready  xpv1.Condition
+	}
+
+	deleted := xpv1.Unavailable().WithMessage(errDeletedExternally)
+
+	cases := map[string]struct {
+		reason  string
+		disable bool
+		found   bool
+		cr      *v1alpha1.Project
+		want    want
+	}{
+		"Recreate": {
+			reason: "A project deleted outside of Crossplane should be created again by default.",
+			cr:     project(withKey("key"), withObservedProjectURL("https://sonarcloud.io/dashboard?id=key")),
+			want:   want{ready: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown}},
+		},
+		"RecreationDisabled": {
+			reason:  "A project deleted outside of Crossplane should be reported as unavailable, and as existing so that it is not created again.",
+			disable: true,
+			cr:      project(withKey("key"), withObservedProjectURL("https://sonarcloud.io/dashboard?id=key")),
+			want:    want{exists: true, ready: deleted},
+		},
+		"DeletedWhileRecreationDisabled": {
+			reason:  "A project deleted outside of Crossplane should be reported as not existing once its managed resource is deleted, so that the deletion completes.",
+			disable: true,
+			cr: project(withKey("key"), withObservedProjectURL("https://sonarcloud.io/dashboard?id=key"), func(cr *v1alpha1.Project) {
+				cr.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			}),
+			want: want{ready: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown}},
+		},
+		"NeverObserved": {
+			reason:  "A project that was never observed should be created even when recreation is disabled.",
+			disable: true,
+			cr:      project(withKey("key")),
+			want:    want{ready: xpv1.Condition{Type: xpv1.TypeReady, Status: corev1.ConditionUnknown}},
+		},
+		"Restored": {
+			reason:  "A project restored after it was reported as deleted should be reported as available again.",
+			disable: true,
+			found:   true,
+			cr: project(withKey("key"), withObservedProjectURL("https://sonarcloud.io/dashboard?id=key"), func(cr *v1alpha1.Project) {
+				cr.SetConditions(deleted)
+			}),
+			want: want{exists: true, ready: xpv1.Available()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var projects []sonar.Project
+			if tc.found {
+				projects = append(projects, sonar.Project{Organization: "org", Key: "key"})
+			}
+			search := searchResponse(projects...)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/project_badges/token":
+					_, _ = w.Write([]byte(`{"token":"badge-token"}`))
+				default:
+					search(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			e := &external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), disableRecreation: tc.disable, logger: logging.NewNopLogger()}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.exists, o.ResourceExists); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want exists, +got exists:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ready, tc.cr.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want ready condition, +got ready condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type projectModifier func(*v1alpha1.Project)
+
+func withOrganization(organization string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Organization = organization }
+}
+
+func withConditions(c ...xpv1.Condition) projectModifier {
+	return func(p *v1alpha1.Project) { p.SetConditions(c...) }
+}
+
+func withObservedOrganization(organization string) projectModifier {
+	return func(p *v1alpha1.Project) { p.Status.AtProvider.Organization = organization }
+}
+
+func withExternalName(name string) projectModifier {
+	return func(p *v1alpha1.Project) { meta.SetExternalName(p, name) }
+}
+
+func withCreated(t time.Time) projectModifier {
+	return func(p *v1alpha1.Project) { meta.SetExternalCreateSucceeded(p, t) }
+}
+
+func withTemplateProject(key string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.TemplateProject = key }
+}
+
+func withKey(key string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Key = key }
+}
+
+func withName(name string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Name = name }
+}
+
+func withVisibility(visibility string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Visibility = visibility }
+}
+
+func withTags(tags ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Tags = tags }
+}
+
+func withQualityGate(name string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.QualityGate = name }
+}
+
+func withNewCodePeriod(periodType, value string) projectModifier {
+	return func(cr *v1alpha1.Project) {
+		cr.Spec.ForProvider.NewCodePeriod = &v1alpha1.NewCodePeriod{Type: periodType, Value: value}
+	}
+}
+
+func withMeasures(metrics ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Measures = metrics }
+}
+
+func withObservedMeasures(measures map[string]string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.Measures = measures }
+}
+
+func withMainBranch(name string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.MainBranch = name }
+}
+
+func withObservedMainBranch(name string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.MainBranch = name }
+}
+
+// withLinks sets the links parameter from pairs of names and URLs.
+func withLinks(nameURLs ...string) projectModifier {
+	return func(cr *v1alpha1.Project) {
+		for i := 0; i+1 < len(nameURLs); i += 2 {
+			cr.Spec.ForProvider.Links = append(cr.Spec.ForProvider.Links, v1alpha1.ProjectLink{Name: nameURLs[i], URL: nameURLs[i+1]})
+		}
+	}
+}
+
+func withReportAnalyses() projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.ReportAnalyses = true }
+}
+
+func withReportIssueCounts() projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.ReportIssueCounts = true }
+}
+
+func withGenerateAnalysisToken() projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.GenerateAnalysisToken = true }
+}
+
+func withAnalysisTokenName(name string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.AnalysisTokenName = name }
+}
+
+func withBadgeTokenRotation(rotation string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.BadgeTokenRotation = rotation }
+}
+
+func withProfileAssociation(language, name string) projectModifier {
+	return func(cr *v1alpha1.Project) {
+		if cr.Spec.ForProvider.ProfileAssociations == nil {
+			cr.Spec.ForProvider.ProfileAssociations = map[string]string{}
+		}
+		cr.Spec.ForProvider.ProfileAssociations[language] = name
+	}
+}
+
+func withProtectedBranches(branches ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.ProtectedBranches = branches }
+}
+
+func withObservedProtectedBranches(branches ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.ProtectedBranches = branches }
+}
+
+func withExclusions(patterns ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Exclusions = patterns }
+}
+
+func withCoverageExclusions(patterns ...string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Spec.ForProvider.CoverageExclusions = patterns }
+}
+
+func withObservedProfileAssociation(language, name string) projectModifier {
+	return func(cr *v1alpha1.Project) {
+		if cr.Status.AtProvider.ProfileAssociations == nil {
+			cr.Status.AtProvider.ProfileAssociations = map[string]string{}
+		}
+		cr.Status.AtProvider.ProfileAssociations[language] = name
+	}
+}
+
+func withObservedProjectURL(u string) projectModifier {
+	return func(cr *v1alpha1.Project) { cr.Status.AtProvider.ProjectURL = u }
+}
+
+func project(m ...projectModifier) *v1alpha1.Project {
+	cr := &v1alpha1.Project{}
+	cr.Spec.ForProvider.Organization = "org"
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// searchResponse returns a handler that answers every request with a single
+// page of the supplied projects.
+func searchResponse(projects ...sonar.Project) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(sonar.ProjectPage{
+			Paging:   sonar.SonarPaging{PageIndex: 1, PageSize: 100, Total: len(projects)},
+			Projects: projects,
+		})
+	}
+}
+
+func TestExternalConnectionFailure(t *testing.T) {
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	cr := &v1alpha1.Project{
+		Spec: v1alpha1.ProjectSpec{
+			ForProvider: v1alpha1.ProjectParameters{
+				Organization: "org",
+				Key:          "key",
+				Visibility:   "private",
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, e *external) error
+		want   string
+	}{
+		"Observe": {
+			reason: "Observe should return an error rather than exit when the API is unreachable.",
+			call: func(ctx context.Context, e *external) error {
+				_, err := e.Observe(ctx, cr)
+				return err
+			},
+			want: errGetProject,
+		},
+		"Create": {
+			reason: "Create should return an error rather than exit when the API is unreachable.",
+			call: func(ctx context.Context, e *external) error {
+				_, err := e.Create(ctx, cr)
+				return err
+			},
+			want: errCreateProject,
+		},
+		"Update": {
+			reason: "Update should return an error rather than exit when the API is unreachable.",
+			call: func(ctx context.Context, e *external) error {
+				_, err := e.Update(ctx, cr)
+				return err
+			},
+			want: errUpdateProject,
+		},
+		"Delete": {
+			reason: "Delete should return an error rather than exit when the API is unreachable.",
+			call: func(ctx context.Context, e *external) error {
+				return e.Delete(ctx, cr)
+			},
+			want: errDeleteProject,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{projectClient: sonar.NewProjectClient(sonar.SonarApiOptions{BaseUrl: srv.URL}), logger: logging.NewNopLogger()}
+			err := tc.call(context.Background(), e)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Errorf("\n%s\ne.%s(...): want error prefixed %q, got %v", tc.reason, name, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCredentialsNotLogged(t *testing.T) {
+	const token = "squ_0123456789abcdef"
+
+	srv := httptest.NewServer(searchResponse(sonar.Project{Organization: "org", Key: "key", Visibility: "private"}))
+	defer srv.Close()
+
+	var out strings.Builder
+	logger := logging.NewLogrLogger(funcr.New(func(prefix, args string) {
+		out.WriteString(prefix + " " + args + "\n")
+	}, funcr.Options{Verbosity: 1}))
+
+	c := &connector{
+		kube: &test.MockClient{
+			MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+				switch o := obj.(type) {
+				case *apisv1alpha1.ProviderConfig:
+					o.Spec.BaseURL = srv.URL
+					o.Spec.Credentials = apisv1alpha1.ProviderCredentials{
+						Source: xpv1.CredentialsSourceSecret,
+						CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+							SecretRef: &xpv1.SecretKeySelector{Key: "credentials"},
+						},
+					}
+				case *corev1.Secret:
+					o.Data = map[string][]byte{"credentials": []byte(token)}
+				}
+				return nil
+			},
+		},
+		usage:       resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		logger:      logger,
+		newClientFn: sonar.NewProjectService,
+	}
+
+	cr := project(withKey("key"), withVisibility("private"))
+	cr.Spec.ProviderConfigReference = &xpv1.Reference{Name: "default"}
+
+	e, err := c.Connect(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("c.Connect(...): unexpected error: %v", err)
+	}
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+
+	if out.Len() == 0 {
+		t.Fatal("expected reconcile steps to be logged at debug level")
+	}
+	if strings.Contains(out.String(), token) {
+		t.Errorf("credentials were logged:\n%s", out.String())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+	forbidden := &sonar.SonarAPIError{StatusCode: http.StatusForbidden, Status: "403 Forbidden", Message: "Insufficient privileges"}
+
+	type want struct {
+		key string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Success": {
+			reason: "The project should be deleted by its key.",
+			want:   want{key: "key"},
+		},
+		"Error": {
+			reason: "An error deleting the project should be returned.",
+			err:    errBoom,
+			want:   want{key: "key", err: errors.Wrap(errBoom, errDeleteProject)},
+		},
+		"AlreadyDeleted": {
+			reason: "A project that no longer exists should be reported as deleted.",
+			err:    &sonar.SonarAPIError{StatusCode: http.StatusNotFound, Status: "404 Not Found", Message: "Project 'key' not found"},
+			want:   want{key: "key"},
+		},
+		"Forbidden": {
+			reason: "A project that cannot be deleted for lack of permission should be reported as an error.",
+			err:    forbidden,
+			want:   want{key: "key", err: errors.Wrap(forbidden, errDeleteProject)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var key string
+			e := &external{
+				projectClient: &fake.MockProjectService{
+					MockDelete: func(_ context.Context, project string) error {
+						key = project
+						return tc.err
+					},
+				},
+				logger: logging.NewNopLogger(),
+			}
+
+			err := e.Delete(context.Background(), project(withKey("key")))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, key); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want key, +got key:\n%s\n", tc.reason, diff)
+			}
 		})
 	}
 }
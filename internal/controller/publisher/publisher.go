@@ -0,0 +1,58 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package publisher publishes the connection details of managed resources to
+// Kubernetes secrets.
+package publisher
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+)
+
+// FieldManager is the name the provider writes connection secrets as, so
+// that the fields it owns do not conflict with those of other controllers.
+const FieldManager = "provider-sonar"
+
+// NewAPISecretPublisher returns a ConnectionPublisher that writes the
+// connection secret of a managed resource as the supplied field manager. The
+// secret is controlled by the managed resource, and so garbage collected with
+// it.
+func NewAPISecretPublisher(c client.Client, ot runtime.ObjectTyper, fieldManager string) *managed.APISecretPublisher {
+	return managed.NewAPISecretPublisher(&fieldOwnerClient{Client: c, owner: client.FieldOwner(fieldManager)}, ot)
+}
+
+// A fieldOwnerClient writes objects as a field owner.
+type fieldOwnerClient struct {
+	client.Client
+	owner client.FieldOwner
+}
+
+func (c *fieldOwnerClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.Client.Create(ctx, obj, append([]client.CreateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldOwnerClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.Client.Update(ctx, obj, append([]client.UpdateOption{c.owner}, opts...)...)
+}
+
+func (c *fieldOwnerClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.Client.Patch(ctx, obj, patch, append([]client.PatchOption{c.owner}, opts...)...)
+}
@@ -0,0 +1,101 @@
+/*
+ Copyright 2022 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/project/v1alpha1"
+)
+
+// A recordingClient records the field managers objects are written as.
+type recordingClient struct {
+	client.Client
+	managers []string
+}
+
+func (c *recordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	o := &client.CreateOptions{}
+	o.ApplyOptions(opts)
+	c.managers = append(c.managers, o.FieldManager)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *recordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	o := &client.PatchOptions{}
+	o.ApplyOptions(opts)
+	c.managers = append(c.managers, o.FieldManager)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestPublishConnection(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := corev1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := &v1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "payments", UID: types.UID("uid")}}
+	cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Namespace: "crossplane-system", Name: "payments-sonar"})
+
+	kube := &recordingClient{Client: fake.NewClientBuilder().WithScheme(s).Build()}
+	p := NewAPISecretPublisher(kube, s, FieldManager)
+
+	// The secret is created, then patched when its details change.
+	for _, cd := range []managed.ConnectionDetails{{"projectKey": []byte("payments")}, {"projectKey": []byte("billing")}} {
+		if _, err := p.PublishConnection(context.Background(), cr, cd); err != nil {
+			t.Fatalf("p.PublishConnection(...): unexpected error: %v", err)
+		}
+	}
+
+	secret := &corev1.Secret{}
+	if err := kube.Get(context.Background(), types.NamespacedName{Namespace: "crossplane-system", Name: "payments-sonar"}, secret); err != nil {
+		t.Fatalf("kube.Get(...): unexpected error: %v", err)
+	}
+
+	controller := true
+	wantOwners := []metav1.OwnerReference{{
+		APIVersion: v1alpha1.ProjectGroupVersionKind.GroupVersion().String(),
+		Kind:       v1alpha1.ProjectKind,
+		Name:       "payments",
+		UID:        types.UID("uid"),
+		Controller: &controller,
+	}}
+	if diff := cmp.Diff(wantOwners, secret.GetOwnerReferences()); diff != "" {
+		t.Errorf("p.PublishConnection(...): -want owner references, +got owner references:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(map[string][]byte{"projectKey": []byte("billing")}, secret.Data); diff != "" {
+		t.Errorf("p.PublishConnection(...): -want data, +got data:\n%s\n", diff)
+	}
+	if diff := cmp.Diff([]string{FieldManager, FieldManager}, kube.managers); diff != "" {
+		t.Errorf("p.PublishConnection(...): -want field managers, +got field managers:\n%s\n", diff)
+	}
+}
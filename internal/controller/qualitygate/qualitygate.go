@@ -0,0 +1,382 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qualitygate
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/qualitygate/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotQualityGate = "managed resource is not a QualityGate custom resource"
+	errTrackPCUsage   = "cannot track ProviderConfig usage"
+	errGetPC          = "cannot get ProviderConfig"
+
+	errGetQualityGate    = "cannot get quality gate"
+	errCreateQualityGate = "cannot create quality gate"
+	errRenameQualityGate = "cannot rename quality gate"
+	errDeleteQualityGate = "cannot delete quality gate"
+	errValidateCondition = "cannot validate quality gate conditions"
+	errCreateCondition   = "cannot create quality gate condition"
+	errUpdateCondition   = "cannot update quality gate condition"
+	errDeleteCondition   = "cannot delete quality gate condition"
+	errGetDefault        = "cannot get default quality gate"
+	errSetDefault        = "cannot set default quality gate"
+	errNoBuiltInGate     = "cannot find built-in quality gate to restore as default"
+)
+
+// Setup adds a controller that reconciles QualityGate managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.QualityGateGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.QualityGateGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewQualityGateClient}),
+		// The external name is the ID of the quality gate, set by Create, not
+		// the name of the resource that the default initializer would set.
+		managed.WithInitializers(),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.QualityGate{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.QualityGateClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.QualityGate)
+	if !ok {
+		return nil, errors.New(errNotQualityGate)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{qualityGateClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	qualityGateClient sonar.QualityGateClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.QualityGate)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotQualityGate)
+	}
+
+	gate, err := c.getQualityGate(ctx, cr)
+	if err != nil {
+		if errors.Is(err, sonar.ErrQualityGateNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetQualityGate)
+	}
+
+	cr.Status.AtProvider = generateObservation(gate)
+
+	// The ID of a quality gate found by its name is recorded too.
+	li := meta.GetExternalName(cr) != cr.Status.AtProvider.ID
+	meta.SetExternalName(cr, cr.Status.AtProvider.ID)
+
+	// The default quality gate is only looked up when it is managed, since it
+	// takes another request.
+	if cr.Spec.ForProvider.Default {
+		def, err := c.qualityGateClient.GetDefault(ctx, cr.Spec.ForProvider.Organization)
+		if err != nil && !errors.Is(err, sonar.ErrQualityGateNotFound) {
+			return managed.ExternalObservation{}, errors.Wrap(err, errGetDefault)
+		}
+		cr.Status.AtProvider.IsDefault = err == nil && def.Name == gate.Name
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	toCreate, toUpdate, toDelete := diffConditions(cr.Spec.ForProvider.Conditions, cr.Status.AtProvider.Conditions)
+
+	return managed.ExternalObservation{
+		ResourceExists: true,
+		ResourceUpToDate: len(toCreate) == 0 && len(toUpdate) == 0 && len(toDelete) == 0 &&
+			cr.Spec.ForProvider.Default == cr.Status.AtProvider.IsDefault &&
+			cr.Spec.ForProvider.Name == cr.Status.AtProvider.Name,
+		ResourceLateInitialized: li,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.QualityGate)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotQualityGate)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	// A new quality gate has no conditions, so every desired condition is
+	// created. They are validated first, so that a gate is not created with
+	// only some of them.
+	toCreate, _, _ := diffConditions(cr.Spec.ForProvider.Conditions, nil)
+	if err := c.qualityGateClient.ValidateConditions(ctx, toCreate); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errValidateCondition)
+	}
+
+	gate, err := c.qualityGateClient.Create(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Name)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateQualityGate)
+	}
+	// Only the annotations of a QualityGate are saved after it is created, so
+	// its ID is recorded as its external name, which finds it until the next
+	// Observe even if it is renamed in between.
+	meta.SetExternalName(cr, string(gate.Id))
+	cr.Status.AtProvider.ID = string(gate.Id)
+	cr.Status.AtProvider.Name = cr.Spec.ForProvider.Name
+
+	// Anything that fails here is retried by the next Update.
+	for _, condition := range toCreate {
+		if _, err := c.qualityGateClient.CreateCondition(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Name, condition); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errCreateCondition)
+		}
+	}
+
+	if cr.Spec.ForProvider.Default {
+		if err := c.qualityGateClient.SetAsDefault(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Name); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errSetDefault)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.QualityGate)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotQualityGate)
+	}
+
+	org := cr.Spec.ForProvider.Organization
+
+	// A quality gate is renamed rather than created again, which would lose
+	// its conditions and the projects that selected it.
+	if cr.Status.AtProvider.Name != cr.Spec.ForProvider.Name {
+		if err := c.qualityGateClient.Rename(ctx, org, cr.Status.AtProvider.ID, cr.Spec.ForProvider.Name); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRenameQualityGate)
+		}
+		cr.Status.AtProvider.Name = cr.Spec.ForProvider.Name
+	}
+
+	// Observe records the conditions, including their IDs, in the status just
+	// before Update is called.
+	toCreate, toUpdate, toDelete := diffConditions(cr.Spec.ForProvider.Conditions, cr.Status.AtProvider.Conditions)
+	if err := c.qualityGateClient.ValidateConditions(ctx, append(append([]sonar.QualityGateCondition{}, toUpdate...), toCreate...)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errValidateCondition)
+	}
+
+	for _, condition := range toDelete {
+		if err := c.qualityGateClient.DeleteCondition(ctx, org, string(condition.Id)); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errDeleteCondition)
+		}
+	}
+	for _, condition := range toUpdate {
+		if err := c.qualityGateClient.UpdateCondition(ctx, org, condition); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateCondition)
+		}
+	}
+	for _, condition := range toCreate {
+		if _, err := c.qualityGateClient.CreateCondition(ctx, org, cr.Spec.ForProvider.Name, condition); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errCreateCondition)
+		}
+	}
+
+	// Setting a new default replaces the current one, be it another managed
+	// quality gate or the built-in one.
+	if cr.Spec.ForProvider.Default && !cr.Status.AtProvider.IsDefault {
+		if err := c.qualityGateClient.SetAsDefault(ctx, org, cr.Spec.ForProvider.Name); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errSetDefault)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.QualityGate)
+	if !ok {
+		return errors.New(errNotQualityGate)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	// The default quality gate cannot be deleted, so the built-in quality gate
+	// is made the default again first.
+	if cr.Spec.ForProvider.Default && cr.Status.AtProvider.IsDefault {
+		if err := c.restoreBuiltInDefault(ctx, cr.Spec.ForProvider.Organization); err != nil {
+			return err
+		}
+	}
+
+	err := c.qualityGateClient.Delete(ctx, cr.Spec.ForProvider.Organization, cr.Status.AtProvider.ID)
+	return errors.Wrap(err, errDeleteQualityGate)
+}
+
+// restoreBuiltInDefault makes the built-in quality gate, "Sonar way", the
+// default of an organization.
+func (c *external) restoreBuiltInDefault(ctx context.Context, organization string) error {
+	gates, err := c.qualityGateClient.List(ctx, organization)
+	if err != nil {
+		return errors.Wrap(err, errGetDefault)
+	}
+	for _, gate := range gates {
+		if gate.IsBuiltIn {
+			return errors.Wrap(c.qualityGateClient.SetAsDefault(ctx, organization, gate.Name), errSetDefault)
+		}
+	}
+	return errors.New(errNoBuiltInGate)
+}
+
+// getQualityGate looks a QualityGate up by the ID recorded in its external
+// name, which still finds it after its name changed, and otherwise by its
+// name. An external name that is the name of the resource was set by the
+// default initializer, which earlier versions used, so the ID recorded in the
+// status is used instead.
+func (c *external) getQualityGate(ctx context.Context, cr *v1alpha1.QualityGate) (sonar.QualityGate, error) {
+	id := meta.GetExternalName(cr)
+	if id == "" || id == cr.GetName() {
+		id = cr.Status.AtProvider.ID
+	}
+	if id != "" {
+		gate, err := c.qualityGateClient.GetById(ctx, cr.Spec.ForProvider.Organization, id)
+		// Versions that no longer look quality gates up by ID reject the
+		// request.
+		var apiErr *sonar.SonarAPIError
+		if !errors.Is(err, sonar.ErrQualityGateNotFound) && !(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest) {
+			return gate, err
+		}
+	}
+	return c.qualityGateClient.GetByName(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Name)
+}
+
+// generateObservation produces the observed state of a QualityGate from the
+// quality gate returned by the API.
+func generateObservation(gate sonar.QualityGate) v1alpha1.QualityGateObservation {
+	o := v1alpha1.QualityGateObservation{ID: string(gate.Id), Name: gate.Name}
+	for _, c := range gate.Conditions {
+		o.Conditions = append(o.Conditions, v1alpha1.QualityGateConditionObservation{
+			ID: string(c.Id),
+			QualityGateCondition: v1alpha1.QualityGateCondition{
+				Metric: c.Metric,
+				Op:     c.Op,
+				Error:  c.Error,
+			},
+		})
+	}
+	return o
+}
+
+// diffConditions compares the desired conditions of a quality gate with the
+// observed ones. Conditions are matched by metric, since a quality gate holds at
+// most one condition per metric. Conditions to update and delete carry the ID of
+// the observed condition they apply to.
+func diffConditions(desired []v1alpha1.QualityGateCondition, observed []v1alpha1.QualityGateConditionObservation) (toCreate, toUpdate, toDelete []sonar.QualityGateCondition) {
+	existing := make(map[string]v1alpha1.QualityGateConditionObservation, len(observed))
+	for _, o := range observed {
+		existing[o.Metric] = o
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		wanted[d.Metric] = true
+
+		o, ok := existing[d.Metric]
+		if !ok {
+			toCreate = append(toCreate, sonar.QualityGateCondition{Metric: d.Metric, Op: d.Op, Error: d.Error})
+			continue
+		}
+		if o.Op != d.Op || o.Error != d.Error {
+			toUpdate = append(toUpdate, sonar.QualityGateCondition{Id: sonar.ID(o.ID), Metric: d.Metric, Op: d.Op, Error: d.Error})
+		}
+	}
+
+	for _, o := range observed {
+		if !wanted[o.Metric] {
+			toDelete = append(toDelete, sonar.QualityGateCondition{Id: sonar.ID(o.ID), Metric: o.Metric, Op: o.Op, Error: o.Error})
+		}
+	}
+
+	return toCreate, toUpdate, toDelete
+}
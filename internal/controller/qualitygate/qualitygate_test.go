@@ -0,0 +1,444 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qualitygate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/provider-sonar/apis/qualitygate/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestDiffConditions(t *testing.T) {
+	coverage := v1alpha1.QualityGateCondition{Metric: "new_coverage", Op: "LT", Error: "80"}
+	duplication := v1alpha1.QualityGateCondition{Metric: "new_duplicated_lines_density", Op: "GT", Error: "3"}
+
+	type args struct {
+		desired  []v1alpha1.QualityGateCondition
+		observed []v1alpha1.QualityGateConditionObservation
+	}
+
+	type want struct {
+		toCreate []sonar.QualityGateCondition
+		toUpdate []sonar.QualityGateCondition
+		toDelete []sonar.QualityGateCondition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			reason: "No changes should be needed when the observed conditions match the desired ones.",
+			args: args{
+				desired:  []v1alpha1.QualityGateCondition{coverage},
+				observed: []v1alpha1.QualityGateConditionObservation{{ID: "1", QualityGateCondition: coverage}},
+			},
+		},
+		"Add": {
+			reason: "A desired condition that was not observed should be created.",
+			args: args{
+				desired:  []v1alpha1.QualityGateCondition{coverage, duplication},
+				observed: []v1alpha1.QualityGateConditionObservation{{ID: "1", QualityGateCondition: coverage}},
+			},
+			want: want{
+				toCreate: []sonar.QualityGateCondition{{Metric: "new_duplicated_lines_density", Op: "GT", Error: "3"}},
+			},
+		},
+		"Change": {
+			reason: "A condition whose threshold changed should be updated using the observed id.",
+			args: args{
+				desired: []v1alpha1.QualityGateCondition{{Metric: "new_coverage", Op: "LT", Error: "90"}},
+				observed: []v1alpha1.QualityGateConditionObservation{
+					{ID: "1", QualityGateCondition: coverage},
+				},
+			},
+			want: want{
+				toUpdate: []sonar.QualityGateCondition{{Id: "1", Metric: "new_coverage", Op: "LT", Error: "90"}},
+			},
+		},
+		"Remove": {
+			reason: "An observed condition that is no longer desired should be deleted by id.",
+			args: args{
+				desired: []v1alpha1.QualityGateCondition{coverage},
+				observed: []v1alpha1.QualityGateConditionObservation{
+					{ID: "1", QualityGateCondition: coverage},
+					{ID: "2", QualityGateCondition: duplication},
+				},
+			},
+			want: want{
+				toDelete: []sonar.QualityGateCondition{{Id: "2", Metric: "new_duplicated_lines_density", Op: "GT", Error: "3"}},
+			},
+		},
+		"RemoveAll": {
+			reason: "Every observed condition should be deleted when none are desired.",
+			args: args{
+				observed: []v1alpha1.QualityGateConditionObservation{{ID: "1", QualityGateCondition: coverage}},
+			},
+			want: want{
+				toDelete: []sonar.QualityGateCondition{{Id: "1", Metric: "new_coverage", Op: "LT", Error: "80"}},
+			},
+		},
+		"AddChangeRemove": {
+			reason: "Adds, changes and removals should be reconciled individually in a single diff.",
+			args: args{
+				desired: []v1alpha1.QualityGateCondition{
+					{Metric: "new_coverage", Op: "LT", Error: "70"},
+					{Metric: "new_bugs", Op: "GT", Error: "0"},
+				},
+				observed: []v1alpha1.QualityGateConditionObservation{
+					{ID: "1", QualityGateCondition: coverage},
+					{ID: "2", QualityGateCondition: duplication},
+				},
+			},
+			want: want{
+				toCreate: []sonar.QualityGateCondition{{Metric: "new_bugs", Op: "GT", Error: "0"}},
+				toUpdate: []sonar.QualityGateCondition{{Id: "1", Metric: "new_coverage", Op: "LT", Error: "70"}},
+				toDelete: []sonar.QualityGateCondition{{Id: "2", Metric: "new_duplicated_lines_density", Op: "GT", Error: "3"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			toCreate, toUpdate, toDelete := diffConditions(tc.args.desired, tc.args.observed)
+			if diff := cmp.Diff(tc.want.toCreate, toCreate); diff != "" {
+				t.Errorf("\n%s\ndiffConditions(...): -want toCreate, +got toCreate:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.toUpdate, toUpdate); diff != "" {
+				t.Errorf("\n%s\ndiffConditions(...): -want toUpdate, +got toUpdate:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.toDelete, toDelete); diff != "" {
+				t.Errorf("\n%s\ndiffConditions(...): -want toDelete, +got toDelete:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// defaultGateServer serves a gate named strict, and the quality gates of an
+// organization whose default is the named gate. Every write request is recorded.
+func defaultGateServer(t *testing.T, defaultGate string, writes *[]url.Values) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/api/qualitygates/show":
+			_, _ = w.Write([]byte(`{"id":"2","name":"strict","conditions":[]}`))
+		case "/api/qualitygates/list":
+			gates := `[{"id":"1","name":"Sonar way","isBuiltIn":true,"isDefault":` + boolJSON(defaultGate == "Sonar way") + `},` +
+				`{"id":"2","name":"strict","isDefault":` + boolJSON(defaultGate == "strict") + `}]`
+			_, _ = w.Write([]byte(`{"qualitygates":` + gates + `}`))
+		default:
+			params := r.Form
+			params.Set("path", r.URL.Path)
+			*writes = append(*writes, params)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func boolJSON(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func gate(isDefault bool) *v1alpha1.QualityGate {
+	cr := &v1alpha1.QualityGate{}
+	cr.Spec.ForProvider = v1alpha1.QualityGateParameters{Organization: "org", Name: "strict", Default: isDefault}
+	return cr
+}
+
+func TestRename(t *testing.T) {
+	cases := map[string]struct {
+		reason       string
+		name         string
+		externalName string
+		statusID     string
+		upToDate     bool
+		want         []url.Values
+	}{
+		"NameChanged": {
+			reason:       "A quality gate whose name changed should be renamed by its tracked ID rather than created again.",
+			name:         "stricter",
+			externalName: "2",
+			statusID:     "2",
+			want:         []url.Values{{"path": {"/api/qualitygates/rename"}, "organization": {"org"}, "id": {"2"}, "name": {"stricter"}}},
+		},
+		"NameChangedAfterCreate": {
+			reason:       "A quality gate renamed before it was observed, whose ID only its external name holds since the status set by Create is not saved, should be renamed by that ID.",
+			name:         "stricter",
+			externalName: "2",
+			want:         []url.Values{{"path": {"/api/qualitygates/rename"}, "organization": {"org"}, "id": {"2"}, "name": {"stricter"}}},
+		},
+		"NameChangedDefaultExternalName": {
+			reason:       "A quality gate whose external name is the name of the resource, set by the default initializer of earlier versions, should be renamed by the ID in its status.",
+			name:         "stricter",
+			externalName: "strict-gate",
+			statusID:     "2",
+			want:         []url.Values{{"path": {"/api/qualitygates/rename"}, "organization": {"org"}, "id": {"2"}, "name": {"stricter"}}},
+		},
+		"NameUnchanged": {
+			reason:       "A quality gate whose name did not change should be up to date.",
+			name:         "strict",
+			externalName: "2",
+			statusID:     "2",
+			upToDate:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var writes []url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				switch {
+				case r.URL.Path == "/api/qualitygates/show" && r.Form.Get("id") == "2":
+					_, _ = w.Write([]byte(`{"id":"2","name":"strict","conditions":[]}`))
+				case r.URL.Path == "/api/qualitygates/show":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					params := r.Form
+					params.Set("path", r.URL.Path)
+					writes = append(writes, params)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer srv.Close()
+
+			mg := gate(false)
+			mg.SetName("strict-gate")
+			mg.Spec.ForProvider.Name = tc.name
+			meta.SetExternalName(mg, tc.externalName)
+			mg.Status.AtProvider.ID = tc.statusID
+
+			e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			o, err := e.Observe(context.Background(), mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if !o.ResourceExists {
+				t.Fatalf("\n%s\ne.Observe(...): want the quality gate found by its ID", tc.reason)
+			}
+			if diff := cmp.Diff("2", meta.GetExternalName(mg)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.upToDate, o.ResourceUpToDate); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want up to date, +got up to date:\n%s\n", tc.reason, diff)
+			}
+			if o.ResourceUpToDate {
+				return
+			}
+
+			if _, err := e.Update(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, writes); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.name, mg.Status.AtProvider.Name); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want observed name, +got observed name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveDefault(t *testing.T) {
+	type want struct {
+		isDefault bool
+		upToDate  bool
+	}
+
+	cases := map[string]struct {
+		reason      string
+		mg          *v1alpha1.QualityGate
+		defaultGate string
+		want        want
+	}{
+		"BuiltInDefault": {
+			reason:      "A gate that should be the default should not be up to date while the built-in gate is the default.",
+			mg:          gate(true),
+			defaultGate: "Sonar way",
+			want:        want{isDefault: false, upToDate: false},
+		},
+		"IsDefault": {
+			reason:      "A gate that should be the default should be up to date once it is the default.",
+			mg:          gate(true),
+			defaultGate: "strict",
+			want:        want{isDefault: true, upToDate: true},
+		},
+		"Unmanaged": {
+			reason:      "The default should not be managed when the gate is not asked to be the default.",
+			mg:          gate(false),
+			defaultGate: "Sonar way",
+			want:        want{isDefault: false, upToDate: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var writes []url.Values
+			srv := defaultGateServer(t, tc.defaultGate, &writes)
+			defer srv.Close()
+
+			e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			o, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			got := want{isDefault: tc.mg.Status.AtProvider.IsDefault, upToDate: o.ResourceUpToDate}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdateDefault(t *testing.T) {
+	var writes []url.Values
+	srv := defaultGateServer(t, "Sonar way", &writes)
+	defer srv.Close()
+
+	e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	mg := gate(true)
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+	if _, err := e.Update(context.Background(), mg); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %v", err)
+	}
+
+	want := []url.Values{{"path": {"/api/qualitygates/set_as_default"}, "organization": {"org"}, "name": {"strict"}}}
+	if diff := cmp.Diff(want, writes); diff != "" {
+		t.Errorf("e.Update(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestDeleteDefault(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		defaultGate string
+		want        []url.Values
+	}{
+		"RestoreBuiltIn": {
+			reason:      "The built-in gate should be made the default again before the default gate is deleted.",
+			defaultGate: "strict",
+			want: []url.Values{
+				{"path": {"/api/qualitygates/set_as_default"}, "organization": {"org"}, "name": {"Sonar way"}},
+				{"path": {"/api/qualitygates/destroy"}, "organization": {"org"}, "id": {"2"}},
+			},
+		},
+		"NotDefault": {
+			reason:      "A gate that is not the default should be deleted straight away.",
+			defaultGate: "Sonar way",
+			want: []url.Values{
+				{"path": {"/api/qualitygates/destroy"}, "organization": {"org"}, "id": {"2"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var writes []url.Values
+			srv := defaultGateServer(t, tc.defaultGate, &writes)
+			defer srv.Close()
+
+			e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			mg := gate(true)
+			if _, err := e.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if err := e.Delete(context.Background(), mg); err != nil {
+				t.Fatalf("\n%s\ne.Delete(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, writes); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateSetsExternalName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"2","name":"strict"}`))
+	}))
+	defer srv.Close()
+
+	e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	mg := gate(false)
+	if _, err := e.Create(context.Background(), mg); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	// Only the annotations are saved after Create, so the ID is recorded as
+	// the external name.
+	if diff := cmp.Diff("2", meta.GetExternalName(mg)); diff != "" {
+		t.Errorf("e.Create(...): -want external name, +got external name:\n%s\n", diff)
+	}
+}
+
+func TestCreateInvalidCondition(t *testing.T) {
+	var writes []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.URL.Path {
+		case "/api/metrics/search":
+			_, _ = w.Write([]byte(`{"metrics":[{"key":"new_reliability_rating","type":"RATING"}],"total":1,"p":1,"ps":500}`))
+		default:
+			params := r.Form
+			params.Set("path", r.URL.Path)
+			writes = append(writes, params)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	e := external{qualityGateClient: sonar.NewQualityGateClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	mg := gate(false)
+	mg.Spec.ForProvider.Conditions = []v1alpha1.QualityGateCondition{{Metric: "new_reliability_rating", Op: "LT", Error: "2"}}
+
+	_, err := e.Create(context.Background(), mg)
+	if !errors.Is(err, sonar.ErrInvalidCondition) {
+		t.Fatalf("e.Create(...): want an invalid condition error, got %v", err)
+	}
+	want := `cannot validate quality gate conditions: invalid quality gate condition on metric "new_reliability_rating" with operator "LT": metrics of type RATING only accept the GT operator`
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+	if len(writes) != 0 {
+		t.Errorf("e.Create(...): want no quality gate created, got requests %v", writes)
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qualityprofile
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/qualityprofile/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotQualityProfile = "managed resource is not a QualityProfile custom resource"
+	errTrackPCUsage      = "cannot track ProviderConfig usage"
+	errGetPC             = "cannot get ProviderConfig"
+
+	errGetQualityProfile    = "cannot get quality profile"
+	errCreateQualityProfile = "cannot create quality profile"
+	errUpdateQualityProfile = "cannot update quality profile"
+	errDeleteQualityProfile = "cannot delete quality profile"
+	errAddProject           = "cannot associate project with quality profile"
+	errRemoveProject        = "cannot remove project from quality profile"
+)
+
+// Setup adds a controller that reconciles QualityProfile managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.QualityProfileGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.QualityProfileGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewQualityProfileClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.QualityProfile{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.QualityProfileClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.QualityProfile)
+	if !ok {
+		return nil, errors.New(errNotQualityProfile)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{qualityProfileClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	qualityProfileClient sonar.QualityProfileClient
+}
+
+// get returns the observed quality profile. A profile that was created is
+// found by the key recorded in its status, so that a rename can be detected.
+// Otherwise it is found by its language and name.
+func (c *external) get(ctx context.Context, cr *v1alpha1.QualityProfile) (sonar.QualityProfile, error) {
+	p := cr.Spec.ForProvider
+	if cr.Status.AtProvider.Key != "" {
+		return c.qualityProfileClient.GetByKey(ctx, p.Organization, p.Language, cr.Status.AtProvider.Key)
+	}
+	return c.qualityProfileClient.GetByName(ctx, p.Organization, p.Language, p.Name)
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.QualityProfile)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotQualityProfile)
+	}
+
+	profile, err := c.get(ctx, cr)
+	if err != nil {
+		if errors.Is(err, sonar.ErrQualityProfileNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetQualityProfile)
+	}
+
+	cr.Status.AtProvider.Key = profile.Key
+	cr.SetConditions(xpv1.Available())
+
+	if profile.Name != cr.Spec.ForProvider.Name {
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, nil
+	}
+
+	projects, err := c.qualityProfileClient.Projects(ctx, profile.Key)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetQualityProfile)
+	}
+	toAdd, toRemove := diffProjects(cr.Spec.ForProvider.Projects, projects)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: len(toAdd) == 0 && len(toRemove) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.QualityProfile)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotQualityProfile)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	p := cr.Spec.ForProvider
+	profile, err := c.qualityProfileClient.Create(ctx, p.Organization, p.Language, p.Name)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateQualityProfile)
+	}
+	cr.Status.AtProvider.Key = profile.Key
+
+	// Anything that fails here is retried by the next Update.
+	for _, project := range p.Projects {
+		if err := c.qualityProfileClient.AddProject(ctx, p.Organization, p.Language, p.Name, project); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAddProject)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.QualityProfile)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotQualityProfile)
+	}
+
+	p := cr.Spec.ForProvider
+
+	profile, err := c.get(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateQualityProfile)
+	}
+
+	if profile.Name != p.Name {
+		if err := c.qualityProfileClient.Rename(ctx, profile.Key, p.Name); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateQualityProfile)
+		}
+	}
+
+	projects, err := c.qualityProfileClient.Projects(ctx, profile.Key)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateQualityProfile)
+	}
+
+	toAdd, toRemove := diffProjects(p.Projects, projects)
+	for _, project := range toRemove {
+		if err := c.qualityProfileClient.RemoveProject(ctx, p.Organization, p.Language, p.Name, project); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRemoveProject)
+		}
+	}
+	for _, project := range toAdd {
+		if err := c.qualityProfileClient.AddProject(ctx, p.Organization, p.Language, p.Name, project); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAddProject)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.QualityProfile)
+	if !ok {
+		return errors.New(errNotQualityProfile)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	p := cr.Spec.ForProvider
+	err := c.qualityProfileClient.Delete(ctx, p.Organization, p.Language, p.Name)
+	return errors.Wrap(err, errDeleteQualityProfile)
+}
+
+// diffProjects compares the desired project associations of a quality profile
+// with the observed ones. Associations are left unmanaged when none are
+// desired.
+func diffProjects(desired, observed []string) (toAdd, toRemove []string) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	existing := make(map[string]bool, len(observed))
+	for _, o := range observed {
+		existing[o] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		wanted[d] = true
+		if !existing[d] {
+			toAdd = append(toAdd, d)
+		}
+	}
+
+	for _, o := range observed {
+		if !wanted[o] {
+			toRemove = append(toRemove, o)
+		}
+	}
+
+	return toAdd, toRemove
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qualityprofile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/qualityprofile/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied quality profiles and associated projects, and
+// records every other request it receives.
+type fakeSonar struct {
+	profiles []sonar.QualityProfile
+	projects []string
+	requests []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	switch r.URL.Path {
+	case "/api/qualityprofiles/search":
+		_ = json.NewEncoder(w).Encode(map[string][]sonar.QualityProfile{"profiles": f.profiles})
+	case "/api/qualityprofiles/projects":
+		results := make([]map[string]string, 0, len(f.projects))
+		for _, p := range f.projects {
+			results = append(results, map[string]string{"key": p})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"paging":  sonar.SonarPaging{PageIndex: 1, PageSize: 500, Total: len(results)},
+			"results": results,
+		})
+	case "/api/qualityprofiles/create":
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		_ = json.NewEncoder(w).Encode(map[string]sonar.QualityProfile{"profile": {Key: "AXP1", Name: r.Form.Get("name"), Language: r.Form.Get("language")}})
+	default:
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o   managed.ExternalObservation
+		key string
+	}
+
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.QualityProfile
+		want   want
+	}{
+		"NotFound": {
+			reason: "A quality profile that does not exist should be reported as such.",
+			sonar:  &fakeSonar{},
+			mg:     profile(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"OtherLanguage": {
+			reason: "A quality profile with the same name but another language should not match.",
+			sonar:  &fakeSonar{profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "strict", Language: "js"}}},
+			mg:     profile(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A quality profile matching by language and name should be reported as up to date and have its key recorded.",
+			sonar: &fakeSonar{
+				profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "strict", Language: "java"}},
+				projects: []string{"b", "a"},
+			},
+			mg: profile(withProjects("a", "b")),
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				key: "AXP1",
+			},
+		},
+		"Renamed": {
+			reason: "A quality profile renamed outside of Crossplane should be found by key and reported as not up to date.",
+			sonar:  &fakeSonar{profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "lenient", Language: "java"}}},
+			mg:     profile(withKey("AXP1")),
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				key: "AXP1",
+			},
+		},
+		"ProjectDrift": {
+			reason: "A quality profile whose associated projects drifted should be reported as not up to date.",
+			sonar: &fakeSonar{
+				profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "strict", Language: "java"}},
+				projects: []string{"a", "c"},
+			},
+			mg: profile(withProjects("a", "b")),
+			want: want{
+				o:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				key: "AXP1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{qualityProfileClient: sonar.NewQualityProfileClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, tc.mg.Status.AtProvider.Key); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want key, +got key:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := profile(withProjects("a"))
+	e := external{qualityProfileClient: sonar.NewQualityProfileClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/qualityprofiles/create", Params: url.Values{"organization": {"org"}, "language": {"java"}, "name": {"strict"}}},
+		{Path: "/api/qualityprofiles/add_project", Params: url.Values{"organization": {"org"}, "language": {"java"}, "qualityProfile": {"strict"}, "project": {"a"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("e.Create(...): -want requests, +got requests:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("AXP1", cr.Status.AtProvider.Key); diff != "" {
+		t.Errorf("e.Create(...): -want key, +got key:\n%s\n", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.QualityProfile
+		want   []request
+	}{
+		"Rename": {
+			reason: "A quality profile renamed outside of Crossplane should be renamed back by key.",
+			sonar:  &fakeSonar{profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "lenient", Language: "java"}}},
+			mg:     profile(withKey("AXP1")),
+			want: []request{
+				{Path: "/api/qualityprofiles/rename", Params: url.Values{"key": {"AXP1"}, "name": {"strict"}}},
+			},
+		},
+		"ProjectDrift": {
+			reason: "Missing projects should be associated and undesired ones removed.",
+			sonar: &fakeSonar{
+				profiles: []sonar.QualityProfile{{Key: "AXP1", Name: "strict", Language: "java"}},
+				projects: []string{"a", "c"},
+			},
+			mg: profile(withKey("AXP1"), withProjects("a", "b")),
+			want: []request{
+				{Path: "/api/qualityprofiles/remove_project", Params: url.Values{"organization": {"org"}, "language": {"java"}, "qualityProfile": {"strict"}, "project": {"c"}}},
+				{Path: "/api/qualityprofiles/add_project", Params: url.Values{"organization": {"org"}, "language": {"java"}, "qualityProfile": {"strict"}, "project": {"b"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{qualityProfileClient: sonar.NewQualityProfileClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.sonar.requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type profileModifier func(*v1alpha1.QualityProfile)
+
+func withKey(key string) profileModifier {
+	return func(cr *v1alpha1.QualityProfile) { cr.Status.AtProvider.Key = key }
+}
+
+func withProjects(projects ...string) profileModifier {
+	return func(cr *v1alpha1.QualityProfile) { cr.Spec.ForProvider.Projects = projects }
+}
+
+func profile(m ...profileModifier) *v1alpha1.QualityProfile {
+	cr := &v1alpha1.QualityProfile{}
+	cr.Spec.ForProvider = v1alpha1.QualityProfileParameters{
+		Organization: "org",
+		Language:     "java",
+		Name:         "strict",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package setting
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/setting/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotSetting   = "managed resource is not a Setting custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+
+	errGetSetting   = "cannot get setting"
+	errSetSetting   = "cannot set setting"
+	errResetSetting = "cannot reset setting"
+)
+
+// Setup adds a controller that reconciles Setting managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.SettingGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.SettingGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewSettingsClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Setting{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.SettingsClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Setting)
+	if !ok {
+		return nil, errors.New(errNotSetting)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{settingsClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	settingsClient sonar.SettingsClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Setting)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSetting)
+	}
+
+	setting, err := c.settingsClient.Get(ctx, cr.Spec.ForProvider.Project, cr.Spec.ForProvider.Key)
+	if errors.Is(err, sonar.ErrSettingNotFound) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetSetting)
+	}
+
+	// A setting that takes its default or inherited value is not set on
+	// its scope, and is set again by Create.
+	cr.Status.AtProvider.Inherited = setting.Inherited
+	if setting.Inherited {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: isUpToDate(cr.Spec.ForProvider, setting),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Setting)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSetting)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	err := c.settingsClient.Set(ctx, cr.Spec.ForProvider.Project, desiredSetting(cr.Spec.ForProvider))
+	return managed.ExternalCreation{}, errors.Wrap(err, errSetSetting)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Setting)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSetting)
+	}
+
+	err := c.settingsClient.Set(ctx, cr.Spec.ForProvider.Project, desiredSetting(cr.Spec.ForProvider))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errSetSetting)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Setting)
+	if !ok {
+		return errors.New(errNotSetting)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.settingsClient.Reset(ctx, cr.Spec.ForProvider.Project, cr.Spec.ForProvider.Key)
+	return errors.Wrap(err, errResetSetting)
+}
+
+// desiredSetting produces the setting set for the supplied parameters.
+func desiredSetting(p v1alpha1.SettingParameters) sonar.Setting {
+	return sonar.Setting{
+		Key:         p.Key,
+		Value:       p.Value,
+		Values:      p.Values,
+		FieldValues: p.FieldValues,
+	}
+}
+
+// isUpToDate returns true if the observed setting holds the desired value.
+// The order of multiple values is significant, as it is for the API.
+func isUpToDate(p v1alpha1.SettingParameters, setting sonar.Setting) bool {
+	if p.Value != setting.Value {
+		return false
+	}
+	if len(p.Values) > 0 || len(setting.Values) > 0 {
+		if !reflect.DeepEqual(p.Values, setting.Values) {
+			return false
+		}
+	}
+	if len(p.FieldValues) > 0 || len(setting.FieldValues) > 0 {
+		if !reflect.DeepEqual(p.FieldValues, setting.FieldValues) {
+			return false
+		}
+	}
+	return true
+}
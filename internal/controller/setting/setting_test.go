@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/setting/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		handler http.HandlerFunc
+		mg      resource.Managed
+	}
+
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "A setting that is not defined should be reported as not existing.",
+			args: args{
+				handler: valuesResponse(),
+				mg:      setting(withValue("target/jacoco.xml")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"Inherited": {
+			reason: "A setting that takes its default value should be reported as not existing.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", Value: "default", Inherited: true}),
+				mg:      setting(withValue("target/jacoco.xml")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ScalarUpToDate": {
+			reason: "A single value setting holding the desired value should be reported as up to date.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", Value: "target/jacoco.xml"}),
+				mg:      setting(withValue("target/jacoco.xml")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"ScalarDrift": {
+			reason: "A single value setting changed outside of Crossplane should be reported as not up to date.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", Value: "build/jacoco.xml"}),
+				mg:      setting(withValue("target/jacoco.xml")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"MultiValueUpToDate": {
+			reason: "A multi-value setting holding the desired values should be reported as up to date.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", Values: []string{"**/generated/**", "**/*.pb.go"}}),
+				mg:      setting(withValues("**/generated/**", "**/*.pb.go")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"MultiValueDrift": {
+			reason: "A multi-value setting missing a desired value should be reported as not up to date.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", Values: []string{"**/generated/**"}}),
+				mg:      setting(withValues("**/generated/**", "**/*.pb.go")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"FieldValuesUpToDate": {
+			reason: "A property set setting holding the desired field values should be reported as up to date.",
+			args: args{
+				handler: valuesResponse(sonar.Setting{Key: "sonar.exclusions", FieldValues: []map[string]string{{"ruleKey": "go:S100"}}}),
+				mg:      setting(withFieldValues(map[string]string{"ruleKey": "go:S100"})),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.args.handler)
+			defer srv.Close()
+
+			e := external{settingsClient: sonar.NewSettingsClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	type want struct {
+		path   string
+		params url.Values
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Setting
+		call   func(ctx context.Context, e *external, cr *v1alpha1.Setting) error
+		want   want
+	}{
+		"CreateScalar": {
+			reason: "Create should set the value of a single value setting on its project.",
+			mg:     setting(withValue("target/jacoco.xml")),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Setting) error {
+				_, err := e.Create(ctx, cr)
+				return err
+			},
+			want: want{
+				path:   "/api/settings/set",
+				params: url.Values{"key": {"sonar.exclusions"}, "component": {"key"}, "value": {"target/jacoco.xml"}},
+			},
+		},
+		"UpdateMultiValue": {
+			reason: "Update should set every value of a multi-value setting.",
+			mg:     setting(withValues("**/generated/**", "**/*.pb.go")),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Setting) error {
+				_, err := e.Update(ctx, cr)
+				return err
+			},
+			want: want{
+				path:   "/api/settings/set",
+				params: url.Values{"key": {"sonar.exclusions"}, "component": {"key"}, "values": {"**/generated/**", "**/*.pb.go"}},
+			},
+		},
+		"Delete": {
+			reason: "Delete should reset the setting to its default value.",
+			mg:     setting(withValue("target/jacoco.xml")),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Setting) error {
+				return e.Delete(ctx, cr)
+			},
+			want: want{
+				path:   "/api/settings/reset",
+				params: url.Values{"keys": {"sonar.exclusions"}, "component": {"key"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				path = r.URL.Path
+				params = r.Form
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			e := &external{settingsClient: sonar.NewSettingsClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if err := tc.call(context.Background(), e, tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want path, +got path:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want params, +got params:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+type settingModifier func(*v1alpha1.Setting)
+
+func withValue(value string) settingModifier {
+	return func(cr *v1alpha1.Setting) { cr.Spec.ForProvider.Value = value }
+}
+
+func withValues(values ...string) settingModifier {
+	return func(cr *v1alpha1.Setting) { cr.Spec.ForProvider.Values = values }
+}
+
+func withFieldValues(fieldValues ...map[string]string) settingModifier {
+	return func(cr *v1alpha1.Setting) { cr.Spec.ForProvider.FieldValues = fieldValues }
+}
+
+func setting(m ...settingModifier) *v1alpha1.Setting {
+	cr := &v1alpha1.Setting{}
+	cr.Spec.ForProvider = v1alpha1.SettingParameters{
+		Key:     "sonar.exclusions",
+		Project: "key",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// valuesResponse returns a handler that answers every request with the
+// supplied settings.
+func valuesResponse(settings ...sonar.Setting) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string][]sonar.Setting{"settings": settings})
+	}
+}
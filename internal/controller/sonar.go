@@ -19,21 +19,56 @@ package controller
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/almbinding"
+	"github.com/crossplane/provider-sonar/internal/controller/almsetting"
+	"github.com/crossplane/provider-sonar/internal/controller/application"
 	"github.com/crossplane/provider-sonar/internal/controller/config"
+	"github.com/crossplane/provider-sonar/internal/controller/organization"
+	"github.com/crossplane/provider-sonar/internal/controller/permissiontemplate"
+	"github.com/crossplane/provider-sonar/internal/controller/portfolio"
 	"github.com/crossplane/provider-sonar/internal/controller/project"
+	"github.com/crossplane/provider-sonar/internal/controller/qualitygate"
+	"github.com/crossplane/provider-sonar/internal/controller/qualityprofile"
+	"github.com/crossplane/provider-sonar/internal/controller/setting"
+	"github.com/crossplane/provider-sonar/internal/controller/user"
+	"github.com/crossplane/provider-sonar/internal/controller/usergroup"
+	"github.com/crossplane/provider-sonar/internal/controller/webhook"
 )
 
 // Setup creates all Sonar controllers with the supplied logger and adds them to
 // the supplied manager.
+// managedSetups add the controllers of the managed resources, which poll their
+// resources every controller.Options.PollInterval.
+var managedSetups = []func(ctrl.Manager, controller.Options) error{
+	almbinding.Setup,
+	almsetting.Setup,
+	application.Setup,
+	organization.Setup,
+	permissiontemplate.Setup,
+	portfolio.Setup,
+	project.Setup,
+	qualitygate.Setup,
+	qualityprofile.Setup,
+	setting.Setup,
+	user.Setup,
+	usergroup.Setup,
+	webhook.Setup,
+}
+
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
-		config.Setup,
-		project.Setup,
-	} {
+	if err := config.Setup(mgr, o); err != nil {
+		return err
+	}
+	for _, setup := range managedSetups {
 		if err := setup(mgr, o); err != nil {
 			return err
 		}
 	}
-	return nil
+
+	// The requests in flight are drained, and the connections kept alive to
+	// the instance closed, once the manager stops.
+	return mgr.Add(manager.RunnableFunc(sonar.CloseIdleConnectionsWhenDone))
 }
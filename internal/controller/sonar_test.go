@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+// TestSetupPollInterval checks that the Setup of every managed resource
+// controller passes the poll interval of its options both to the managed
+// reconciler, which requeues resources after it, and to the poll reconciler,
+// which jitters it. The reconcilers do not expose their interval, so the
+// source of the Setup functions is checked.
+func TestSetupPollInterval(t *testing.T) {
+	for _, setup := range managedSetups {
+		fn := runtime.FuncForPC(reflect.ValueOf(setup).Pointer())
+		file, _ := fn.FileLine(fn.Entry())
+
+		t.Run(fn.Name(), func(t *testing.T) {
+			f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+			if err != nil {
+				t.Fatalf("parser.ParseFile(%q): unexpected error: %v", file, err)
+			}
+
+			var decl *ast.FuncDecl
+			for _, d := range f.Decls {
+				if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "Setup" {
+					decl = fd
+				}
+			}
+			if decl == nil || len(decl.Type.Params.List) != 2 || len(decl.Type.Params.List[1].Names) != 1 {
+				t.Fatalf("%s: want a Setup(mgr, o) function", file)
+			}
+			o := decl.Type.Params.List[1].Names[0].Name
+
+			got := map[string]bool{"managed.WithPollInterval": false, "poll.NewReconciler": false}
+			ast.Inspect(decl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				pkg, ok := sel.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				name := pkg.Name + "." + sel.Sel.Name
+				if _, ok := got[name]; !ok {
+					return true
+				}
+				for _, arg := range call.Args {
+					if isPollInterval(arg, o) {
+						got[name] = true
+					}
+				}
+				return true
+			})
+
+			for name, passed := range got {
+				if !passed {
+					t.Errorf("%s: Setup should pass %s.PollInterval to %s", file, o, name)
+				}
+			}
+		})
+	}
+}
+
+// isPollInterval returns true if expr is the PollInterval field of the options
+// named o.
+func isPollInterval(expr ast.Expr, o string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "PollInterval" {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == o
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/user/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		response string
+		mg       *v1alpha1.User
+		want     want
+	}{
+		"NotFound": {
+			reason:   "A user whose login is not found should be reported as not existing.",
+			response: `{"users":[{"login":"jdoe2","name":"John Doe","active":true}]}`,
+			mg:       user(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"Deactivated": {
+			reason:   "A deactivated user should be reported as not existing.",
+			response: `{"users":[{"login":"jdoe","name":"John Doe","email":"jdoe@example.org","active":false}]}`,
+			mg:       user(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason:   "A user matching the desired state should be reported as up to date.",
+			response: `{"users":[{"login":"jdoe","name":"John Doe","email":"jdoe@example.org","active":true}]}`,
+			mg:       user(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"NameDrift": {
+			reason:   "A user whose name drifted should be reported as not up to date.",
+			response: `{"users":[{"login":"jdoe","name":"Johnny","email":"jdoe@example.org","active":true}]}`,
+			mg:       user(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"EmailDrift": {
+			reason:   "A user whose email drifted should be reported as not up to date.",
+			response: `{"users":[{"login":"jdoe","name":"John Doe","email":"john@example.org","active":true}]}`,
+			mg:       user(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer srv.Close()
+
+			e := external{userClient: sonar.NewUserClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	var password string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		password = r.FormValue("password")
+		_, _ = w.Write([]byte(`{"user":{"login":"jdoe","name":"John Doe","active":true,"local":true}}`))
+	}))
+	defer srv.Close()
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok && key.Namespace == "ns" && key.Name == "jdoe" {
+				s.Data = map[string][]byte{"password": []byte("s3cr3t")}
+			}
+			return nil
+		},
+	}
+
+	cr := user()
+	cr.Spec.ForProvider.PasswordSecretRef = &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Namespace: "ns", Name: "jdoe"},
+		Key:             "password",
+	}
+
+	e := external{kube: kube, userClient: sonar.NewUserClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("s3cr3t", password); diff != "" {
+		t.Errorf("e.Create(...): -want password, +got password:\n%s\n", diff)
+	}
+}
+
+func user() *v1alpha1.User {
+	cr := &v1alpha1.User{}
+	cr.Spec.ForProvider = v1alpha1.UserParameters{
+		Login: "jdoe",
+		Name:  "John Doe",
+		Email: "jdoe@example.org",
+	}
+	return cr
+}
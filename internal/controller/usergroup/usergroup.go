@@ -0,0 +1,263 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usergroup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/provider-sonar/apis/usergroup/v1alpha1"
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotUserGroup = "managed resource is not a UserGroup custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+
+	errGetUserGroup    = "cannot get user group"
+	errCreateUserGroup = "cannot create user group"
+	errUpdateUserGroup = "cannot update user group"
+	errDeleteUserGroup = "cannot delete user group"
+	errAddUser         = "cannot add user to user group"
+	errRemoveUser      = "cannot remove user from user group"
+)
+
+// Setup adds a controller that reconciles UserGroup managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.UserGroupGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.UserGroupGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewUserGroupClient}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.UserGroup{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.UserGroupClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.UserGroup)
+	if !ok {
+		return nil, errors.New(errNotUserGroup)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{userGroupClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	userGroupClient sonar.UserGroupClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.UserGroup)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotUserGroup)
+	}
+
+	p := cr.Spec.ForProvider
+
+	group, err := c.userGroupClient.GetByName(ctx, p.Name)
+	if err != nil {
+		if errors.Is(err, sonar.ErrGroupNotFound) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetUserGroup)
+	}
+
+	members, err := c.userGroupClient.Members(ctx, group.Name)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetUserGroup)
+	}
+
+	cr.Status.AtProvider = v1alpha1.UserGroupObservation{ID: string(group.Id), Members: members}
+	cr.SetConditions(xpv1.Available())
+
+	toAdd, toRemove := diffMembers(p.Members, members)
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: (p.Description == "" || p.Description == group.Description) && len(toAdd) == 0 && len(toRemove) == 0,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.UserGroup)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotUserGroup)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	p := cr.Spec.ForProvider
+	if _, err := c.userGroupClient.Create(ctx, p.Name, p.Description); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateUserGroup)
+	}
+
+	// Anything that fails here is retried by the next Update.
+	for _, login := range p.Members {
+		if err := c.userGroupClient.AddUser(ctx, p.Name, login); err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errAddUser)
+		}
+	}
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.UserGroup)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotUserGroup)
+	}
+
+	p := cr.Spec.ForProvider
+
+	group, err := c.userGroupClient.GetByName(ctx, p.Name)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateUserGroup)
+	}
+
+	if p.Description != "" && p.Description != group.Description {
+		if err := c.userGroupClient.Update(ctx, p.Name, p.Description); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateUserGroup)
+		}
+	}
+
+	members, err := c.userGroupClient.Members(ctx, p.Name)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateUserGroup)
+	}
+
+	toAdd, toRemove := diffMembers(p.Members, members)
+	for _, login := range toRemove {
+		if err := c.userGroupClient.RemoveUser(ctx, p.Name, login); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errRemoveUser)
+		}
+	}
+	for _, login := range toAdd {
+		if err := c.userGroupClient.AddUser(ctx, p.Name, login); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errAddUser)
+		}
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.UserGroup)
+	if !ok {
+		return errors.New(errNotUserGroup)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.userGroupClient.Delete(ctx, cr.Spec.ForProvider.Name)
+	return errors.Wrap(err, errDeleteUserGroup)
+}
+
+// diffMembers compares the desired members of a group with the observed ones.
+// Unlike the projects of a quality profile, membership is always managed, so
+// no desired members means every observed member is removed.
+func diffMembers(desired, observed []string) (toAdd, toRemove []string) {
+	existing := make(map[string]bool, len(observed))
+	for _, o := range observed {
+		existing[o] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		if wanted[d] {
+			continue
+		}
+		wanted[d] = true
+		if !existing[d] {
+			toAdd = append(toAdd, d)
+		}
+	}
+
+	for _, o := range observed {
+		if !wanted[o] {
+			toRemove = append(toRemove, o)
+		}
+	}
+
+	return toAdd, toRemove
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usergroup
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+
+	"github.com/crossplane/provider-sonar/apis/usergroup/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+type request struct {
+	Path   string
+	Params url.Values
+}
+
+// fakeSonar serves the supplied groups and members, and records every other
+// request it receives.
+type fakeSonar struct {
+	groups   []sonar.Group
+	members  []string
+	requests []request
+}
+
+func (f *fakeSonar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	switch r.URL.Path {
+	case "/api/user_groups/search":
+		_ = json.NewEncoder(w).Encode(map[string][]sonar.Group{"groups": f.groups})
+	case "/api/user_groups/users":
+		users := make([]map[string]string, 0, len(f.members))
+		for _, m := range f.members {
+			users = append(users, map[string]string{"login": m})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"total": len(users), "users": users})
+	case "/api/user_groups/create":
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+		_ = json.NewEncoder(w).Encode(map[string]sonar.Group{"group": {Id: "1", Name: r.Form.Get("name")}})
+	default:
+		f.requests = append(f.requests, request{Path: r.URL.Path, Params: r.Form})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o       managed.ExternalObservation
+		members []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.UserGroup
+		want   want
+	}{
+		"NotFound": {
+			reason: "A group whose name only matches partially should be reported as not existing.",
+			sonar:  &fakeSonar{groups: []sonar.Group{{Name: "developers-eu"}}},
+			mg:     group(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"UpToDate": {
+			reason: "A group with the desired members, in any order, should be reported as up to date.",
+			sonar: &fakeSonar{
+				groups:  []sonar.Group{{Name: "developers", Description: "Developers"}},
+				members: []string{"bob", "alice"},
+			},
+			mg: group(withMembers("alice", "bob")),
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				members: []string{"bob", "alice"},
+			},
+		},
+		"EmptyMembership": {
+			reason: "A group with members should not be up to date when no members are desired.",
+			sonar: &fakeSonar{
+				groups:  []sonar.Group{{Name: "developers", Description: "Developers"}},
+				members: []string{"alice"},
+			},
+			mg: group(),
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				members: []string{"alice"},
+			},
+		},
+		"DescriptionDrift": {
+			reason: "A group whose description drifted should be reported as not up to date.",
+			sonar:  &fakeSonar{groups: []sonar.Group{{Name: "developers", Description: "Other"}}},
+			mg:     group(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{userGroupClient: sonar.NewUserGroupClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.members, tc.mg.Status.AtProvider.Members); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want members, +got members:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreateDelete(t *testing.T) {
+	f := &fakeSonar{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+
+	cr := group(withMembers("alice"))
+	e := external{userGroupClient: sonar.NewUserGroupClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %v", err)
+	}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %v", err)
+	}
+
+	want := []request{
+		{Path: "/api/user_groups/create", Params: url.Values{"name": {"developers"}, "description": {"Developers"}}},
+		{Path: "/api/user_groups/add_user", Params: url.Values{"name": {"developers"}, "login": {"alice"}}},
+		{Path: "/api/user_groups/delete", Params: url.Values{"name": {"developers"}}},
+	}
+	if diff := cmp.Diff(want, f.requests); diff != "" {
+		t.Errorf("e.Create(...), e.Delete(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		sonar  *fakeSonar
+		mg     *v1alpha1.UserGroup
+		want   []request
+	}{
+		"Description": {
+			reason: "A description that drifted should be updated.",
+			sonar:  &fakeSonar{groups: []sonar.Group{{Name: "developers", Description: "Other"}}},
+			mg:     group(),
+			want: []request{
+				{Path: "/api/user_groups/update", Params: url.Values{"currentName": {"developers"}, "description": {"Developers"}}},
+			},
+		},
+		"MembershipDrift": {
+			reason: "Missing members should be added and undesired ones removed.",
+			sonar: &fakeSonar{
+				groups:  []sonar.Group{{Name: "developers", Description: "Developers"}},
+				members: []string{"alice", "carol"},
+			},
+			mg: group(withMembers("alice", "bob")),
+			want: []request{
+				{Path: "/api/user_groups/remove_user", Params: url.Values{"name": {"developers"}, "login": {"carol"}}},
+				{Path: "/api/user_groups/add_user", Params: url.Values{"name": {"developers"}, "login": {"bob"}}},
+			},
+		},
+		"EmptyMembership": {
+			reason: "Every member should be removed when no members are desired.",
+			sonar: &fakeSonar{
+				groups:  []sonar.Group{{Name: "developers", Description: "Developers"}},
+				members: []string{"alice", "bob"},
+			},
+			mg: group(),
+			want: []request{
+				{Path: "/api/user_groups/remove_user", Params: url.Values{"name": {"developers"}, "login": {"alice"}}},
+				{Path: "/api/user_groups/remove_user", Params: url.Values{"name": {"developers"}, "login": {"bob"}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.sonar)
+			defer srv.Close()
+
+			e := external{userGroupClient: sonar.NewUserGroupClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if _, err := e.Update(context.Background(), tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.sonar.requests); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want requests, +got requests:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type groupModifier func(*v1alpha1.UserGroup)
+
+func withMembers(members ...string) groupModifier {
+	return func(cr *v1alpha1.UserGroup) { cr.Spec.ForProvider.Members = members }
+}
+
+func group(m ...groupModifier) *v1alpha1.UserGroup {
+	cr := &v1alpha1.UserGroup{}
+	cr.Spec.ForProvider = v1alpha1.UserGroupParameters{
+		Name:        "developers",
+		Description: "Developers",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
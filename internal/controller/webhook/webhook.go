@@ -0,0 +1,304 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	apisv1alpha1 "github.com/crossplane/provider-sonar/apis/v1alpha1"
+	"github.com/crossplane/provider-sonar/apis/webhook/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+	"github.com/crossplane/provider-sonar/internal/controller/features"
+	"github.com/crossplane/provider-sonar/internal/controller/poll"
+	"github.com/crossplane/provider-sonar/internal/controller/publisher"
+)
+
+const (
+	errNotWebhook   = "managed resource is not a Webhook custom resource"
+	errTrackPCUsage = "cannot track ProviderConfig usage"
+	errGetPC        = "cannot get ProviderConfig"
+
+	errGetSecret      = "cannot get webhook secret"
+	errSecretKey      = "webhook secret %s/%s has no key %q"
+	errListWebhooks   = "cannot list webhooks"
+	errListDeliveries = "cannot list webhook deliveries"
+	errCreateWebhook  = "cannot create webhook"
+	errUpdateWebhook  = "cannot update webhook"
+	errDeleteWebhook  = "cannot delete webhook"
+)
+
+// Setup adds a controller that reconciles Webhook managed resources.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(v1alpha1.WebhookGroupKind)
+	logger := o.Logger.WithValues("controller", name)
+
+	cps := []managed.ConnectionPublisher{publisher.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme(), publisher.FieldManager)}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.WebhookGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			logger:      logger,
+			newClientFn: sonar.NewWebhookClient}),
+		// The external name is the key generated by SonarCloud, so it must
+		// not default to the resource name.
+		managed.WithInitializers(),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithLogger(logger),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithConnectionPublishers(cps...))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.Webhook{}).
+		Complete(ratelimiter.NewReconciler(name, poll.NewReconciler(r, o.PollInterval), o.GlobalRateLimiter))
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	logger      logging.Logger
+	newClientFn func(options sonar.SonarApiOptions) sonar.WebhookClient
+}
+
+// Connect produces an ExternalClient by:
+// 1. Tracking that the managed resource is using a ProviderConfig.
+// 2. Getting the managed resource's ProviderConfig.
+// 3. Getting the credentials specified by the ProviderConfig.
+// 4. Using the credentials to form a client.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1alpha1.Webhook)
+	if !ok {
+		return nil, errors.New(errNotWebhook)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetPC)
+	}
+
+	options, err := sonar.OptionsFromProviderConfig(ctx, c.kube, pc, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	svc := c.newClientFn(options)
+
+	return &external{kube: c.kube, webhookClient: svc}, nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes an
+// external resource to ensure it reflects the managed resource's desired state.
+type external struct {
+	kube          client.Client
+	webhookClient sonar.WebhookClient
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Webhook)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotWebhook)
+	}
+
+	webhooks, err := c.webhookClient.List(ctx, cr.Spec.ForProvider.Organization, cr.Spec.ForProvider.Project)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListWebhooks)
+	}
+
+	webhook, ok := findWebhook(webhooks, meta.GetExternalName(cr), cr.Spec.ForProvider)
+	if !ok {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A webhook found by its name and URL is adopted, recording its key as
+	// the external name.
+	li := meta.GetExternalName(cr) != webhook.Key
+	meta.SetExternalName(cr, webhook.Key)
+
+	cr.Status.AtProvider.Key = webhook.Key
+	cr.Status.AtProvider.HasSecret = webhook.HasSecret
+	if err := c.observeLastDelivery(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        isUpToDate(cr.Spec.ForProvider, webhook),
+		ResourceLateInitialized: li,
+	}, nil
+}
+
+// observeLastDelivery reports the outcome of the latest notification of a
+// webhook, which helps to tell why a CI integration does not react to analyses.
+func (c *external) observeLastDelivery(ctx context.Context, cr *v1alpha1.Webhook) error {
+	deliveries, err := c.webhookClient.ListDeliveries(ctx, cr.Status.AtProvider.Key)
+	if err != nil {
+		return errors.Wrap(err, errListDeliveries)
+	}
+
+	cr.Status.AtProvider.LastDeliveryAt = nil
+	cr.Status.AtProvider.LastDeliveryHTTPStatus = nil
+	cr.Status.AtProvider.LastDeliverySuccess = nil
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	last := deliveries[0]
+	if !last.At.IsZero() {
+		cr.Status.AtProvider.LastDeliveryAt = &metav1.Time{Time: last.At.Time}
+	}
+	// A delivery that got no response, for example because the URL could not
+	// be reached, has no HTTP status.
+	if last.HttpStatus != 0 {
+		cr.Status.AtProvider.LastDeliveryHTTPStatus = &last.HttpStatus
+	}
+	cr.Status.AtProvider.LastDeliverySuccess = &last.Success
+	return nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Webhook)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotWebhook)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	options, err := c.webhookOptions(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	webhook, err := c.webhookClient.Create(ctx, options)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, errCreateWebhook)
+	}
+	// The key is recorded as the external name, as the status set here is
+	// not persisted.
+	meta.SetExternalName(cr, webhook.Key)
+
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Webhook)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotWebhook)
+	}
+
+	options, err := c.webhookOptions(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	err = c.webhookClient.Update(ctx, meta.GetExternalName(cr), options)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateWebhook)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Webhook)
+	if !ok {
+		return errors.New(errNotWebhook)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	err := c.webhookClient.Delete(ctx, meta.GetExternalName(cr))
+	return errors.Wrap(err, errDeleteWebhook)
+}
+
+// webhookOptions produces the options used to create or update a webhook,
+// reading its secret from the referenced Kubernetes secret if any.
+func (c *external) webhookOptions(ctx context.Context, p v1alpha1.WebhookParameters) (sonar.WebhookOptions, error) {
+	options := sonar.WebhookOptions{
+		Organization: p.Organization,
+		Project:      p.Project,
+		Name:         p.Name,
+		Url:          p.URL,
+	}
+
+	if ref := p.SecretSecretRef; ref != nil {
+		s := &corev1.Secret{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return sonar.WebhookOptions{}, errors.Wrap(err, errGetSecret)
+		}
+		// A missing key is an error rather than an empty secret, which
+		// would remove the secret of the webhook.
+		secret, ok := s.Data[ref.Key]
+		if !ok {
+			return sonar.WebhookOptions{}, errors.Errorf(errSecretKey, ref.Namespace, ref.Name, ref.Key)
+		}
+		options.Secret = string(secret)
+	}
+
+	return options, nil
+}
+
+// findWebhook returns the webhook with the supplied key. Failing that, it
+// returns the webhook with the desired name and URL, which finds a webhook
+// whose key was never recorded.
+func findWebhook(webhooks []sonar.Webhook, key string, p v1alpha1.WebhookParameters) (sonar.Webhook, bool) {
+	if key != "" {
+		for _, webhook := range webhooks {
+			if webhook.Key == key {
+				return webhook, true
+			}
+		}
+	}
+	for _, webhook := range webhooks {
+		if webhook.Name == p.Name && webhook.Url == p.URL {
+			return webhook, true
+		}
+	}
+	return sonar.Webhook{}, false
+}
+
+// isUpToDate returns true if the observed webhook matches the desired one. The
+// API never returns the secret itself, so only whether one is set is compared.
+func isUpToDate(p v1alpha1.WebhookParameters, webhook sonar.Webhook) bool {
+	return p.Name == webhook.Name &&
+		p.URL == webhook.Url &&
+		(p.SecretSecretRef != nil) == webhook.HasSecret
+}
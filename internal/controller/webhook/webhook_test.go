@@ -0,0 +1,338 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/provider-sonar/apis/webhook/v1alpha1"
+	"github.com/crossplane/provider-sonar/internal/clients/sonar"
+)
+
+// Unlike many Kubernetes projects Crossplane does not use third party testing
+// libraries, per the common Go test review comments. Crossplane encourages the
+// use of table driven unit tests. The tests of the crossplane-runtime project
+// are representative of the testing style Crossplane encourages.
+//
+// https://github.com/golang/go/wiki/TestComments
+// https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		handler http.HandlerFunc
+		mg      resource.Managed
+	}
+
+	type want struct {
+		o   managed.ExternalObservation
+		err error
+	}
+
+	observed := listResponse(sonar.Webhook{Key: "AXW1", Name: "ci", Url: "https://ci.example.com/hook"})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A webhook without an external name and no webhook with its name and URL has not been created yet.",
+			args: args{
+				handler: listResponse(sonar.Webhook{Key: "AXW1", Name: "ci", Url: "https://elsewhere.example.com/hook"}),
+				mg:      webhook(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A webhook whose key is not listed should be reported as not existing.",
+			args: args{
+				handler: listResponse(sonar.Webhook{Key: "AXW1", Name: "other", Url: "https://ci.example.com/hook"}),
+				mg:      webhook(withKey("AXW2")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"Adopted": {
+			reason: "A webhook without an external name should adopt the webhook with its name and URL, recording its key.",
+			args: args{
+				handler: observed,
+				mg:      webhook(),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"UpToDate": {
+			reason: "A webhook found by the key in its external name and matching the desired state should be reported as up to date.",
+			args: args{
+				handler: observed,
+				mg:      webhook(withKey("AXW1")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"URLDrift": {
+			reason: "A webhook whose URL was changed outside of Crossplane should be reported as not up to date.",
+			args: args{
+				handler: listResponse(sonar.Webhook{Key: "AXW1", Name: "ci", Url: "https://elsewhere.example.com/hook"}),
+				mg:      webhook(withKey("AXW1")),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"SecretDrift": {
+			reason: "A webhook that should be signed but has no secret should be reported as not up to date.",
+			args: args{
+				handler: observed,
+				mg:      webhook(withKey("AXW1"), withSecretRef()),
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.args.handler)
+			defer srv.Close()
+
+			e := external{webhookClient: sonar.NewWebhookClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			got, err := e.Observe(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveLastDelivery(t *testing.T) {
+	list := listResponse(sonar.Webhook{Key: "AXW1", Name: "ci", Url: "https://ci.example.com/hook"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/webhooks/deliveries" {
+			list(w, r)
+			return
+		}
+		if got := r.FormValue("webhook"); got != "AXW1" {
+			t.Errorf("e.Observe(...): want deliveries of webhook AXW1, got %q", got)
+		}
+		_, _ = w.Write([]byte(`{"deliveries":[` +
+			`{"id":"d2","at":"2022-11-10T19:33:53+0100","success":false,"httpStatus":502},` +
+			`{"id":"d1","at":"2022-11-09T10:00:00+0100","success":true,"httpStatus":200}]}`))
+	}))
+	defer srv.Close()
+
+	e := external{webhookClient: sonar.NewWebhookClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+	mg := webhook(withKey("AXW1"))
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %v", err)
+	}
+
+	status, success := 502, false
+	want := v1alpha1.WebhookObservation{
+		Key:                    "AXW1",
+		LastDeliveryAt:         &metav1.Time{Time: time.Date(2022, 11, 10, 18, 33, 53, 0, time.UTC)},
+		LastDeliveryHTTPStatus: &status,
+		LastDeliverySuccess:    &success,
+	}
+	if diff := cmp.Diff(want, mg.Status.AtProvider, cmp.Comparer(func(a, b metav1.Time) bool { return a.Equal(&b) })); diff != "" {
+		t.Errorf("e.Observe(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCreateUpdateDelete(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok && key.Namespace == "ns" && key.Name == "webhook" {
+				s.Data = map[string][]byte{"secret": []byte("s3cr3t")}
+			}
+			return nil
+		},
+	}
+
+	type want struct {
+		path   string
+		params url.Values
+		key    string
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *v1alpha1.Webhook
+		call   func(ctx context.Context, e *external, cr *v1alpha1.Webhook) error
+		want   want
+	}{
+		"Create": {
+			reason: "Create should send the secret read from the referenced Kubernetes secret and record the returned key as the external name.",
+			mg:     webhook(withSecretRef()),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Webhook) error {
+				_, err := e.Create(ctx, cr)
+				return err
+			},
+			want: want{
+				path:   "/api/webhooks/create",
+				params: url.Values{"organization": {"org"}, "project": {"key"}, "name": {"ci"}, "url": {"https://ci.example.com/hook"}, "secret": {"s3cr3t"}},
+				key:    "AXW1",
+			},
+		},
+		"Update": {
+			reason: "Update should send the desired state for the webhook key recorded in the external name.",
+			mg:     webhook(withKey("AXW1"), withSecretRef()),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Webhook) error {
+				_, err := e.Update(ctx, cr)
+				return err
+			},
+			want: want{
+				path:   "/api/webhooks/update",
+				params: url.Values{"webhook": {"AXW1"}, "name": {"ci"}, "url": {"https://ci.example.com/hook"}, "secret": {"s3cr3t"}},
+				key:    "AXW1",
+			},
+		},
+		"UpdateSecretRemoved": {
+			reason: "Update should send an empty secret to remove the secret of a webhook that no longer references one.",
+			mg:     webhook(withKey("AXW1")),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Webhook) error {
+				_, err := e.Update(ctx, cr)
+				return err
+			},
+			want: want{
+				path:   "/api/webhooks/update",
+				params: url.Values{"webhook": {"AXW1"}, "name": {"ci"}, "url": {"https://ci.example.com/hook"}, "secret": {""}},
+				key:    "AXW1",
+			},
+		},
+		"Delete": {
+			reason: "Delete should delete the webhook key recorded in the external name.",
+			mg:     webhook(withKey("AXW1")),
+			call: func(ctx context.Context, e *external, cr *v1alpha1.Webhook) error {
+				return e.Delete(ctx, cr)
+			},
+			want: want{
+				path:   "/api/webhooks/delete",
+				params: url.Values{"webhook": {"AXW1"}},
+				key:    "AXW1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			var params url.Values
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = r.ParseForm()
+				path = r.URL.Path
+				params = r.Form
+				_, _ = w.Write([]byte(`{"webhook":{"key":"AXW1","name":"ci","url":"https://ci.example.com/hook"}}`))
+			}))
+			defer srv.Close()
+
+			e := &external{kube: kube, webhookClient: sonar.NewWebhookClient(sonar.SonarApiOptions{BaseUrl: srv.URL})}
+			if err := tc.call(context.Background(), e, tc.mg); err != nil {
+				t.Fatalf("\n%s\ne.%s(...): unexpected error: %v", tc.reason, name, err)
+			}
+			if diff := cmp.Diff(tc.want.path, path); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want path, +got path:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, params); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want params, +got params:\n%s\n", tc.reason, name, diff)
+			}
+			if diff := cmp.Diff(tc.want.key, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("\n%s\ne.%s(...): -want key, +got key:\n%s\n", tc.reason, name, diff)
+			}
+		})
+	}
+}
+
+func TestWebhookOptionsMissingKey(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*corev1.Secret); ok {
+				s.Data = map[string][]byte{"other": []byte("s3cr3t")}
+			}
+			return nil
+		},
+	}
+
+	e := &external{kube: kube}
+	cr := webhook(withSecretRef())
+	if _, err := e.webhookOptions(context.Background(), cr.Spec.ForProvider); err == nil {
+		t.Errorf("e.webhookOptions(...): want an error for a referenced secret without the key, rather than an empty secret that removes the secret of the webhook")
+	}
+}
+
+type webhookModifier func(*v1alpha1.Webhook)
+
+func withKey(key string) webhookModifier {
+	return func(cr *v1alpha1.Webhook) { meta.SetExternalName(cr, key) }
+}
+
+func withSecretRef() webhookModifier {
+	return func(cr *v1alpha1.Webhook) {
+		cr.Spec.ForProvider.SecretSecretRef = &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "ns", Name: "webhook"},
+			Key:             "secret",
+		}
+	}
+}
+
+func webhook(m ...webhookModifier) *v1alpha1.Webhook {
+	cr := &v1alpha1.Webhook{}
+	cr.Spec.ForProvider = v1alpha1.WebhookParameters{
+		Organization: "org",
+		Project:      "key",
+		Name:         "ci",
+		URL:          "https://ci.example.com/hook",
+	}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// listResponse returns a handler that answers every request with the supplied
+// webhooks.
+func listResponse(webhooks ...sonar.Webhook) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string][]sonar.Webhook{"webhooks": webhooks})
+	}
+}
@@ -26,7 +26,7 @@ func main() {
 
 	// fmt.Println((projectClient.GetByProjectKey("chicoribas", "chicoribas_scafflater")))
 
-	fmt.Println((projectClient.Create(context.Background(), "chicoribas", "test_provider_name", "test_provider_key", "public")))
+	fmt.Println((projectClient.Create(context.Background(), "chicoribas", "test_provider_name", "test_provider_key", "public", "")))
 	fmt.Println((projectClient.Delete(context.Background(), "test_provider_key")))
 	// fmt.Println((projectClient.UpdateVisibility("test_provider_key", "private")))
 